@@ -76,7 +76,7 @@ func main() {
 		fmt.Printf("\nItem %d:\n", i+1)
 		fmt.Printf("  SKU: %s\n", item.SKU)
 		fmt.Printf("  Title: %s\n", item.Title)
-		fmt.Printf("  Price: %.2f\n", item.Price)
+		fmt.Printf("  Price: %s\n", item.Price)
 		fmt.Printf("  Quantity: %d\n", item.Quantity)
 		fmt.Printf("  Is Supplier Item: %v\n", item.IsSupplierItem)
 		if item.ShopifyVariantID != nil {
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	nameFlag := flag.String("name", "", "Staff member display name")
+	emailFlag := flag.String("email", "", "Staff member email (optional)")
+	apiKeyFlag := flag.String("api-key", "", "API key for this staff member (save it; it cannot be retrieved later)")
+	flag.Parse()
+
+	name := strings.TrimSpace(*nameFlag)
+	email := strings.TrimSpace(*emailFlag)
+	// Trim so the stored hash matches what the server receives (StaffAuthMiddleware trims the Bearer token)
+	apiKey := strings.TrimSpace(*apiKeyFlag)
+	if name == "" || apiKey == "" {
+		fmt.Println("Usage:")
+		fmt.Println("  go run cmd/create-supplier-staff/main.go --name \"Warehouse Team\" --api-key \"your-staff-api-key\" [--email ops@example.com]")
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// Hash the API key (bcrypt for verification; SHA256 hex for fast lookup)
+	apiKeyHash, err := bcrypt.GenerateFromPassword([]byte(apiKey), 10)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to hash API key: %v\n", err)
+		os.Exit(1)
+	}
+	h := sha256.Sum256([]byte(apiKey))
+
+	repos := postgres.NewRepositories(db, logger)
+
+	staff := &domain.SupplierStaff{
+		Name:         name,
+		APIKeyHash:   string(apiKeyHash),
+		APIKeyLookup: hex.EncodeToString(h[:]),
+		IsActive:     true,
+	}
+	if email != "" {
+		staff.Email = &email
+	}
+
+	if err := repos.SupplierStaff.Create(context.Background(), staff); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create supplier staff: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Supplier staff created successfully!\n\n")
+	fmt.Printf("Staff ID: %s\n", staff.ID.String())
+	fmt.Printf("Name: %s\n", staff.Name)
+	fmt.Printf("API Key: %s\n", apiKey)
+	fmt.Printf("\n⚠️  IMPORTANT: Save this API key securely! You won't be able to see it again.\n")
+	fmt.Printf("\nUse it for /v1/supplier routes (confirm, reject, ship across all partners):\n")
+	fmt.Printf("Authorization: Bearer %s\n", apiKey)
+}
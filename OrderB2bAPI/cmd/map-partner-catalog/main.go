@@ -356,9 +356,11 @@ func main() {
 				ShopifyProductID: product.ProductID,
 				ShopifyVariantID: product.VariantID,
 				Title:            &title,
-				Price:            &product.Price,
 				IsActive:         true,
 			}
+			if price, err := domain.ParseMoney(product.Price, ""); err == nil {
+				m.Price = &price
+			}
 			mappings = append(mappings, m)
 		}
 		if err := repos.PartnerSKUMapping.UpsertBatch(ctx, *partnerUUID, mappings); err != nil {
@@ -52,6 +52,11 @@ func main() {
 	// Initialize repositories
 	repos := postgres.NewRepositories(db, logger)
 
+	// Refuse to start on a database still holding legacy/unknown order statuses
+	if err := service.VerifyOrderStatuses(context.Background(), repos); err != nil {
+		logger.Fatal("Order status check failed", zap.Error(err))
+	}
+
 	// Initialize router
 	router := api.NewRouter(cfg, repos, logger)
 
@@ -76,6 +81,12 @@ func main() {
 	go service.RunCatalogSyncLoop(syncCtx, cfg, repos, logger)
 	logger.Info("Catalog sync job started (runs on startup and every 10 minutes)")
 
+	// SLA monitor: records orders stuck in a status past their threshold and alerts staff
+	if cfg.SLA.CheckInterval > 0 {
+		go service.RunSLAMonitorLoop(syncCtx, cfg, repos, logger)
+		logger.Info("SLA monitor started", zap.Duration("interval", cfg.SLA.CheckInterval))
+	}
+
 	logger.Info("Server started successfully", zap.String("address", srv.Addr))
 
 	// Wait for interrupt signal to gracefully shutdown the server
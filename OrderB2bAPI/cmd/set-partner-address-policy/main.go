@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	partnerIDFlag := flag.String("partner-id", "", "Partner UUID (from list-partners)")
+	policyFlag := flag.String("policy", "", "Address policy: lenient or strict")
+	flag.Parse()
+
+	partnerIDStr := strings.TrimSpace(*partnerIDFlag)
+	policy := domain.AddressPolicy(strings.ToLower(strings.TrimSpace(*policyFlag)))
+
+	if partnerIDStr == "" || !policy.IsValid() {
+		fmt.Fprintf(os.Stderr, "Error: --partner-id and --policy (lenient or strict) are required.\n")
+		fmt.Fprintf(os.Stderr, "Usage: go run cmd/set-partner-address-policy/main.go --partner-id <uuid> --policy <lenient|strict>\n")
+		fmt.Fprintf(os.Stderr, "  lenient: store cities not in the Jordan gazetteer as sent, without codes (default)\n")
+		fmt.Fprintf(os.Stderr, "  strict:  carts with a city not in the gazetteer get 422\n")
+		os.Exit(1)
+	}
+
+	partnerID, err := uuid.Parse(partnerIDStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid partner-id UUID: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repos := postgres.NewRepositories(db, logger)
+
+	partner, err := repos.Partner.GetByID(context.Background(), partnerID)
+	if err != nil || partner == nil {
+		fmt.Fprintf(os.Stderr, "Partner not found: %v\n", err)
+		os.Exit(1)
+	}
+
+	partner.AddressPolicy = policy
+	if err := repos.Partner.Update(context.Background(), partner); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update partner: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Partner %s address_policy set to: %s\n", partner.Name, policy)
+}
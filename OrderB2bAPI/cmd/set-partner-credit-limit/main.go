@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	partnerIDFlag := flag.String("partner-id", "", "Partner UUID (from list-partners)")
+	limitFlag := flag.String("limit", "", "Credit limit (e.g. 5000), or none to remove the limit")
+	policyFlag := flag.String("policy", "", "Credit policy: reject or hold (optional; unchanged when empty)")
+	flag.Parse()
+
+	partnerIDStr := strings.TrimSpace(*partnerIDFlag)
+	limitStr := strings.ToLower(strings.TrimSpace(*limitFlag))
+	policy := domain.CreditPolicy(strings.ToLower(strings.TrimSpace(*policyFlag)))
+
+	var limit *domain.Money
+	validLimit := limitStr == "none"
+	if n, err := domain.ParseMoney(limitStr, ""); err == nil && !n.IsNegative() {
+		limit = &n
+		validLimit = true
+	}
+
+	if partnerIDStr == "" || !validLimit || (policy != "" && !policy.IsValid()) {
+		fmt.Fprintf(os.Stderr, "Error: --partner-id and --limit (an amount, or none) are required; --policy must be reject or hold.\n")
+		fmt.Fprintf(os.Stderr, "Usage: go run cmd/set-partner-credit-limit/main.go --partner-id <uuid> --limit <amount|none> [--policy <reject|hold>]\n")
+		fmt.Fprintf(os.Stderr, "  reject: carts that would exceed the limit get 402 credit_limit_exceeded (default)\n")
+		fmt.Fprintf(os.Stderr, "  hold:   create the order but hold it in INCOMPLETE_CAUTION\n")
+		os.Exit(1)
+	}
+
+	partnerID, err := uuid.Parse(partnerIDStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid partner-id UUID: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repos := postgres.NewRepositories(db, logger)
+
+	partner, err := repos.Partner.GetByID(context.Background(), partnerID)
+	if err != nil || partner == nil {
+		fmt.Fprintf(os.Stderr, "Partner not found: %v\n", err)
+		os.Exit(1)
+	}
+
+	partner.CreditLimit = limit
+	if policy != "" {
+		partner.CreditPolicy = policy
+	}
+	if err := repos.Partner.Update(context.Background(), partner); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update partner: %v\n", err)
+		os.Exit(1)
+	}
+
+	if limit == nil {
+		fmt.Printf("Partner %s credit limit removed (credit_policy: %s)\n", partner.Name, partner.CreditPolicy)
+		return
+	}
+	fmt.Printf("Partner %s credit_limit set to: %s (credit_policy: %s)\n", partner.Name, limit.In(partner.Currency).Display(), partner.CreditPolicy)
+}
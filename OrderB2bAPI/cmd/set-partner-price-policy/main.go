@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	partnerIDFlag := flag.String("partner-id", "", "Partner UUID (from list-partners)")
+	policyFlag := flag.String("policy", "", "Price policy: reject, flag or override")
+	flag.Parse()
+
+	partnerIDStr := strings.TrimSpace(*partnerIDFlag)
+	policy := domain.PricePolicy(strings.ToLower(strings.TrimSpace(*policyFlag)))
+
+	if partnerIDStr == "" || !policy.IsValid() {
+		fmt.Fprintf(os.Stderr, "Error: --partner-id and --policy (reject, flag or override) are required.\n")
+		fmt.Fprintf(os.Stderr, "Usage: go run cmd/set-partner-price-policy/main.go --partner-id <uuid> --policy <reject|flag|override>\n")
+		fmt.Fprintf(os.Stderr, "  reject:   carts whose prices or totals differ from ours get 422\n")
+		fmt.Fprintf(os.Stderr, "  flag:     keep the partner's prices, record the discrepancy (default)\n")
+		fmt.Fprintf(os.Stderr, "  override: replace the partner's prices and totals with ours\n")
+		os.Exit(1)
+	}
+
+	partnerID, err := uuid.Parse(partnerIDStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid partner-id UUID: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repos := postgres.NewRepositories(db, logger)
+
+	partner, err := repos.Partner.GetByID(context.Background(), partnerID)
+	if err != nil || partner == nil {
+		fmt.Fprintf(os.Stderr, "Partner not found: %v\n", err)
+		os.Exit(1)
+	}
+
+	partner.PricePolicy = policy
+	if err := repos.Partner.Update(context.Background(), partner); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update partner: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Partner %s price_policy set to: %s\n", partner.Name, policy)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	partnerIDFlag := flag.String("partner-id", "", "Partner UUID (from list-partners)")
+	statusFlag := flag.String("status", "", "Order status (e.g. UNFULFILLED) or Wassel delivery status code (e.g. 130)")
+	thresholdFlag := flag.String("threshold", "", "Maximum time in the status (e.g. 90m, 48h, 3d), off, or default")
+	flag.Parse()
+
+	partnerIDStr := strings.TrimSpace(*partnerIDFlag)
+	statusStr := strings.ToUpper(strings.TrimSpace(*statusFlag))
+	thresholdStr := strings.ToLower(strings.TrimSpace(*thresholdFlag))
+
+	threshold := &domain.PartnerSLAThreshold{}
+	validStatus := false
+	if code, err := strconv.Atoi(statusStr); err == nil && code > 0 {
+		threshold.DeliveryStatus = &code
+		validStatus = true
+	} else if status := domain.OrderStatus(statusStr); status.IsValid() {
+		threshold.OrderStatus = &status
+		validStatus = true
+	}
+
+	validThreshold := thresholdStr == "off" || thresholdStr == "default"
+	if d, err := config.ParseSLADuration(thresholdStr); err == nil && d > 0 {
+		threshold.MaxMinutes = int(d / time.Minute)
+		validThreshold = true
+	}
+
+	if partnerIDStr == "" || !validStatus || !validThreshold {
+		fmt.Fprintf(os.Stderr, "Error: --partner-id, --status (an order status or Wassel status code) and --threshold are required.\n")
+		fmt.Fprintf(os.Stderr, "Usage: go run cmd/set-partner-sla-threshold/main.go --partner-id <uuid> --status <STATUS|code> --threshold <duration|off|default>\n")
+		fmt.Fprintf(os.Stderr, "  duration: how long an order may stay in the status (e.g. 90m, 48h, 3d)\n")
+		fmt.Fprintf(os.Stderr, "  off:      never report the partner's orders for this status\n")
+		fmt.Fprintf(os.Stderr, "  default:  use the SLA_THRESHOLDS default again\n")
+		os.Exit(1)
+	}
+
+	partnerID, err := uuid.Parse(partnerIDStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid partner-id UUID: %v\n", err)
+		os.Exit(1)
+	}
+	threshold.PartnerID = partnerID
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repos := postgres.NewRepositories(db, logger)
+
+	partner, err := repos.Partner.GetByID(context.Background(), partnerID)
+	if err != nil || partner == nil {
+		fmt.Fprintf(os.Stderr, "Partner not found: %v\n", err)
+		os.Exit(1)
+	}
+
+	if thresholdStr == "default" {
+		if err := repos.PartnerSLAThreshold.Delete(context.Background(), partnerID, threshold.OrderStatus, threshold.DeliveryStatus); err != nil {
+			fmt.Fprintf(os.Stderr, "No SLA threshold to remove: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Partner %s SLA threshold for %s reset to the default\n", partner.Name, statusStr)
+		return
+	}
+	if err := repos.PartnerSLAThreshold.Upsert(context.Background(), threshold); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set SLA threshold: %v\n", err)
+		os.Exit(1)
+	}
+
+	if threshold.MaxMinutes == 0 {
+		fmt.Printf("Partner %s SLA check for %s turned off\n", partner.Name, statusStr)
+		return
+	}
+	fmt.Printf("Partner %s SLA threshold for %s set to %d minutes\n", partner.Name, statusStr, threshold.MaxMinutes)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -27,11 +28,11 @@ type CartSubmitRequest struct {
 }
 
 type CartItem struct {
-	SKU        string  `json:"sku" binding:"required"`
-	Title      string  `json:"title" binding:"required"`
-	Price      float64 `json:"price" binding:"required,min=0"`
-	Quantity   int     `json:"quantity" binding:"required,min=1"`
-	ProductURL *string `json:"product_url,omitempty"`
+	SKU        string       `json:"sku" binding:"required"`
+	Title      string       `json:"title" binding:"required"`
+	Price      domain.Money `json:"price" binding:"required,min=0"`
+	Quantity   int          `json:"quantity" binding:"required,min=1"`
+	ProductURL *string      `json:"product_url,omitempty"`
 }
 
 // CustomerInfo matches Zain format: first_name, last_name, email, phone_number.
@@ -52,10 +53,10 @@ type ShippingAddress struct {
 }
 
 type CartTotals struct {
-	Subtotal float64 `json:"subtotal" binding:"required,min=0"`
-	Tax      float64 `json:"tax" binding:"min=0"`
-	Shipping float64 `json:"shipping" binding:"min=0"`
-	Total    float64 `json:"total" binding:"required,min=0"`
+	Subtotal domain.Money `json:"subtotal" binding:"required,min=0"`
+	Tax      domain.Money `json:"tax" binding:"min=0"`
+	Shipping domain.Money `json:"shipping" binding:"min=0"`
+	Total    domain.Money `json:"total" binding:"required,min=0"`
 }
 
 // CartSubmitResponse represents the response
@@ -66,6 +67,18 @@ type CartSubmitResponse struct {
 	ShopifyDraftOrderID *int64             `json:"shopify_draft_order_id,omitempty"`
 	ShopifyOrderID      *string            `json:"shopify_order_id,omitempty"`
 	ShopifyError        string             `json:"shopify_error,omitempty"`
+	// Set when the cart's prices or totals disagreed with ours (see the partner's price_policy)
+	PricePolicy        domain.PricePolicy         `json:"price_policy,omitempty"`
+	PriceDiscrepancies []service.PriceDiscrepancy `json:"price_discrepancies,omitempty"`
+	PricesOverridden   bool                       `json:"prices_overridden,omitempty"`
+	// Stock per item when the partner's stock_policy is not off; hold_reason is set when the order was held
+	// (insufficient stock, credit limit exceeded or possible duplicate)
+	Availability []service.ItemAvailability `json:"availability,omitempty"`
+	HoldReason   string                     `json:"hold_reason,omitempty"`
+	// Earlier order with the same customer phone and items; this one is held and not sent to Shopify until confirmed
+	PossibleDuplicateOf *uuid.UUID `json:"possible_duplicate_of,omitempty"`
+	// Canonical city and area stored on the order, with their gazetteer codes when found
+	Shipping *service.NormalizedAddress `json:"shipping,omitempty"`
 }
 
 func HandleCartSubmit(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
@@ -110,137 +123,329 @@ func HandleCartSubmit(cfg *config.Config, repos *repository.Repositories, logger
 			return
 		}
 
-		// Check for partner catalog SKUs (partner-scoped; only this partner's SKUs accepted)
-		logger.Info("Checking cart for partner catalog SKUs", zap.Int("item_count", len(req.Items)), zap.String("partner_id", partner.ID.String()))
-		skuService := service.NewSKUService(repos, logger)
-		hasPartnerSKU, partnerItems, err := skuService.CheckCartForPartnerSKUs(
-			c.Request.Context(),
-			partner.ID,
-			req.Items,
-		)
+		result, err := submitCart(c.Request.Context(), cfg, repos, logger, partner, req)
 		if err != nil {
-			logger.Error("Failed to check partner SKUs", zap.Error(err))
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": e.Fields,
+				})
+				return
+			}
+			if e, ok := err.(*errors.ErrCreditLimitExceeded); ok {
+				c.JSON(http.StatusPaymentRequired, creditLimitExceededBody(e))
+				return
+			}
+			if createErr, ok := err.(*orderCreateError); ok {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   "failed to create order",
+					"details": createErr.Error(),
+				})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			return
 		}
-		logger.Info("Partner SKU check completed", zap.Bool("has_partner_sku", hasPartnerSKU), zap.Int("partner_item_count", len(partnerItems)))
-
-		if !hasPartnerSKU {
-			logger.Info("No partner catalog SKUs found, returning 204")
+		if result.Outcome == cartOutcomeNoSupplierItems {
 			c.Status(http.StatusNoContent)
 			return
 		}
 
-		// Check if order with this partner_order_id already exists (idempotency by partner_order_id)
-		existingOrder, err := repos.SupplierOrder.GetByPartnerIDAndPartnerOrderID(
-			c.Request.Context(),
-			partner.ID,
-			req.PartnerOrderID,
-		)
-		if err == nil && existingOrder != nil {
-			// Order already exists - return it; sync to Shopify if not yet linked
-			logger.Info("Order already exists, returning existing order",
-				zap.String("partner_order_id", req.PartnerOrderID),
-				zap.String("order_id", existingOrder.ID.String()))
-			resp := buildCartSubmitResponseWithShopifySync(c.Request.Context(), existingOrder, partner, repos, cfg, logger)
-			c.JSON(http.StatusOK, resp)
-			return
-		}
-		// If error is "not found", that's expected - continue to create new order
-		if _, isNotFound := err.(*errors.ErrNotFound); !isNotFound && err != nil {
-			// Real database error - log it but continue (might be transient)
-			logger.Warn("Error checking for existing order, will attempt to create",
-				zap.Error(err),
-				zap.String("partner_order_id", req.PartnerOrderID))
+		// Store idempotency key if provided
+		if result.Outcome == cartOutcomeCreated {
+			idempotencyKey, requestHash, _, _ := middleware.GetIdempotencyInfo(c)
+			storeIdempotencyKey(c.Request.Context(), repos, logger, partner.ID, idempotencyKey, requestHash, result.Order.ID)
 		}
 
-		// Create order (only partner-scoped items are in partnerItems)
-		logger.Info("Creating order from cart", zap.String("partner_order_id", req.PartnerOrderID))
-		orderService := service.NewOrderService(repos, logger)
-		order, err := orderService.CreateOrderFromCart(c.Request.Context(), partner.ID, req, partnerItems)
-		if err != nil {
-			logger.Error("Failed to create order",
-				zap.Error(err),
-				zap.String("error_details", err.Error()),
-				zap.String("partner_order_id", req.PartnerOrderID))
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "failed to create order",
-				"details": err.Error(),
-			})
-			return
+		c.JSON(http.StatusOK, result.Response)
+	}
+}
+
+// Outcomes of submitting one cart
+const (
+	cartOutcomeCreated          = "created"
+	cartOutcomeExisting         = "existing"
+	cartOutcomeNoSupplierItems  = "no_supplier_items"
+	cartOutcomeValidationError  = "validation_error"
+	cartOutcomeCreditExceeded   = "credit_limit_exceeded"
+	cartOutcomeIdempotencyError = "idempotency_conflict"
+	cartOutcomeError            = "error"
+)
+
+// cartSubmitResult is the result of submitCart. Order and Response are unset for cartOutcomeNoSupplierItems.
+type cartSubmitResult struct {
+	Outcome  string
+	Order    *domain.SupplierOrder
+	Response CartSubmitResponse
+}
+
+// creditLimitExceededBody is the error response for a cart refused over the partner's credit limit.
+func creditLimitExceededBody(e *errors.ErrCreditLimitExceeded) gin.H {
+	available := e.Limit.Sub(e.Exposure)
+	if available.IsNegative() {
+		available = domain.NewMoney(0, available.Currency())
+	}
+	return gin.H{
+		"error": "credit limit exceeded",
+		"code":  cartOutcomeCreditExceeded,
+		"details": gin.H{
+			"credit_limit":     e.Limit,
+			"exposure":         e.Exposure,
+			"available_credit": available,
+			"order_total":      e.Requested,
+		},
+	}
+}
+
+// orderCreateError is returned by submitCart when the order itself could not be created.
+type orderCreateError struct {
+	err error
+}
+
+func (e *orderCreateError) Error() string {
+	return e.err.Error()
+}
+
+// submitCart runs one validated cart through the partner SKU check, the partner_order_id duplicate check,
+// the price and stock checks, order creation (with the credit check), the possible duplicate check (same customer
+// and items), the partner's auto-confirmation rules and Shopify draft/complete. Shopify failures are reported in
+// Response.ShopifyError, not as errors; an invalid delivery window or customer phone, an unknown city under the
+// strict address policy, or prices or quantities refused under the partner's reject policies, return
+// *errors.ErrValidation; an order over the credit limit under the reject policy returns
+// *errors.ErrCreditLimitExceeded.
+func submitCart(
+	ctx context.Context,
+	cfg *config.Config,
+	repos *repository.Repositories,
+	logger *zap.Logger,
+	partner *domain.Partner,
+	req service.CartSubmitRequest,
+) (*cartSubmitResult, error) {
+	// Check for partner catalog SKUs (partner-scoped; only this partner's SKUs accepted)
+	logger.Info("Checking cart for partner catalog SKUs", zap.Int("item_count", len(req.Items)), zap.String("partner_id", partner.ID.String()))
+	skuService := service.NewSKUService(repos, logger)
+	hasPartnerSKU, partnerItems, err := skuService.CheckCartForPartnerSKUs(
+		ctx,
+		partner.ID,
+		req.Items,
+	)
+	if err != nil {
+		logger.Error("Failed to check partner SKUs", zap.Error(err))
+		return nil, err
+	}
+	logger.Info("Partner SKU check completed", zap.Bool("has_partner_sku", hasPartnerSKU), zap.Int("partner_item_count", len(partnerItems)))
+
+	if !hasPartnerSKU {
+		logger.Info("No partner catalog SKUs found", zap.String("partner_order_id", req.PartnerOrderID))
+		return &cartSubmitResult{Outcome: cartOutcomeNoSupplierItems}, nil
+	}
+
+	// Check if order with this partner_order_id already exists (idempotency by partner_order_id)
+	existingOrder, err := repos.SupplierOrder.GetByPartnerIDAndPartnerOrderID(
+		ctx,
+		partner.ID,
+		req.PartnerOrderID,
+	)
+	if err == nil && existingOrder != nil {
+		// Order already exists - return it; sync to Shopify if not yet linked
+		logger.Info("Order already exists, returning existing order",
+			zap.String("partner_order_id", req.PartnerOrderID),
+			zap.String("order_id", existingOrder.ID.String()))
+		resp := buildCartSubmitResponseWithShopifySync(ctx, existingOrder, partner, repos, cfg, logger)
+		return &cartSubmitResult{Outcome: cartOutcomeExisting, Order: existingOrder, Response: resp}, nil
+	}
+	// If error is "not found", that's expected - continue to create new order
+	if _, isNotFound := err.(*errors.ErrNotFound); !isNotFound && err != nil {
+		// Real database error - log it but continue (might be transient)
+		logger.Warn("Error checking for existing order, will attempt to create",
+			zap.Error(err),
+			zap.String("partner_order_id", req.PartnerOrderID))
+	}
+
+	// Checked after the duplicate check so a retried cart still returns its order once the date has passed
+	deliveryWindow, err := service.ParseDeliveryWindow(req.DeliveryWindow, cfg.DeliveryWindow, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Customer phone in E.164 (stored, and matched to Shopify customers, in that form)
+	if err := service.NormalizeCustomerPhone(&req); err != nil {
+		return nil, err
+	}
+
+	// Canonical city/area from the Jordan gazetteer (unknown cities refused under the strict address_policy)
+	address, err := service.NormalizeShippingAddress(&req.Shipping, partner.AddressPolicy)
+	if err != nil {
+		logger.Info("Cart rejected: unknown shipping city",
+			zap.String("partner_order_id", req.PartnerOrderID),
+			zap.String("city", req.Shipping.City))
+		return nil, err
+	}
+
+	// Agreed wholesale prices from the partner's price list (warehouse local date)
+	priceList, err := service.NewPriceListService(repos, logger).Load(ctx, partner, time.Now().In(cfg.DeliveryWindow.Location))
+	if err != nil {
+		logger.Error("Failed to load partner price list", zap.Error(err))
+		return nil, err
+	}
+
+	// Amounts are in the partner's currency
+	if err := service.CheckCartCurrency(&req, partner); err != nil {
+		return nil, err
+	}
+
+	// Compare the partner's prices and totals with ours; override rewrites req before the order is created
+	priceCheck, err := service.CheckCartPrices(&req, partnerItems, priceList, partner.PricePolicy)
+	if err != nil {
+		logger.Info("Cart rejected: prices do not match",
+			zap.String("partner_order_id", req.PartnerOrderID),
+			zap.Int("discrepancies", len(priceCheck.Discrepancies)))
+		return nil, err
+	}
+
+	// Check quantities against live Shopify stock (per the partner's stock_policy)
+	shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
+	stockCheck, err := shopifyService.CheckCartStock(ctx, req.Items, partnerItems, partner.StockPolicy, cfg.StockCacheTTL)
+	if err != nil {
+		logger.Info("Cart rejected: insufficient stock",
+			zap.String("partner_order_id", req.PartnerOrderID),
+			zap.Int("short_items", len(stockCheck.Insufficient())))
+		return nil, err
+	}
+
+	// Create order (only partner-scoped items are in partnerItems); the total is checked against the partner's
+	// credit limit (per its credit_policy) as the order is stored
+	logger.Info("Creating order from cart", zap.String("partner_order_id", req.PartnerOrderID))
+	orderService := service.NewOrderService(repos, logger)
+	order, creditCheck, err := orderService.CreateOrderFromCart(ctx, partner, req, partnerItems, priceList, deliveryWindow)
+	if err != nil {
+		if _, ok := err.(*errors.ErrCreditLimitExceeded); ok {
+			logger.Info("Cart rejected: credit limit exceeded",
+				zap.String("partner_order_id", req.PartnerOrderID),
+				zap.String("details", err.Error()))
+			return nil, err
 		}
+		logger.Error("Failed to create order",
+			zap.Error(err),
+			zap.String("error_details", err.Error()),
+			zap.String("partner_order_id", req.PartnerOrderID))
+		return nil, &orderCreateError{err: err}
+	}
 
-		logger.Info("Order created successfully", zap.String("order_id", order.ID.String()))
+	logger.Info("Order created successfully", zap.String("order_id", order.ID.String()))
+	orderService.RecordPriceCheck(ctx, order, priceCheck)
+	service.ReserveStock(stockCheck)
+	if err := orderService.RecordStockCheck(ctx, order, stockCheck); err != nil {
+		logger.Warn("Failed to hold order for insufficient stock", zap.String("order_id", order.ID.String()), zap.Error(err))
+	}
+	if err := orderService.RecordCreditCheck(ctx, order, creditCheck); err != nil {
+		logger.Warn("Failed to hold order over credit limit", zap.String("order_id", order.ID.String()), zap.Error(err))
+	}
 
-		// Create Shopify draft order and complete it so it appears in Shopify Orders
-		var shopifyErr string
-		orderItems, err := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), order.ID)
+	// Create Shopify draft order and complete it so it appears in Shopify Orders
+	var shopifyErr string
+	orderItems, err := repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		logger.Error("Failed to get order items for draft order", zap.Error(err))
+		shopifyErr = "get order items: " + err.Error()
+	} else if original, err := orderService.FindPossibleDuplicate(ctx, order, orderItems, cfg.DuplicateOrderWindow); err != nil {
+		logger.Warn("Failed to check order for duplicates", zap.String("order_id", order.ID.String()), zap.Error(err))
+	} else if original != nil {
+		// Held for review; synced to Shopify once confirmed
+		if err := orderService.RecordPossibleDuplicate(ctx, order, original); err != nil {
+			logger.Warn("Failed to hold possible duplicate order", zap.String("order_id", order.ID.String()), zap.Error(err))
+		} else {
+			logger.Info("Order held as possible duplicate",
+				zap.String("order_id", order.ID.String()),
+				zap.String("duplicate_of", original.ID.String()))
+		}
+	}
+	if err == nil {
+		if rule, err := orderService.ApplyOrderRules(ctx, order, orderItems); err != nil {
+			logger.Warn("Failed to apply order rules", zap.String("order_id", order.ID.String()), zap.Error(err))
+		} else if rule != nil {
+			logger.Info("Order rule matched",
+				zap.String("order_id", order.ID.String()),
+				zap.String("rule_id", rule.ID.String()),
+				zap.String("action", string(rule.Action)))
+		}
+	}
+	if err == nil && !service.HeldAsDuplicate(order) {
+		draftOrderID, err := shopifyService.CreateDraftOrder(ctx, order, orderItems, partner.Name)
 		if err != nil {
-			logger.Error("Failed to get order items for draft order", zap.Error(err))
-			shopifyErr = "get order items: " + err.Error()
+			logger.Error("Failed to create Shopify draft order", zap.Error(err), zap.String("error_details", err.Error()))
+			shopifyErr = "create draft order: " + err.Error()
 		} else {
-			shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
-			draftOrderID, err := shopifyService.CreateDraftOrder(c.Request.Context(), order, orderItems, partner.Name)
+			if err := repos.SupplierOrder.UpdateShopifyDraftOrderID(ctx, order.ID, draftOrderID); err != nil {
+				logger.Warn("Failed to update order with draft order ID", zap.Error(err))
+			}
+			order.ShopifyDraftOrderID = &draftOrderID
+
+			shopifyOrderNumericID, err := shopifyService.CompleteDraftOrder(ctx, draftOrderID)
 			if err != nil {
-				logger.Error("Failed to create Shopify draft order", zap.Error(err), zap.String("error_details", err.Error()))
-				shopifyErr = "create draft order: " + err.Error()
+				logger.Error("Failed to complete Shopify draft order", zap.Error(err))
+				shopifyErr = "complete draft order: " + err.Error()
 			} else {
-				if err := repos.SupplierOrder.UpdateShopifyDraftOrderID(c.Request.Context(), order.ID, draftOrderID); err != nil {
-					logger.Warn("Failed to update order with draft order ID", zap.Error(err))
+				orderName, nameErr := shopifyService.GetOrderNameByID(ctx, shopifyOrderNumericID)
+				if nameErr != nil {
+					logger.Warn("Failed to get Shopify order name, storing numeric ID as string", zap.Error(nameErr))
+					orderName = strconv.FormatInt(shopifyOrderNumericID, 10)
 				}
-				order.ShopifyDraftOrderID = &draftOrderID
-
-				shopifyOrderNumericID, err := shopifyService.CompleteDraftOrder(c.Request.Context(), draftOrderID)
-				if err != nil {
-					logger.Error("Failed to complete Shopify draft order", zap.Error(err))
-					shopifyErr = "complete draft order: " + err.Error()
-				} else {
-					orderName, nameErr := shopifyService.GetOrderNameByID(c.Request.Context(), shopifyOrderNumericID)
-					if nameErr != nil {
-						logger.Warn("Failed to get Shopify order name, storing numeric ID as string", zap.Error(nameErr))
-						orderName = strconv.FormatInt(shopifyOrderNumericID, 10)
-					}
-					if err := repos.SupplierOrder.UpdateShopifyOrderID(c.Request.Context(), order.ID, orderName); err != nil {
-						logger.Warn("Failed to update order with Shopify order ID", zap.Error(err))
-					}
-					order.ShopifyOrderID = &orderName
-					if setErr := shopifyService.SetOrderPartnerMetafield(c.Request.Context(), orderName, partner.Name); setErr != nil {
-						logger.Warn("Failed to set order partner metafield", zap.Error(setErr))
-					}
+				if err := repos.SupplierOrder.UpdateShopifyOrderID(ctx, order.ID, orderName); err != nil {
+					logger.Warn("Failed to update order with Shopify order ID", zap.Error(err))
+				}
+				order.ShopifyOrderID = &orderName
+				if setErr := shopifyService.SetOrderPartnerMetafield(ctx, orderName, partner.Name); setErr != nil {
+					logger.Warn("Failed to set order partner metafield", zap.Error(setErr))
 				}
 			}
 		}
+	}
 
-		// Build response with Shopify info so caller can see success or error
-		resp := CartSubmitResponse{
-			SupplierOrderID:     order.ID.String(),
-			PartnerOrderID:      order.PartnerOrderID,
-			Status:              order.Status,
-			ShopifyDraftOrderID: order.ShopifyDraftOrderID,
-			ShopifyOrderID:      order.ShopifyOrderID,
-			ShopifyError:        shopifyErr,
-		}
-
-		// Store idempotency key if provided
-		idempotencyKey, requestHash, _, _ := middleware.GetIdempotencyInfo(c)
-		if idempotencyKey != "" {
-			idempotency := &domain.IdempotencyKey{
-				Key:             idempotencyKey,
-				PartnerID:       partner.ID,
-				SupplierOrderID: order.ID,
-				RequestHash:     requestHash,
-			}
-			if err := repos.IdempotencyKey.Create(c.Request.Context(), idempotency); err != nil {
-				logger.Warn("Failed to store idempotency key", zap.Error(err))
-			}
-		}
+	// Build response with Shopify info so caller can see success or error
+	resp := CartSubmitResponse{
+		SupplierOrderID:     order.ID.String(),
+		PartnerOrderID:      order.PartnerOrderID,
+		Status:              order.Status,
+		ShopifyDraftOrderID: order.ShopifyDraftOrderID,
+		ShopifyOrderID:      order.ShopifyOrderID,
+		ShopifyError:        shopifyErr,
+	}
+	if len(priceCheck.Discrepancies) > 0 {
+		resp.PricePolicy = priceCheck.Policy
+		resp.PriceDiscrepancies = priceCheck.Discrepancies
+		resp.PricesOverridden = priceCheck.Overridden
+	}
+	if stockCheck != nil {
+		resp.Availability = stockCheck.Items
+	}
+	if order.HoldReason != nil {
+		resp.HoldReason = *order.HoldReason
+	}
+	resp.PossibleDuplicateOf = order.PossibleDuplicateOf
+	resp.Shipping = address
+	return &cartSubmitResult{Outcome: cartOutcomeCreated, Order: order, Response: resp}, nil
+}
 
-		c.JSON(http.StatusOK, resp)
+// storeIdempotencyKey records key -> order so a retry with the same key returns the order. No-op when key is empty.
+func storeIdempotencyKey(ctx context.Context, repos *repository.Repositories, logger *zap.Logger, partnerID uuid.UUID, key, requestHash string, orderID uuid.UUID) {
+	if key == "" {
+		return
+	}
+	idempotency := &domain.IdempotencyKey{
+		Key:             key,
+		PartnerID:       partnerID,
+		SupplierOrderID: orderID,
+		RequestHash:     requestHash,
+	}
+	if err := repos.IdempotencyKey.Create(ctx, idempotency); err != nil {
+		logger.Warn("Failed to store idempotency key", zap.Error(err))
 	}
 }
 
 // buildCartSubmitResponseWithShopifySync builds the cart submit response for an order.
-// If the order has no Shopify order linked yet, it attempts to create the draft order and complete it now (retroactive sync).
+// If the order has no Shopify order linked yet, it attempts to create the draft order and complete it now (retroactive sync),
+// unless the order is held as a possible duplicate.
 func buildCartSubmitResponseWithShopifySync(
 	ctx context.Context,
 	order *domain.SupplierOrder,
@@ -255,8 +460,12 @@ func buildCartSubmitResponseWithShopifySync(
 		Status:              order.Status,
 		ShopifyDraftOrderID: order.ShopifyDraftOrderID,
 		ShopifyOrderID:      order.ShopifyOrderID,
+		PossibleDuplicateOf: order.PossibleDuplicateOf,
+	}
+	if order.HoldReason != nil {
+		resp.HoldReason = *order.HoldReason
 	}
-	if order.ShopifyOrderID != nil {
+	if order.ShopifyOrderID != nil || service.HeldAsDuplicate(order) {
 		return resp
 	}
 	shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
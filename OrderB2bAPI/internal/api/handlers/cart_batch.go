@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// CartBatchSubmitRequest is the POST /v1/carts/submit-batch payload. Carts are kept raw so one malformed cart
+// is reported in its own result instead of failing the whole batch.
+type CartBatchSubmitRequest struct {
+	Carts []json.RawMessage `json:"carts" binding:"required,min=1"`
+}
+
+// cartBatchEntry is one cart of a batch: a regular cart plus its own idempotency key.
+type cartBatchEntry struct {
+	service.CartSubmitRequest
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+// CartBatchResult is the outcome of one cart in a batch, in request order.
+type CartBatchResult struct {
+	Index          int                 `json:"index"`
+	PartnerOrderID string              `json:"partner_order_id,omitempty"`
+	Result         string              `json:"result"`
+	Status         int                 `json:"status"` // status POST /v1/carts/submit would have returned
+	Order          *CartSubmitResponse `json:"order,omitempty"`
+	Error          string              `json:"error,omitempty"`
+	Details        interface{}         `json:"details,omitempty"`
+}
+
+// HandleCartSubmitBatch handles POST /v1/carts/submit-batch. Each cart goes through the same steps as
+// POST /v1/carts/submit; the Idempotency-Key header is not used, each cart carries its own idempotency_key.
+func HandleCartSubmitBatch(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var req CartBatchSubmitRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+		if len(req.Carts) > cfg.CartBatchMaxSize {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fmt.Sprintf("at most %d carts per batch", cfg.CartBatchMaxSize),
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+		results := make([]CartBatchResult, len(req.Carts))
+		summary := make(map[string]int)
+		for i, raw := range req.Carts {
+			result := CartBatchResult{Index: i}
+
+			var entry cartBatchEntry
+			err := json.Unmarshal(raw, &entry)
+			if err == nil {
+				err = binding.Validator.ValidateStruct(&entry)
+			}
+			result.PartnerOrderID = entry.PartnerOrderID
+			if err != nil {
+				result.Result = cartOutcomeValidationError
+				result.Status = http.StatusUnprocessableEntity
+				result.Error = "validation failed"
+				result.Details = err.Error()
+				results[i] = result
+				summary[result.Result]++
+				continue
+			}
+
+			// Per-cart idempotency: same key and same cart returns the stored order
+			var requestHash string
+			if entry.IdempotencyKey != "" {
+				hash := sha256.Sum256(raw)
+				requestHash = hex.EncodeToString(hash[:])
+
+				existingKey, err := repos.IdempotencyKey.GetByKey(ctx, entry.IdempotencyKey)
+				if err != nil {
+					logger.Error("Failed to check idempotency key", zap.Error(err))
+				} else if existingKey != nil {
+					if existingKey.RequestHash != requestHash || existingKey.PartnerID != partner.ID {
+						result.Result = cartOutcomeIdempotencyError
+						result.Status = http.StatusConflict
+						result.Error = "idempotency key conflict: same key used with different payload"
+						results[i] = result
+						summary[result.Result]++
+						continue
+					}
+					order, err := repos.SupplierOrder.GetByID(ctx, existingKey.SupplierOrderID)
+					if err != nil {
+						logger.Error("Failed to get existing order", zap.Error(err))
+						result.Result = cartOutcomeError
+						result.Status = http.StatusInternalServerError
+						result.Error = "internal error"
+					} else {
+						resp := buildCartSubmitResponseWithShopifySync(ctx, order, partner, repos, cfg, logger)
+						result.Result = cartOutcomeExisting
+						result.Status = http.StatusOK
+						result.Order = &resp
+					}
+					results[i] = result
+					summary[result.Result]++
+					continue
+				}
+			}
+
+			submitted, err := submitCart(ctx, cfg, repos, logger, partner, entry.CartSubmitRequest)
+			switch {
+			case err != nil:
+				result.Result = cartOutcomeError
+				result.Status = http.StatusInternalServerError
+				result.Error = "internal error"
+				if createErr, ok := err.(*orderCreateError); ok {
+					result.Error = "failed to create order"
+					result.Details = createErr.Error()
+				}
+				if e, ok := err.(*errors.ErrValidation); ok {
+					result.Result = cartOutcomeValidationError
+					result.Status = http.StatusUnprocessableEntity
+					result.Error = "validation failed"
+					result.Details = e.Fields
+				}
+				if e, ok := err.(*errors.ErrCreditLimitExceeded); ok {
+					body := creditLimitExceededBody(e)
+					result.Result = cartOutcomeCreditExceeded
+					result.Status = http.StatusPaymentRequired
+					result.Error = "credit limit exceeded"
+					result.Details = body["details"]
+				}
+			case submitted.Outcome == cartOutcomeNoSupplierItems:
+				result.Result = submitted.Outcome
+				result.Status = http.StatusNoContent
+			default:
+				result.Result = submitted.Outcome
+				result.Status = http.StatusOK
+				result.Order = &submitted.Response
+				if submitted.Outcome == cartOutcomeCreated {
+					storeIdempotencyKey(ctx, repos, logger, partner.ID, entry.IdempotencyKey, requestHash, submitted.Order.ID)
+				}
+			}
+			results[i] = result
+			summary[result.Result]++
+		}
+
+		logger.Info("Cart batch processed", zap.String("partner_id", partner.ID.String()), zap.Int("cart_count", len(req.Carts)), zap.Any("summary", summary))
+		c.JSON(http.StatusOK, gin.H{
+			"results": results,
+			"summary": summary,
+		})
+	}
+}
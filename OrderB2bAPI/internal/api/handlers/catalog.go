@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -11,6 +12,7 @@ import (
 	"github.com/jafarshop/b2bapi/internal/config"
 	"github.com/jafarshop/b2bapi/internal/productb2b"
 	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
 )
 
 // HandleGetCatalogProducts handles GET /v1/catalog/products (partner's products only)
@@ -36,12 +38,30 @@ func HandleGetCatalogProducts(cfg *config.Config, repos *repository.Repositories
 			}
 		}
 
+		// Prices are the partner's agreed prices where its price list has a rule (warehouse local date)
+		priceList, err := service.NewPriceListService(repos, logger).Load(c.Request.Context(), partner, time.Now().In(cfg.DeliveryWindow.Location))
+		if err != nil {
+			logger.Error("Failed to load partner price list", zap.Error(err), zap.String("partner_id", partner.ID.String()))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
 		// Prefer ProductB2B when configured
 		if cfg.ProductB2B.BaseURL != "" && cfg.ProductB2B.ServiceKey != "" {
 			client := productb2b.NewClient(cfg.ProductB2B.BaseURL, cfg.ProductB2B.ServiceKey, logger)
 			body, err := client.GetCatalogProducts(c.Request.Context(), collectionHandle, cursor, limit)
 			if err == nil {
 				logger.Debug("Catalog served from ProductB2B", zap.String("collection_handle", collectionHandle), zap.String("partner_id", partner.ID.String()))
+				if !priceList.IsEmpty() {
+					if catalog, err := productb2b.ParseCatalogProducts(body); err == nil {
+						for _, product := range catalog.Data {
+							priceList.ApplyToCatalogProduct(product)
+						}
+						c.JSON(http.StatusOK, catalog)
+						return
+					}
+					logger.Warn("Failed to parse ProductB2B catalog, serving Shopify prices", zap.Error(err), zap.String("partner_id", partner.ID.String()))
+				}
 				c.Data(http.StatusOK, "application/json", body)
 				return
 			}
@@ -65,7 +85,7 @@ func HandleGetCatalogProducts(cfg *config.Config, repos *repository.Repositories
 				title = *m.Title
 			}
 			if m.Price != nil {
-				price = *m.Price
+				price = m.Price.String()
 			}
 			item := map[string]interface{}{
 				"sku":       m.SKU,
@@ -73,6 +93,10 @@ func HandleGetCatalogProducts(cfg *config.Config, repos *repository.Repositories
 				"price":     price,
 				"image_url": m.ImageURL,
 			}
+			if agreed, ok := priceList.AgreedPrice(m); ok {
+				item["price"] = agreed.String()
+				item["list_price"] = price
+			}
 			items = append(items, item)
 		}
 		c.JSON(http.StatusOK, gin.H{
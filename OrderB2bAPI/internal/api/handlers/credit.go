@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// MarkOrderPaidRequest represents mark order paid request (body optional)
+type MarkOrderPaidRequest struct {
+	Reference string `json:"reference" binding:"max=100"`
+}
+
+// writeCreditAccount renders the partner's credit limit, exposure and remaining credit.
+func writeCreditAccount(c *gin.Context, repos *repository.Repositories, logger *zap.Logger, partner *domain.Partner) {
+	account, err := service.NewCreditService(repos, logger).GetAccount(c.Request.Context(), partner)
+	if err != nil {
+		logger.Error("Failed to load partner credit", zap.String("partner_id", partner.ID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"partner_id":       partner.ID.String(),
+		"credit_limit":     account.Limit,
+		"credit_policy":    account.Policy,
+		"exposure":         account.Exposure,
+		"open_orders":      account.OpenOrders,
+		"available_credit": account.Available(),
+		"currency":         partner.Currency,
+	})
+}
+
+// HandleGetCredit handles GET /v1/credit (partner's credit limit and remaining credit)
+func HandleGetCredit(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		writeCreditAccount(c, repos, logger, partner)
+	}
+}
+
+// HandleGetSupplierCredit handles GET /v1/supplier/credit?partner_id=
+func HandleGetSupplierCredit(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		partnerID, err := uuid.Parse(strings.TrimSpace(c.Query("partner_id")))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "partner_id must be a UUID"})
+			return
+		}
+		partner, err := repos.Partner.GetByID(c.Request.Context(), partnerID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "partner not found"})
+				return
+			}
+			logger.Error("Failed to get partner", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		writeCreditAccount(c, repos, logger, partner)
+	}
+}
+
+// HandleMarkOrderPaid handles POST /v1/supplier/orders/:id/mark-paid (records payment for an order, e.g. settled
+// on the partner's account; frees its amount from the partner's credit exposure)
+func HandleMarkOrderPaid(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, ok := middleware.GetStaffFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Body is optional
+		var req MarkOrderPaidRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		order, ok := loadOrderForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		ctx := c.Request.Context()
+		creditService := service.NewCreditService(repos, logger)
+		recorded, err := creditService.RecordPayment(ctx, order, staff.ID, strings.TrimSpace(req.Reference))
+		if err != nil {
+			logger.Error("Failed to record order payment", zap.String("order_id", order.ID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record payment"})
+			return
+		}
+
+		if recorded && order.ShopifyOrderID != nil && *order.ShopifyOrderID != "" {
+			shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
+			if err := shopifyService.MarkOrderPaid(ctx, *order.ShopifyOrderID); err != nil {
+				logger.Warn("Failed to mark Shopify order paid", zap.String("order_id", order.ID.String()), zap.Error(err))
+				repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+					SupplierOrderID: order.ID,
+					EventType:       domain.OrderEventShopifySyncFailed,
+					EventData: map[string]interface{}{
+						"action": "mark_paid",
+						"error":  err.Error(),
+					},
+				})
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":             order.ID.String(),
+			"partner_id":     order.PartnerID.String(),
+			"status":         order.Status,
+			"payment_status": order.PaymentStatus,
+		})
+	}
+}
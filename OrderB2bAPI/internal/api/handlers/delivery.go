@@ -246,7 +246,7 @@ func createMinimalOrderFromWassel(ctx context.Context, repos *repository.Reposit
 		CustomerName:   "Wassel delivery",
 		CustomerPhone:  "",
 		ShippingAddress: map[string]interface{}{},
-		CartTotal:      0,
+		CartTotal:      domain.Money{},
 		PaymentStatus:  "",
 	}
 	if itemRef != "" && isDigitsOnly(itemRef) {
@@ -255,10 +255,49 @@ func createMinimalOrderFromWassel(ctx context.Context, repos *repository.Reposit
 	if err := repos.SupplierOrder.Create(ctx, order); err != nil {
 		return nil, err
 	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventCreated,
+		EventData: map[string]interface{}{
+			"source":            "wassel",
+			"item_reference_no": itemRef,
+		},
+	}
+	repos.OrderEvent.Create(ctx, event)
+
 	logger.Info("Internal delivery webhook: created minimal order for ItemReferenceNo", zap.String("item_reference_no", itemRef), zap.String("order_id", order.ID.String()))
 	return order, nil
 }
 
+// recordCODCollection adds the order's cash-on-delivery collection to the ledger and, when it is new and the
+// order exists in Shopify, marks the Shopify order paid. Failures are logged (and a Shopify failure recorded as
+// a shopify_sync_failed event) but never fail the webhook.
+func recordCODCollection(ctx context.Context, cfg *config.Config, repos *repository.Repositories, order *domain.SupplierOrder, waybill string, logger *zap.Logger) {
+	codService := service.NewCODService(repos, logger)
+	collected, err := codService.RecordDelivery(ctx, order, waybill)
+	if err != nil {
+		logger.Warn("Internal delivery webhook: failed to record COD collection", zap.String("order_id", order.ID.String()), zap.Error(err))
+	}
+	if !collected || order.ShopifyOrderID == nil || *order.ShopifyOrderID == "" {
+		return
+	}
+
+	shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
+	if err := shopifyService.MarkOrderPaid(ctx, *order.ShopifyOrderID); err != nil {
+		logger.Warn("Internal delivery webhook: failed to mark Shopify order paid", zap.String("order_id", order.ID.String()), zap.Error(err))
+		repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+			SupplierOrderID: order.ID,
+			EventType:       domain.OrderEventShopifySyncFailed,
+			EventData: map[string]interface{}{
+				"action": "mark_paid",
+				"error":  err.Error(),
+			},
+		})
+	}
+}
+
 func isDigitsOnly(s string) bool {
 	for _, r := range s {
 		if !unicode.IsDigit(r) {
@@ -378,7 +417,28 @@ func HandleInternalDeliveryWebhook(cfg *config.Config, repos *repository.Reposit
 		}
 
 		// Store last delivery status on the order (so we can show it via GET delivery-status even when partner has no webhook)
-		_ = repos.SupplierOrder.UpdateLastDeliveryStatus(c.Request.Context(), order.ID, status, statusLabel, waybill, deliveryImageURL)
+		orderService := service.NewOrderService(repos, logger)
+		if err := orderService.RecordDeliveryStatus(c.Request.Context(), order, status, statusLabel, waybill, deliveryImageURL); err != nil {
+			logger.Warn("Internal delivery webhook: failed to store delivery status", zap.String("order_id", order.ID.String()), zap.Error(err))
+		}
+
+		// Delivered (170): record the cash Wassel collected and mark the Shopify order paid (first time only)
+		if status == service.WasselStatusDelivered {
+			recordCODCollection(c.Request.Context(), cfg, repos, order, waybill, logger)
+		}
+
+		// Wassel return statuses (180, 190, 210) open a return so the order moves into the return flow
+		if service.IsWasselReturnStatus(status) {
+			returnService := service.NewReturnService(repos, logger)
+			ret, opened, retErr := returnService.OpenWasselReturn(c.Request.Context(), order, status)
+			if retErr != nil {
+				logger.Warn("Internal delivery webhook: failed to open return", zap.String("order_id", order.ID.String()), zap.Int("status", status), zap.Error(retErr))
+			} else if opened {
+				logger.Info("Internal delivery webhook: opened return", zap.String("order_id", order.ID.String()), zap.String("return_id", ret.ID.String()), zap.Int("status", status))
+				orderItems, _ := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), order.ID)
+				notifyPartnerReturnEvent(c.Request.Context(), repos, order, buildReturnResponse(ret, orderItems), "return_opened", logger)
+			}
+		}
 
 		partner, err := repos.Partner.GetByID(c.Request.Context(), order.PartnerID)
 		if err != nil {
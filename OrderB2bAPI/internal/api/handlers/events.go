@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// internalEventFields are stored on events for auditing but not exposed to partners.
+var internalEventFields = []string{"staff_id"}
+
+// HandleGetOrderEvents handles GET /v1/orders/:id/events (order timeline, oldest first).
+// Optional ?type= filters by a comma-separated list of event types.
+func HandleGetOrderEvents(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		idParam := c.Param("id")
+		if idParam == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order ID or partner_order_id required"})
+			return
+		}
+		order, err := resolveOrderByIDOrPartnerOrderID(c.Request.Context(), repos, partner.ID, idParam)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.PartnerID != partner.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 50
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		var eventTypes []domain.OrderEventType
+		if typeParam := strings.TrimSpace(c.Query("type")); typeParam != "" {
+			for _, t := range strings.Split(typeParam, ",") {
+				eventType := domain.OrderEventType(strings.TrimSpace(t))
+				if !eventType.IsValid() {
+					c.JSON(http.StatusBadRequest, gin.H{
+						"error":   "invalid event type",
+						"details": fmt.Sprintf("unknown event type %q", t),
+					})
+					return
+				}
+				eventTypes = append(eventTypes, eventType)
+			}
+		}
+
+		events, err := repos.OrderEvent.ListByOrderID(c.Request.Context(), order.ID, eventTypes, limit, offset)
+		if err != nil {
+			logger.Error("Failed to list order events", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		total, err := repos.OrderEvent.CountByOrderID(c.Request.Context(), order.ID, eventTypes)
+		if err != nil {
+			logger.Error("Failed to count order events", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		eventResponses := make([]gin.H, len(events))
+		for i, event := range events {
+			eventResponses[i] = buildOrderEventResponse(event)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"order_id":         order.ID.String(),
+			"partner_order_id": order.PartnerOrderID,
+			"events":           eventResponses,
+			"total":            total,
+			"limit":            limit,
+			"offset":           offset,
+		})
+	}
+}
+
+func buildOrderEventResponse(event *domain.OrderEvent) gin.H {
+	data := make(map[string]interface{}, len(event.EventData))
+	for k, v := range event.EventData {
+		data[k] = v
+	}
+	for _, field := range internalEventFields {
+		delete(data, field)
+	}
+
+	resp := gin.H{
+		"id":          event.ID.String(),
+		"type":        event.EventType,
+		"occurred_at": event.CreatedAt,
+		"data":        data,
+	}
+	if summary := orderEventSummary(event.EventType, data); summary != "" {
+		resp["summary"] = summary
+	}
+	return resp
+}
+
+// orderEventSummary is a short human-readable line for the timeline.
+func orderEventSummary(eventType domain.OrderEventType, data map[string]interface{}) string {
+	str := func(key string) string {
+		if v, ok := data[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+
+	switch eventType {
+	case domain.OrderEventCreated:
+		return "Order created"
+	case domain.OrderEventStatusChange:
+		if from := str("from"); from != "" {
+			return fmt.Sprintf("Status changed from %s to %s", from, str("to"))
+		}
+		return fmt.Sprintf("Status changed to %s", str("to"))
+	case domain.OrderEventStatusChangeRejected:
+		return fmt.Sprintf("Status change from %s to %s refused", str("from"), str("to"))
+	case domain.OrderEventTrackingUpdated:
+		if carrier := str("tracking_carrier"); carrier != "" {
+			return fmt.Sprintf("Tracking updated: %s %s", carrier, str("tracking_number"))
+		}
+		return fmt.Sprintf("Tracking updated: %s", str("tracking_number"))
+	case domain.OrderEventDeliveryStatus:
+		if label := str("status_label"); label != "" {
+			return fmt.Sprintf("Delivery status: %s", label)
+		}
+		return fmt.Sprintf("Delivery status: %s", str("status"))
+	case domain.OrderEventItemsFulfilled:
+		return "Items shipped"
+	case domain.OrderEventAmended:
+		return "Order amended"
+	case domain.OrderEventReturnOpened:
+		return "Return opened"
+	case domain.OrderEventReturnApproved:
+		return "Return approved"
+	case domain.OrderEventReturnRejected:
+		return "Return rejected"
+	case domain.OrderEventShopifySyncFailed:
+		return "Shopify sync failed"
+	case domain.OrderEventNoteAdded:
+		if str("author_type") == string(domain.NoteAuthorStaff) {
+			return "Note added by supplier"
+		}
+		return "Note added by partner"
+	case domain.OrderEventPriceDiscrepancy:
+		if str("overridden") == "true" {
+			return "Submitted prices replaced with catalog prices"
+		}
+		return "Submitted prices differ from catalog prices"
+	case domain.OrderEventStockShortage:
+		if str("held") == "true" {
+			return "Held: insufficient stock"
+		}
+		return "Insufficient stock reported"
+	case domain.OrderEventPaymentCollected:
+		if str("source") == "staff" {
+			return fmt.Sprintf("Payment recorded: %s", str("amount"))
+		}
+		return fmt.Sprintf("Cash on delivery collected: %s", str("amount"))
+	case domain.OrderEventCreditLimitExceeded:
+		return "Held: credit limit exceeded"
+	case domain.OrderEventInvoiceIssued:
+		return fmt.Sprintf("Invoice %s issued", str("invoice_number"))
+	case domain.OrderEventPossibleDuplicate:
+		return fmt.Sprintf("Held: possible duplicate of order %s", str("duplicate_of_partner_order_id"))
+	case domain.OrderEventRuleMatched:
+		if str("action") == string(domain.OrderRuleActionConfirm) {
+			return fmt.Sprintf("Confirmed automatically (rule %q)", str("rule_name"))
+		}
+		return fmt.Sprintf("Held for review (rule %q)", str("rule_name"))
+	}
+	return ""
+}
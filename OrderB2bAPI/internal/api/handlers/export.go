@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/xlsx"
+)
+
+// HandleExportOrders handles GET /v1/orders/export?format=csv|xlsx&from=&to= (the partner's orders with items).
+// Accepts the same filters as GET /v1/admin/orders. Rows are streamed; errors after the first byte can only be logged.
+func HandleExportOrders(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		format := c.DefaultQuery("format", "csv")
+		if format != "csv" && format != "xlsx" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or xlsx"})
+			return
+		}
+		filter, verr := parseOrderListFilter(c)
+		if verr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": verr.Error(), "details": verr.Fields})
+			return
+		}
+		// Same scoping as the order listing: only this partner's orders
+		filter.PartnerID = &partner.ID
+
+		// Large exports stream for longer than the server's write timeout; lift it for this request only so the
+		// file is not cut off partway
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			logger.Warn("Order export: could not lift the write deadline", zap.Error(err))
+		}
+
+		filename := fmt.Sprintf("orders_%s.%s", time.Now().UTC().Format("2006-01-02_150405"), format)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		orderService := service.NewOrderService(repos, logger)
+
+		var err error
+		switch format {
+		case "xlsx":
+			c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+			c.Status(http.StatusOK)
+			err = exportXLSX(c, orderService, filter)
+		default:
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Status(http.StatusOK)
+			err = exportCSV(c, orderService, filter)
+		}
+		if err != nil {
+			// Headers are already sent; the client gets a truncated file
+			logger.Error("Order export failed", zap.String("partner_id", partner.ID.String()), zap.String("format", format), zap.Error(err))
+			return
+		}
+		logger.Info("Order export completed", zap.String("partner_id", partner.ID.String()), zap.String("format", format))
+	}
+}
+
+type orderExporter interface {
+	ExportOrders(ctx context.Context, filter repository.OrderListFilter, writeRow func([]interface{}) error, flush func() error) error
+}
+
+func exportCSV(c *gin.Context, orderService orderExporter, filter repository.OrderListFilter) error {
+	// UTF-8 BOM so Excel shows Arabic names correctly
+	if _, err := c.Writer.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return err
+	}
+	w := csv.NewWriter(c.Writer)
+	if err := w.Write(service.OrderExportColumns); err != nil {
+		return err
+	}
+	record := make([]string, len(service.OrderExportColumns))
+	writeRow := func(cells []interface{}) error {
+		for i, cell := range cells {
+			record[i] = csvCell(cell)
+		}
+		return w.Write(record)
+	}
+	flush := func() error {
+		w.Flush()
+		c.Writer.Flush()
+		return w.Error()
+	}
+	if err := orderService.ExportOrders(c.Request.Context(), filter, writeRow, flush); err != nil {
+		return err
+	}
+	return flush()
+}
+
+func exportXLSX(c *gin.Context, orderService orderExporter, filter repository.OrderListFilter) error {
+	w, err := xlsx.NewWriter(c.Writer, "Orders")
+	if err != nil {
+		return err
+	}
+	if err := w.WriteHeader(service.OrderExportColumns); err != nil {
+		return err
+	}
+	flush := func() error {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	// Amounts as number cells (Excel keeps numbers as floats anyway)
+	writeRow := func(cells []interface{}) error {
+		for i, cell := range cells {
+			if amount, ok := cell.(domain.Money); ok {
+				cells[i] = amount.Float64()
+			}
+		}
+		return w.WriteRow(cells)
+	}
+	if err := orderService.ExportOrders(c.Request.Context(), filter, writeRow, flush); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func csvCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case int:
+		return strconv.Itoa(v)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// writeInvoice sends the order's invoice PDF, issuing the invoice on first request.
+func writeInvoice(c *gin.Context, cfg *config.Config, repos *repository.Repositories, logger *zap.Logger, partner *domain.Partner, order *domain.SupplierOrder) {
+	invoiceService := service.NewInvoiceService(cfg.Invoice, repos, logger)
+	invoice, err := invoiceService.GetOrIssue(c.Request.Context(), partner, order)
+	if err != nil {
+		if e, ok := err.(*errors.ErrConflict); ok {
+			c.JSON(http.StatusConflict, gin.H{"error": e.Error()})
+			return
+		}
+		logger.Error("Failed to issue invoice", zap.String("order_id", order.ID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue invoice"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, invoice.Number))
+	c.Header("X-Invoice-Number", invoice.Number)
+	c.Data(http.StatusOK, "application/pdf", invoice.PDF)
+}
+
+// writeStatement sends the partner's statement PDF for the :month path parameter (YYYY-MM).
+func writeStatement(c *gin.Context, cfg *config.Config, repos *repository.Repositories, logger *zap.Logger, partner *domain.Partner) {
+	month := strings.TrimSpace(c.Param("month"))
+	from, to, err := service.ParseSettlementPeriod(month, cfg.DeliveryWindow.Location, time.Now())
+	if err != nil || month == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid month",
+			"details": map[string]string{"month": "must be a month (YYYY-MM)"},
+		})
+		return
+	}
+
+	invoiceService := service.NewInvoiceService(cfg.Invoice, repos, logger)
+	statement, err := invoiceService.GetStatement(c.Request.Context(), partner, from, to)
+	if err != nil {
+		logger.Error("Failed to load statement", zap.String("partner_id", partner.ID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	pdf, err := invoiceService.RenderStatement(statement)
+	if err != nil {
+		logger.Error("Failed to render statement", zap.String("partner_id", partner.ID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s.pdf"`, statement.Period))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// HandleGetOrderInvoice handles GET /v1/orders/:id/invoice (tax invoice PDF; issued on first request once the
+// order is confirmed)
+func HandleGetOrderInvoice(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		idParam := c.Param("id")
+		if idParam == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order ID or partner_order_id required"})
+			return
+		}
+		order, err := resolveOrderByIDOrPartnerOrderID(c.Request.Context(), repos, partner.ID, idParam)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.PartnerID != partner.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		writeInvoice(c, cfg, repos, logger, partner, order)
+	}
+}
+
+// HandleGetStatement handles GET /v1/statements/:month (partner's statement of account PDF for YYYY-MM)
+func HandleGetStatement(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		writeStatement(c, cfg, repos, logger, partner)
+	}
+}
+
+// HandleGetSupplierOrderInvoice handles GET /v1/supplier/orders/:id/invoice
+func HandleGetSupplierOrderInvoice(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		order, ok := loadOrderForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+		partner, err := repos.Partner.GetByID(c.Request.Context(), order.PartnerID)
+		if err != nil {
+			logger.Error("Failed to get partner", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		writeInvoice(c, cfg, repos, logger, partner, order)
+	}
+}
+
+// HandleGetSupplierStatement handles GET /v1/supplier/statements/:month?partner_id=
+func HandleGetSupplierStatement(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		partnerID, err := uuid.Parse(strings.TrimSpace(c.Query("partner_id")))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "partner_id must be a UUID"})
+			return
+		}
+		partner, err := repos.Partner.GetByID(c.Request.Context(), partnerID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "partner not found"})
+				return
+			}
+			logger.Error("Failed to get partner", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		writeStatement(c, cfg, repos, logger, partner)
+	}
+}
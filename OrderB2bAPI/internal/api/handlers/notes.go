@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// buildOrderNoteResponse renders a note. Which staff member wrote it is only shown to staff.
+func buildOrderNoteResponse(note *domain.OrderNote, forStaff bool) gin.H {
+	resp := gin.H{
+		"id":          note.ID.String(),
+		"order_id":    note.SupplierOrderID.String(),
+		"author_type": note.AuthorType,
+		"body":        note.Body,
+		"created_at":  note.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if forStaff && note.StaffID != nil {
+		resp["staff_id"] = note.StaffID.String()
+	}
+	return resp
+}
+
+// addOrderNote stores the note, mirrors the thread into Shopify and, for staff replies, notifies the partner's
+// webhook. A Shopify failure does not fail the request (the note is kept; the next note retries the sync):
+// it is recorded as a shopify_sync_failed event and reported as shopify_synced=false.
+func addOrderNote(c *gin.Context, cfg *config.Config, repos *repository.Repositories, logger *zap.Logger, order *domain.SupplierOrder, note *domain.OrderNote) {
+	var req service.OrderNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "validation failed",
+			"details": err.Error(),
+		})
+		return
+	}
+	note.Body = req.Body
+
+	ctx := c.Request.Context()
+	orderService := service.NewOrderService(repos, logger)
+	if err := orderService.AddNote(ctx, order, note); err != nil {
+		if e, ok := err.(*errors.ErrValidation); ok {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "details": e.Error()})
+			return
+		}
+		logger.Error("Failed to add order note", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add note"})
+		return
+	}
+
+	shopifySynced := true
+	notes, err := repos.OrderNote.ListByOrderID(ctx, order.ID)
+	if err == nil {
+		shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
+		var action string
+		action, err = shopifyService.SyncOrderNotes(ctx, order, notes)
+		if err != nil {
+			repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+				SupplierOrderID: order.ID,
+				EventType:       domain.OrderEventShopifySyncFailed,
+				EventData: map[string]interface{}{
+					"action": action,
+					"error":  err.Error(),
+				},
+			})
+		}
+	}
+	if err != nil {
+		shopifySynced = false
+		logger.Warn("Failed to sync order notes to Shopify", zap.String("order_id", order.ID.String()), zap.Error(err))
+	}
+
+	resp := buildOrderNoteResponse(note, note.AuthorType == domain.NoteAuthorStaff)
+	resp["shopify_synced"] = shopifySynced
+	c.JSON(http.StatusCreated, resp)
+
+	if note.AuthorType != domain.NoteAuthorStaff {
+		return
+	}
+	// Staff replied: notify the owning partner's webhook if configured (fire-and-forget)
+	partner, err := repos.Partner.GetByID(ctx, order.PartnerID)
+	if err != nil {
+		logger.Warn("Order note: partner lookup failed, skipping webhook", zap.String("order_id", order.ID.String()), zap.Error(err))
+		return
+	}
+	if partner.WebhookURL == nil || *partner.WebhookURL == "" {
+		return
+	}
+	webhookPayload := map[string]interface{}{
+		"partner_id":       partner.ID.String(),
+		"order_id":         order.ID.String(),
+		"partner_order_id": order.PartnerOrderID,
+		"note":             buildOrderNoteResponse(note, false),
+		"event":            "order_note",
+	}
+	go service.NotifyDeliveryUpdate(*partner.WebhookURL, webhookPayload, logger)
+}
+
+func listOrderNotes(c *gin.Context, repos *repository.Repositories, logger *zap.Logger, order *domain.SupplierOrder, forStaff bool) {
+	notes, err := repos.OrderNote.ListByOrderID(c.Request.Context(), order.ID)
+	if err != nil {
+		logger.Error("Failed to list order notes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	noteResponses := make([]gin.H, len(notes))
+	for i, note := range notes {
+		noteResponses[i] = buildOrderNoteResponse(note, forStaff)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"order_id":         order.ID.String(),
+		"partner_order_id": order.PartnerOrderID,
+		"notes":            noteResponses,
+		"total":            len(notes),
+	})
+}
+
+// loadOrderForPartner resolves the :id order for a partner request and writes the error response when it fails.
+func loadOrderForPartner(c *gin.Context, repos *repository.Repositories, logger *zap.Logger, partner *domain.Partner) (*domain.SupplierOrder, bool) {
+	idParam := c.Param("id")
+	if idParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order ID or partner_order_id required"})
+		return nil, false
+	}
+	order, err := resolveOrderByIDOrPartnerOrderID(c.Request.Context(), repos, partner.ID, idParam)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			return nil, false
+		}
+		logger.Error("Failed to get order", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+	if order.PartnerID != partner.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return nil, false
+	}
+	return order, true
+}
+
+// HandleCreateOrderNote handles POST /v1/orders/:id/notes (partner adds a note to the order's thread)
+func HandleCreateOrderNote(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		order, ok := loadOrderForPartner(c, repos, logger, partner)
+		if !ok {
+			return
+		}
+		addOrderNote(c, cfg, repos, logger, order, &domain.OrderNote{
+			AuthorType: domain.NoteAuthorPartner,
+			PartnerID:  &partner.ID,
+		})
+	}
+}
+
+// HandleListOrderNotes handles GET /v1/orders/:id/notes (oldest first)
+func HandleListOrderNotes(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		order, ok := loadOrderForPartner(c, repos, logger, partner)
+		if !ok {
+			return
+		}
+		listOrderNotes(c, repos, logger, order, false)
+	}
+}
+
+// HandleCreateSupplierOrderNote handles POST /v1/supplier/orders/:id/notes (staff reply; the partner's webhook
+// receives an order_note event)
+func HandleCreateSupplierOrderNote(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, ok := middleware.GetStaffFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		order, ok := loadOrderForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+		addOrderNote(c, cfg, repos, logger, order, &domain.OrderNote{
+			AuthorType: domain.NoteAuthorStaff,
+			StaffID:    &staff.ID,
+		})
+	}
+}
+
+// HandleListSupplierOrderNotes handles GET /v1/supplier/orders/:id/notes (oldest first)
+func HandleListSupplierOrderNotes(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		order, ok := loadOrderForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+		listOrderNotes(c, repos, logger, order, true)
+	}
+}
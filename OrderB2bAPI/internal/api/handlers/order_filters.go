@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+	"github.com/jafarshop/b2bapi/pkg/phone"
+)
+
+// parseOrderListFilter reads the shared order listing query parameters:
+// status (comma-separated or repeated), created_from/created_to (alias from/to), updated_from/updated_to (RFC3339 or YYYY-MM-DD;
+// a date-only "to" includes that whole day), customer_phone, shopify_order_id, delivery_status, q, cursor and limit.
+func parseOrderListFilter(c *gin.Context) (repository.OrderListFilter, *errors.ErrValidation) {
+	filter := repository.OrderListFilter{Limit: 50}
+	fields := make(map[string]string)
+
+	if l, err := strconv.Atoi(c.DefaultQuery("limit", "50")); err == nil && l >= 1 && l <= 100 {
+		filter.Limit = l
+	}
+
+	for _, raw := range c.QueryArray("status") {
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			status := domain.OrderStatus(s)
+			if !status.IsValid() {
+				fields["status"] = fmt.Sprintf("unknown status %q", s)
+				continue
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	// parseTime reads the first of params that is set (later names are aliases)
+	parseTime := func(endOfDay bool, params ...string) *time.Time {
+		var param, v string
+		for _, param = range params {
+			if v = strings.TrimSpace(c.Query(param)); v != "" {
+				break
+			}
+		}
+		if v == "" {
+			return nil
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return &t
+		}
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			if endOfDay {
+				t = t.AddDate(0, 0, 1)
+			}
+			return &t
+		}
+		fields[param] = "must be RFC3339 or YYYY-MM-DD"
+		return nil
+	}
+	filter.CreatedFrom = parseTime(false, "created_from", "from")
+	filter.CreatedTo = parseTime(true, "created_to", "to")
+	filter.UpdatedFrom = parseTime(false, "updated_from")
+	filter.UpdatedTo = parseTime(true, "updated_to")
+
+	filter.CustomerPhone = strings.TrimSpace(c.Query("customer_phone"))
+	// Phones are stored in E.164; other forms of the same number match too
+	if number, err := phone.Parse(filter.CustomerPhone); err == nil {
+		filter.CustomerPhone = number
+	}
+	filter.ShopifyOrderID = strings.TrimSpace(c.Query("shopify_order_id"))
+	filter.Search = strings.TrimSpace(c.Query("q"))
+
+	if v := strings.TrimSpace(c.Query("delivery_status")); v != "" {
+		if code, err := strconv.Atoi(v); err == nil {
+			filter.DeliveryStatus = &code
+		} else {
+			fields["delivery_status"] = "must be a Wassel status code"
+		}
+	}
+
+	if v := strings.TrimSpace(c.Query("cursor")); v != "" {
+		cursor, err := repository.DecodeOrderCursor(v)
+		if err != nil {
+			fields["cursor"] = err.Error()
+		}
+		filter.After = cursor
+	}
+
+	if len(fields) > 0 {
+		return filter, &errors.ErrValidation{Message: "invalid order filter", Fields: fields}
+	}
+	return filter, nil
+}
+
+// orderPageResponse adds pagination fields (total, limit, next_cursor) to a listing response.
+func orderPageResponse(resp gin.H, page *repository.OrderPage, limit int) gin.H {
+	resp["total"] = page.Total
+	resp["limit"] = limit
+	if page.Next != nil {
+		resp["next_cursor"] = page.Next.Encode()
+	} else {
+		resp["next_cursor"] = nil
+	}
+	return resp
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+const (
+	// dryRunDefaultDays is the period a dry run covers without from / to
+	dryRunDefaultDays  = 30
+	dryRunDefaultLimit = 200
+	dryRunMaxLimit     = 1000
+)
+
+func buildOrderRuleResponse(rule *domain.PartnerOrderRule) gin.H {
+	return gin.H{
+		"id":              rule.ID.String(),
+		"partner_id":      rule.PartnerID.String(),
+		"name":            rule.Name,
+		"action":          rule.Action,
+		"priority":        rule.Priority,
+		"min_cart_total":  rule.MinCartTotal,
+		"max_cart_total":  rule.MaxCartTotal,
+		"cities":          rule.CityCodes,
+		"min_item_count":  rule.MinItemCount,
+		"max_item_count":  rule.MaxItemCount,
+		"min_delivered":   rule.MinDelivered,
+		"max_returned":    rule.MaxReturned,
+		"payment_methods": rule.PaymentMethods,
+		"created_at":      rule.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// HandleListOrderRules handles GET /v1/supplier/order-rules?partner_id= (the partner's auto-confirmation rules, in
+// the order they are checked)
+func HandleListOrderRules(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		partnerID, err := uuid.Parse(strings.TrimSpace(c.Query("partner_id")))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "partner_id must be a UUID"})
+			return
+		}
+
+		rules, err := repos.PartnerOrderRule.ListByPartnerID(c.Request.Context(), partnerID)
+		if err != nil {
+			logger.Error("Failed to list order rules", zap.String("partner_id", partnerID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		responses := make([]gin.H, len(rules))
+		for i, rule := range rules {
+			responses[i] = buildOrderRuleResponse(rule)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id": partnerID.String(),
+			"rules":      responses,
+		})
+	}
+}
+
+// HandleCreateOrderRule handles POST /v1/supplier/order-rules (adds an auto-confirmation rule for a partner)
+func HandleCreateOrderRule(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var req service.OrderRuleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+		partnerID, ok := loadPartnerForStaff(c, repos, logger, req.PartnerID)
+		if !ok {
+			return
+		}
+
+		rule, err := service.ParseOrderRule(&req, partnerID, "")
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "details": e.Fields})
+				return
+			}
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "details": err.Error()})
+			return
+		}
+		if err := repos.PartnerOrderRule.Create(c.Request.Context(), rule); err != nil {
+			logger.Error("Failed to create order rule", zap.String("partner_id", partnerID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create order rule"})
+			return
+		}
+
+		logger.Info("Order rule created",
+			zap.String("partner_id", partnerID.String()),
+			zap.String("rule_id", rule.ID.String()),
+			zap.String("action", string(rule.Action)))
+		c.JSON(http.StatusCreated, buildOrderRuleResponse(rule))
+	}
+}
+
+// HandleDeleteOrderRule handles DELETE /v1/supplier/order-rules/:id
+func HandleDeleteOrderRule(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order rule ID"})
+			return
+		}
+
+		if err := repos.PartnerOrderRule.Delete(c.Request.Context(), id); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order rule not found"})
+				return
+			}
+			logger.Error("Failed to delete order rule", zap.String("rule_id", id.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// HandleDryRunOrderRules handles POST /v1/supplier/order-rules/dry-run (checks the partner's saved rules, or the
+// rules in the body, against its past orders and reports what each would have done; nothing is changed)
+func HandleDryRunOrderRules(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var req service.OrderRuleDryRunRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+		partnerID, ok := loadPartnerForStaff(c, repos, logger, req.PartnerID)
+		if !ok {
+			return
+		}
+
+		fields := make(map[string]string)
+		// Inline rules are checked in priority order, like saved ones; equal priorities keep their order
+		var rules []*domain.PartnerOrderRule
+		for i := range req.Rules {
+			rule, err := service.ParseOrderRule(&req.Rules[i], partnerID, fmt.Sprintf("rules[%d].", i))
+			if err != nil {
+				if e, ok := err.(*errors.ErrValidation); ok {
+					for field, message := range e.Fields {
+						fields[field] = message
+					}
+				}
+				continue
+			}
+			rules = insertByPriority(rules, rule)
+		}
+
+		loc := cfg.DeliveryWindow.Location
+		now := time.Now().In(loc)
+		to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+		if v := strings.TrimSpace(req.To); v != "" {
+			if day, err := time.ParseInLocation("2006-01-02", v, loc); err == nil {
+				to = day.AddDate(0, 0, 1)
+			} else {
+				fields["to"] = "must be a date (YYYY-MM-DD)"
+			}
+		}
+		from := to.AddDate(0, 0, -dryRunDefaultDays)
+		if v := strings.TrimSpace(req.From); v != "" {
+			if day, err := time.ParseInLocation("2006-01-02", v, loc); err == nil {
+				from = day
+			} else {
+				fields["from"] = "must be a date (YYYY-MM-DD)"
+			}
+		}
+		if !from.Before(to) {
+			fields["from"] = "must not be after to"
+		}
+		limit := req.Limit
+		switch {
+		case limit == 0:
+			limit = dryRunDefaultLimit
+		case limit < 0 || limit > dryRunMaxLimit:
+			fields["limit"] = fmt.Sprintf("must be between 1 and %d", dryRunMaxLimit)
+		}
+		if len(fields) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "details": fields})
+			return
+		}
+
+		source := "request"
+		if len(req.Rules) == 0 {
+			source = "saved"
+			saved, err := repos.PartnerOrderRule.ListByPartnerID(c.Request.Context(), partnerID)
+			if err != nil {
+				logger.Error("Failed to list order rules", zap.String("partner_id", partnerID.String()), zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			rules = saved
+		}
+
+		result, err := service.NewOrderService(repos, logger).DryRunOrderRules(c.Request.Context(), partnerID, rules, from, to, limit)
+		if err != nil {
+			logger.Error("Failed to dry-run order rules", zap.String("partner_id", partnerID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id":   partnerID.String(),
+			"rules_source": source,
+			"from":         from.Format("2006-01-02"),
+			"to":           to.AddDate(0, 0, -1).Format("2006-01-02"),
+			"result":       result,
+		})
+	}
+}
+
+// insertByPriority adds rule after the rules of the same or lower priority.
+func insertByPriority(rules []*domain.PartnerOrderRule, rule *domain.PartnerOrderRule) []*domain.PartnerOrderRule {
+	i := len(rules)
+	for i > 0 && rules[i-1].Priority > rule.Priority {
+		i--
+	}
+	rules = append(rules, nil)
+	copy(rules[i+1:], rules[i:])
+	rules[i] = rule
+	return rules
+}
+
+// loadPartnerForStaff parses a partner_id from a staff request body and checks the partner exists. On failure it
+// writes the error response and returns ok=false.
+func loadPartnerForStaff(c *gin.Context, repos *repository.Repositories, logger *zap.Logger, rawID string) (uuid.UUID, bool) {
+	partnerID, err := uuid.Parse(strings.TrimSpace(rawID))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "validation failed",
+			"details": map[string]string{"partner_id": "must be a UUID"},
+		})
+		return uuid.Nil, false
+	}
+	if _, err := repos.Partner.GetByID(c.Request.Context(), partnerID); err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "partner not found"})
+			return uuid.Nil, false
+		}
+		logger.Error("Failed to get partner", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return uuid.Nil, false
+	}
+	return partnerID, true
+}
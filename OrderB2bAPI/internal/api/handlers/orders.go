@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -29,44 +31,78 @@ func resolveOrderByIDOrPartnerOrderID(ctx context.Context, repos *repository.Rep
 
 // OrderResponse represents the order response
 type OrderResponse struct {
-	ID                  string                 `json:"id"`
-	PartnerOrderID      string                 `json:"partner_order_id"`
-	Status              domain.OrderStatus     `json:"status"`
-	ShopifyDraftOrderID *int64                 `json:"shopify_draft_order_id,omitempty"`
-	ShopifyOrderID      *string                `json:"shopify_order_id,omitempty"`
-	CustomerName        string                 `json:"customer_name"`
-	CustomerPhone       string                 `json:"customer_phone,omitempty"`
-	ShippingAddress     map[string]interface{} `json:"shipping_address"`
-	CartTotal           float64                `json:"cart_total"`
-	PaymentStatus       string                 `json:"payment_status,omitempty"`
-	PaymentMethod       *string                `json:"payment_method,omitempty"`
-	RejectionReason     *string                `json:"rejection_reason,omitempty"`
-	TrackingCarrier     *string                `json:"tracking_carrier,omitempty"`
-	TrackingNumber      *string                `json:"tracking_number,omitempty"`
-	TrackingURL         *string                `json:"tracking_url,omitempty"`
-	Items               []OrderItemResponse    `json:"items"`
-	CreatedAt           string                 `json:"created_at"`
-	UpdatedAt           string                 `json:"updated_at"`
+	ID                  string                  `json:"id"`
+	PartnerOrderID      string                  `json:"partner_order_id"`
+	Status              domain.OrderStatus      `json:"status"`
+	ShopifyDraftOrderID *int64                  `json:"shopify_draft_order_id,omitempty"`
+	ShopifyOrderID      *string                 `json:"shopify_order_id,omitempty"`
+	CustomerName        string                  `json:"customer_name"`
+	CustomerPhone       string                  `json:"customer_phone,omitempty"`
+	ShippingAddress     map[string]interface{}  `json:"shipping_address"`
+	CartTotal           domain.Money            `json:"cart_total"`
+	Currency            string                  `json:"currency"`
+	PaymentStatus       string                  `json:"payment_status,omitempty"`
+	PaymentMethod       *string                 `json:"payment_method,omitempty"`
+	RejectionReason     *string                 `json:"rejection_reason,omitempty"`
+	HoldReason          *string                 `json:"hold_reason,omitempty"`
+	PossibleDuplicateOf *uuid.UUID              `json:"possible_duplicate_of,omitempty"`
+	TrackingCarrier     *string                 `json:"tracking_carrier,omitempty"`
+	TrackingNumber      *string                 `json:"tracking_number,omitempty"`
+	TrackingURL         *string                 `json:"tracking_url,omitempty"`
+	DeliveryWindow      *DeliveryWindowResponse `json:"delivery_window,omitempty"`
+	Items               []OrderItemResponse     `json:"items"`
+	CreatedAt           string                  `json:"created_at"`
+	UpdatedAt           string                  `json:"updated_at"`
+}
+
+// DeliveryWindowResponse is the delivery date/time requested on cart submission
+type DeliveryWindowResponse struct {
+	Date string  `json:"date"`
+	From *string `json:"from,omitempty"`
+	To   *string `json:"to,omitempty"`
+}
+
+func buildDeliveryWindowResponse(w *domain.DeliveryWindow) *DeliveryWindowResponse {
+	if w == nil {
+		return nil
+	}
+	return &DeliveryWindowResponse{Date: w.DateString(), From: w.From, To: w.To}
 }
 
 type OrderItemResponse struct {
-	SKU              string  `json:"sku"`
-	Title            string  `json:"title"`
-	Price            float64 `json:"price"`
-	Quantity         int     `json:"quantity"`
-	ProductURL       *string `json:"product_url,omitempty"`
-	IsSupplierItem   bool    `json:"is_supplier_item"`
-	ShopifyVariantID *int64  `json:"shopify_variant_id,omitempty"`
+	SKU              string       `json:"sku"`
+	Title            string       `json:"title"`
+	Price            domain.Money `json:"price"`
+	Quantity         int          `json:"quantity"`
+	ProductURL       *string      `json:"product_url,omitempty"`
+	IsSupplierItem   bool         `json:"is_supplier_item"`
+	ShopifyVariantID *int64       `json:"shopify_variant_id,omitempty"`
+	// Agreed price from the partner's price list, and the Shopify price it was taken off (when one applied)
+	WholesalePrice *domain.Money `json:"wholesale_price,omitempty"`
+	ListPrice      *domain.Money `json:"list_price,omitempty"`
 	// Enriched from partner catalog (product_title, product_image_url)
 	ProductTitle    *string `json:"product_title,omitempty"`
 	ProductImageURL *string `json:"product_image_url,omitempty"`
+	// Shipped quantity and the shipments (tracking) it went out under
+	FulfilledQuantity int                            `json:"fulfilled_quantity"`
+	Fulfillments      []OrderItemFulfillmentResponse `json:"fulfillments,omitempty"`
+}
+
+type OrderItemFulfillmentResponse struct {
+	Quantity        int     `json:"quantity"`
+	TrackingCarrier *string `json:"tracking_carrier,omitempty"`
+	TrackingNumber  *string `json:"tracking_number,omitempty"`
+	TrackingURL     *string `json:"tracking_url,omitempty"`
+	FulfilledAt     string  `json:"fulfilled_at"`
 }
 
 // shopifyFulfillmentStatusToOrderStatus maps Shopify displayFulfillmentStatus to our domain.OrderStatus.
 // Shopify can return FULFILLED, DELIVERED, IN_TRANSIT, PARTIALLY_FULFILLED, UNFULFILLED, etc.
 func shopifyFulfillmentStatusToOrderStatus(shopifyStatus string) (domain.OrderStatus, bool) {
 	switch strings.ToUpper(strings.TrimSpace(shopifyStatus)) {
-	case "FULFILLED", "PARTIALLY_FULFILLED", "RESTOCKED", "DELIVERED", "IN_TRANSIT", "OUT_FOR_DELIVERY", "PICKED_UP", "IN_PROGRESS", "SUBMITTED", "CONFIRMED", "MARKED_AS_FULFILLED":
+	case "PARTIALLY_FULFILLED":
+		return domain.OrderStatusPartiallyFulfilled, true
+	case "FULFILLED", "RESTOCKED", "DELIVERED", "IN_TRANSIT", "OUT_FOR_DELIVERY", "PICKED_UP", "IN_PROGRESS", "SUBMITTED", "CONFIRMED", "MARKED_AS_FULFILLED":
 		return domain.OrderStatusFulfilled, true
 	case "UNFULFILLED", "PENDING_FULFILLMENT", "OPEN", "SCHEDULED", "ON_HOLD":
 		return domain.OrderStatusUnfulfilled, true
@@ -111,19 +147,25 @@ func HandleGetOrder(cfg *config.Config, repos *repository.Repositories, logger *
 		// Sync status from Shopify when we have a linked Shopify order (so status always reflects Shopify)
 		if order.ShopifyOrderID != nil && *order.ShopifyOrderID != "" {
 			shopifySvc := service.NewShopifyService(cfg.Shopify, repos, logger)
+			orderService := service.NewOrderService(repos, logger)
 			shopifyStatus, tc, tn, tu, syncErr := shopifySvc.GetOrderFulfillmentStatus(c.Request.Context(), *order.ShopifyOrderID)
 			if syncErr != nil {
 				logger.Info("Sync order status from Shopify failed", zap.String("shopify_order_id", *order.ShopifyOrderID), zap.String("partner_order_id", order.PartnerOrderID), zap.Error(syncErr))
 			} else if syncedStatus, ok := shopifyFulfillmentStatusToOrderStatus(shopifyStatus); ok {
-				_ = repos.SupplierOrder.UpdateStatusFromShopify(c.Request.Context(), order.ID, syncedStatus)
-				order.Status = syncedStatus
-				logger.Info("Synced order status from Shopify", zap.String("partner_order_id", order.PartnerOrderID), zap.String("shopify_status", shopifyStatus), zap.String("status", string(syncedStatus)))
+				if err := orderService.SyncStatusFromShopify(c.Request.Context(), order, syncedStatus, "shopify_sync"); err != nil {
+					if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
+						logger.Info("Shopify status not applied, keeping DB status", zap.String("partner_order_id", order.PartnerOrderID), zap.String("shopify_status", shopifyStatus), zap.Error(err))
+					} else {
+						logger.Warn("Failed to store status synced from Shopify", zap.String("partner_order_id", order.PartnerOrderID), zap.Error(err))
+					}
+				} else {
+					logger.Info("Synced order status from Shopify", zap.String("partner_order_id", order.PartnerOrderID), zap.String("shopify_status", shopifyStatus), zap.String("status", string(order.Status)))
+				}
 				// If Shopify has tracking and we don't, persist it
 				if tn != nil && *tn != "" && (order.TrackingNumber == nil || *order.TrackingNumber == "") {
-					_ = repos.SupplierOrder.UpdateTracking(c.Request.Context(), order.ID, tc, tn, tu)
-					order.TrackingCarrier = tc
-					order.TrackingNumber = tn
-					order.TrackingURL = tu
+					if err := orderService.RecordTracking(c.Request.Context(), order, tc, tn, tu, "shopify_sync"); err != nil {
+						logger.Warn("Failed to store tracking synced from Shopify", zap.String("partner_order_id", order.PartnerOrderID), zap.Error(err))
+					}
 				}
 			} else {
 				logger.Debug("Shopify fulfillment status not mapped, keeping DB status", zap.String("shopify_order_id", *order.ShopifyOrderID), zap.String("shopify_status", shopifyStatus))
@@ -138,17 +180,39 @@ func HandleGetOrder(cfg *config.Config, repos *repository.Repositories, logger *
 			return
 		}
 
+		// Per-item shipments, grouped by item
+		fulfillments, err := repos.SupplierOrderItem.ListFulfillmentsByOrderID(c.Request.Context(), order.ID)
+		if err != nil {
+			logger.Error("Failed to get order item fulfillments", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		fulfillmentsByItem := make(map[uuid.UUID][]OrderItemFulfillmentResponse)
+		for _, f := range fulfillments {
+			fulfillmentsByItem[f.SupplierOrderItemID] = append(fulfillmentsByItem[f.SupplierOrderItemID], OrderItemFulfillmentResponse{
+				Quantity:        f.Quantity,
+				TrackingCarrier: f.TrackingCarrier,
+				TrackingNumber:  f.TrackingNumber,
+				TrackingURL:     f.TrackingURL,
+				FulfilledAt:     f.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			})
+		}
+
 		// Build response; enrich items with product details from partner catalog
 		itemResponses := make([]OrderItemResponse, len(items))
 		for i, item := range items {
 			resp := OrderItemResponse{
-				SKU:              item.SKU,
-				Title:            item.Title,
-				Price:            item.Price,
-				Quantity:         item.Quantity,
-				ProductURL:       item.ProductURL,
-				IsSupplierItem:   item.IsSupplierItem,
-				ShopifyVariantID: item.ShopifyVariantID,
+				SKU:               item.SKU,
+				Title:             item.Title,
+				Price:             item.Price,
+				Quantity:          item.Quantity,
+				ProductURL:        item.ProductURL,
+				IsSupplierItem:    item.IsSupplierItem,
+				ShopifyVariantID:  item.ShopifyVariantID,
+				WholesalePrice:    item.WholesalePrice,
+				ListPrice:         item.ListPrice,
+				FulfilledQuantity: item.FulfilledQuantity,
+				Fulfillments:      fulfillmentsByItem[item.ID],
 			}
 			// Enrich from partner_sku_mappings (product_title, product_image_url)
 			if m, err := repos.PartnerSKUMapping.GetBySKUAndPartner(c.Request.Context(), partner.ID, item.SKU); err == nil {
@@ -167,6 +231,7 @@ func HandleGetOrder(cfg *config.Config, repos *repository.Repositories, logger *
 			CustomerName:        order.CustomerName,
 			ShippingAddress:     order.ShippingAddress,
 			CartTotal:           order.CartTotal,
+			Currency:            order.Currency,
 			Items:               itemResponses,
 			CreatedAt:           order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt:           order.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -184,6 +249,10 @@ func HandleGetOrder(cfg *config.Config, repos *repository.Repositories, logger *
 		if order.RejectionReason != nil {
 			response.RejectionReason = order.RejectionReason
 		}
+		if order.HoldReason != nil {
+			response.HoldReason = order.HoldReason
+		}
+		response.PossibleDuplicateOf = order.PossibleDuplicateOf
 		if order.TrackingCarrier != nil {
 			response.TrackingCarrier = order.TrackingCarrier
 		}
@@ -193,7 +262,439 @@ func HandleGetOrder(cfg *config.Config, repos *repository.Repositories, logger *
 		if order.TrackingURL != nil {
 			response.TrackingURL = order.TrackingURL
 		}
+		response.DeliveryWindow = buildDeliveryWindowResponse(order.DeliveryWindow)
 
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+// CancelOrderRequest represents the partner cancel order request
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+// HandleCancelOrder handles POST /v1/orders/:id/cancel
+// Cancels the linked Shopify order (or deletes the draft when it was never completed), then marks our order CANCELED.
+// If Shopify cannot be updated the order is left unchanged so the warehouse never ships a canceled order.
+func HandleCancelOrder(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		idParam := c.Param("id")
+		if idParam == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order ID or partner_order_id required"})
+			return
+		}
+
+		// Body is optional
+		var req CancelOrderRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		order, err := resolveOrderByIDOrPartnerOrderID(c.Request.Context(), repos, partner.ID, idParam)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.PartnerID != partner.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		// Already canceled - idempotent success
+		if order.Status == domain.OrderStatusCanceled {
+			c.JSON(http.StatusOK, gin.H{
+				"id":               order.ID.String(),
+				"partner_order_id": order.PartnerOrderID,
+				"status":           order.Status,
+			})
+			return
+		}
+		if !order.Status.CanTransitionTo(domain.OrderStatusCanceled) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": (&errors.ErrInvalidStateTransition{From: order.Status, To: domain.OrderStatusCanceled}).Error(),
+			})
+			return
+		}
+
+		// Propagate to Shopify first: cancel the order, or delete the draft when only the draft exists
+		shopifyAction := "none"
+		shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
+		var shopifyErr error
+		if order.ShopifyOrderID != nil && *order.ShopifyOrderID != "" {
+			shopifyAction = "order_canceled"
+			note := "Canceled by partner " + partner.Name
+			if req.Reason != "" {
+				note += ": " + req.Reason
+			}
+			shopifyErr = shopifyService.CancelOrder(c.Request.Context(), *order.ShopifyOrderID, note)
+		} else if order.ShopifyDraftOrderID != nil {
+			shopifyAction = "draft_deleted"
+			shopifyErr = shopifyService.DeleteDraftOrder(c.Request.Context(), *order.ShopifyDraftOrderID)
+		}
+		if shopifyErr != nil {
+			logger.Error("Failed to cancel order in Shopify", zap.String("order_id", order.ID.String()), zap.String("action", shopifyAction), zap.Error(shopifyErr))
+			repos.OrderEvent.Create(c.Request.Context(), &domain.OrderEvent{
+				SupplierOrderID: order.ID,
+				EventType:       domain.OrderEventShopifySyncFailed,
+				EventData: map[string]interface{}{
+					"action": shopifyAction,
+					"error":  shopifyErr.Error(),
+				},
+			})
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":   "failed to cancel order in Shopify",
+				"details": shopifyErr.Error(),
+			})
+			return
+		}
+
+		orderService := service.NewOrderService(repos, logger)
+		if err := orderService.CancelOrder(c.Request.Context(), order.ID, req.Reason, shopifyAction); err != nil {
+			if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("Failed to cancel order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel order"})
+			return
+		}
+
+		order, ok = reloadOrder(c, repos, logger, order.ID)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":               order.ID.String(),
+			"partner_order_id": order.PartnerOrderID,
+			"status":           order.Status,
+			"shopify_action":   shopifyAction,
+		})
+
+		// Notify partner webhook if configured (fire-and-forget)
+		if partner.WebhookURL != nil && *partner.WebhookURL != "" {
+			webhookPayload := map[string]interface{}{
+				"partner_id":       partner.ID.String(),
+				"order_id":         order.ID.String(),
+				"partner_order_id": order.PartnerOrderID,
+				"shipping_address": order.ShippingAddress,
+				"status":           order.Status,
+				"reason":           req.Reason,
+				"event":            "order_canceled",
+			}
+			go service.NotifyDeliveryUpdate(*partner.WebhookURL, webhookPayload, logger)
+		}
+	}
+}
+
+// HandleAmendOrder handles PATCH /v1/orders/:id
+// Replaces the items and/or shipping address of an order still awaiting confirmation (INCOMPLETE_CAUTION).
+// Items are re-validated against this partner's catalog. The Shopify order is edited first (or the draft updated
+// when it was never completed); if that fails our order is left unchanged.
+func HandleAmendOrder(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		idParam := c.Param("id")
+		if idParam == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order ID or partner_order_id required"})
+			return
+		}
+
+		var req service.OrderAmendRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+		if req.Items == nil && req.Shipping == nil && req.Totals == nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": "at least one of items, shipping or totals is required",
+			})
+			return
+		}
+
+		order, err := resolveOrderByIDOrPartnerOrderID(c.Request.Context(), repos, partner.ID, idParam)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.PartnerID != partner.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if order.Status != domain.OrderStatusIncompleteCaution {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("order cannot be amended in status %s", order.Status)})
+			return
+		}
+
+		if req.Items != nil && req.Totals == nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": map[string]string{"totals": "required when items change"},
+			})
+			return
+		}
+
+		currentItems, err := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), order.ID)
+		if err != nil {
+			logger.Error("Failed to get order items", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		var amend service.OrderAmendment
+		var partnerItems map[string]*domain.PartnerSKUMapping
+		var priceList *service.PriceList
+		if req.Items != nil {
+			// Re-validate against this partner's catalog, as on cart submit
+			skuService := service.NewSKUService(repos, logger)
+			var hasPartnerSKU bool
+			hasPartnerSKU, partnerItems, err = skuService.CheckCartForPartnerSKUs(c.Request.Context(), partner.ID, req.Items)
+			if err != nil {
+				logger.Error("Failed to check partner SKUs", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			if !hasPartnerSKU {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": "items must contain at least one SKU from your catalog",
+				})
+				return
+			}
+			priceList, err = service.NewPriceListService(repos, logger).Load(c.Request.Context(), partner, time.Now().In(cfg.DeliveryWindow.Location))
+			if err != nil {
+				logger.Error("Failed to load partner price list", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+		}
+		if req.Shipping != nil {
+			// Same gazetteer normalization as on cart submit
+			if _, err := service.NormalizeShippingAddress(req.Shipping, partner.AddressPolicy); err != nil {
+				if e, ok := err.(*errors.ErrValidation); ok {
+					c.JSON(http.StatusUnprocessableEntity, gin.H{
+						"error":   "validation failed",
+						"details": e.Fields,
+					})
+					return
+				}
+				logger.Error("Failed to normalize shipping address", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			email, _ := order.ShippingAddress["email"].(string)
+			amend.ShippingAddress = service.BuildShippingAddress(*req.Shipping, email)
+		}
+
+		// Prices and totals are checked as on cart submit (per the partner's price_policy); new totals without new
+		// items are checked against the items already on the order
+		var priceCheck *service.PriceCheck
+		if req.Totals != nil {
+			if currency := strings.ToUpper(strings.TrimSpace(req.Totals.Currency)); currency != "" && currency != order.Currency {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": map[string]string{"totals.currency": "must be " + order.Currency},
+				})
+				return
+			}
+			cart := service.CartSubmitRequest{Items: req.Items, Totals: *req.Totals}
+			if req.Items == nil {
+				cart.Items = service.CartItemsFromOrderItems(currentItems)
+			}
+			priceCheck, err = service.CheckCartPrices(&cart, partnerItems, priceList, partner.PricePolicy)
+			if err != nil {
+				if e, ok := err.(*errors.ErrValidation); ok {
+					c.JSON(http.StatusUnprocessableEntity, gin.H{
+						"error":   e.Message,
+						"details": e.Fields,
+					})
+					return
+				}
+				logger.Error("Failed to check amended prices", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			if req.Items != nil {
+				req.Items = cart.Items
+			}
+			total := cart.Totals.Total.In(order.Currency)
+			amend.CartTotal = &total
+		}
+
+		// Stock is checked as on cart submit (per the partner's stock_policy); quantities already on the Shopify order
+		// are out of its stock, so only what is added is checked
+		var stockCheck *service.StockCheck
+		shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
+		if req.Items != nil {
+			amend.Items = service.BuildOrderItems(order.ID, req.Items, partnerItems, priceList)
+			stockItems := req.Items
+			if order.ShopifyOrderID != nil && *order.ShopifyOrderID != "" {
+				stockItems = service.AddedCartItems(req.Items, currentItems)
+			}
+			stockCheck, err = shopifyService.CheckCartStock(c.Request.Context(), stockItems, partnerItems, partner.StockPolicy, cfg.StockCacheTTL)
+			if err != nil {
+				if e, ok := err.(*errors.ErrValidation); ok {
+					c.JSON(http.StatusUnprocessableEntity, gin.H{
+						"error":   e.Message,
+						"details": e.Fields,
+					})
+					return
+				}
+				logger.Error("Failed to check amended stock", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+		}
+
+		changes := service.DiffOrderAmendment(order, currentItems, amend)
+		if len(changes) == 0 {
+			c.JSON(http.StatusOK, gin.H{
+				"id":               order.ID.String(),
+				"partner_order_id": order.PartnerOrderID,
+				"status":           order.Status,
+				"changes":          changes,
+				"shopify_action":   "none",
+			})
+			return
+		}
+		_, itemsAdded := changes["items_added"]
+		_, itemsRemoved := changes["items_removed"]
+		_, itemsChanged := changes["items_changed"]
+		itemsDiffer := itemsAdded || itemsRemoved || itemsChanged
+		_, addressDiffers := changes["shipping_address"]
+
+		// Shopify addresses are built from the order, so apply the new address to a copy
+		amended := *order
+		if addressDiffers {
+			amended.ShippingAddress = amend.ShippingAddress
+		}
+
+		// Refuse an amendment over the credit limit before Shopify is edited (AmendOrder checks it again as it stores it)
+		orderService := service.NewOrderService(repos, logger)
+		if amend.CartTotal != nil {
+			if _, err := orderService.CheckAmendmentCredit(c.Request.Context(), partner, order, *amend.CartTotal); err != nil {
+				if e, ok := err.(*errors.ErrCreditLimitExceeded); ok {
+					logger.Info("Amendment rejected: credit limit exceeded", zap.String("order_id", order.ID.String()), zap.String("details", e.Error()))
+					c.JSON(http.StatusPaymentRequired, creditLimitExceededBody(e))
+					return
+				}
+				logger.Error("Failed to check amendment against credit limit", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+		}
+
+		// Propagate to Shopify first: edit the order, or update the draft when only the draft exists
+		shopifyAction := "none"
+		var shopifyErr error
+		if order.ShopifyOrderID != nil && *order.ShopifyOrderID != "" {
+			if itemsDiffer || addressDiffers {
+				shopifyAction = "order_edited"
+			}
+			if itemsDiffer {
+				shopifyErr = shopifyService.EditOrderLineItems(c.Request.Context(), *order.ShopifyOrderID, amend.Items, "Amended by partner "+partner.Name)
+			}
+			if shopifyErr == nil && addressDiffers {
+				shopifyErr = shopifyService.UpdateOrderShippingAddress(c.Request.Context(), *order.ShopifyOrderID, &amended)
+			}
+		} else if order.ShopifyDraftOrderID != nil && (itemsDiffer || addressDiffers) {
+			shopifyAction = "draft_updated"
+			var draftItems []*domain.SupplierOrderItem
+			if itemsDiffer {
+				draftItems = amend.Items
+			}
+			shopifyErr = shopifyService.UpdateDraftOrder(c.Request.Context(), *order.ShopifyDraftOrderID, &amended, draftItems)
+		}
+		if shopifyErr != nil {
+			logger.Error("Failed to amend order in Shopify", zap.String("order_id", order.ID.String()), zap.String("action", shopifyAction), zap.Error(shopifyErr))
+			repos.OrderEvent.Create(c.Request.Context(), &domain.OrderEvent{
+				SupplierOrderID: order.ID,
+				EventType:       domain.OrderEventShopifySyncFailed,
+				EventData: map[string]interface{}{
+					"action": shopifyAction,
+					"error":  shopifyErr.Error(),
+				},
+			})
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":   "failed to amend order in Shopify",
+				"details": shopifyErr.Error(),
+			})
+			return
+		}
+
+		creditCheck, err := orderService.AmendOrder(c.Request.Context(), partner, order.ID, amend, changes, shopifyAction)
+		if err != nil {
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			if e, ok := err.(*errors.ErrCreditLimitExceeded); ok {
+				// Another order took the credit since the check above; Shopify already has the amendment
+				logger.Warn("Amendment rejected after Shopify was updated: credit limit exceeded",
+					zap.String("order_id", order.ID.String()), zap.String("shopify_action", shopifyAction), zap.String("details", e.Error()))
+				c.JSON(http.StatusPaymentRequired, creditLimitExceededBody(e))
+				return
+			}
+			logger.Error("Failed to amend order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to amend order"})
+			return
+		}
+		logger.Info("Order amended by partner", zap.String("order_id", order.ID.String()), zap.String("shopify_action", shopifyAction))
+		orderService.RecordPriceCheck(c.Request.Context(), order, priceCheck)
+		service.ReserveStock(stockCheck)
+		if err := orderService.RecordStockCheck(c.Request.Context(), order, stockCheck); err != nil {
+			logger.Warn("Failed to hold amended order for insufficient stock", zap.String("order_id", order.ID.String()), zap.Error(err))
+		}
+		if err := orderService.RecordCreditCheck(c.Request.Context(), order, creditCheck); err != nil {
+			logger.Warn("Failed to hold amended order over credit limit", zap.String("order_id", order.ID.String()), zap.Error(err))
+		}
+
+		order, ok = reloadOrder(c, repos, logger, order.ID)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":               order.ID.String(),
+			"partner_order_id": order.PartnerOrderID,
+			"status":           order.Status,
+			"changes":          changes,
+			"shopify_action":   shopifyAction,
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+func buildPartnerPriceResponse(price *domain.PartnerPrice, today time.Time) gin.H {
+	resp := gin.H{
+		"id":                price.ID.String(),
+		"partner_id":        price.PartnerID.String(),
+		"sku":               price.SKU,
+		"collection_handle": price.CollectionHandle,
+		"fixed_price":       price.FixedPrice,
+		"discount_percent":  price.DiscountPercent,
+		"valid_from":        nil,
+		"valid_to":          nil,
+		"active":            price.ActiveOn(today),
+		"created_at":        price.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if price.ValidFrom != nil {
+		resp["valid_from"] = price.ValidFrom.Format("2006-01-02")
+	}
+	if price.ValidTo != nil {
+		resp["valid_to"] = price.ValidTo.Format("2006-01-02")
+	}
+	return resp
+}
+
+// HandleListPartnerPrices handles GET /v1/supplier/price-lists?partner_id= (all of the partner's price rules,
+// with whether each is in effect today)
+func HandleListPartnerPrices(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		partnerID, err := uuid.Parse(strings.TrimSpace(c.Query("partner_id")))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "partner_id must be a UUID"})
+			return
+		}
+
+		prices, err := repos.PartnerPrice.ListByPartnerID(c.Request.Context(), partnerID)
+		if err != nil {
+			logger.Error("Failed to list partner prices", zap.String("partner_id", partnerID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		today := time.Now().In(cfg.DeliveryWindow.Location)
+		responses := make([]gin.H, len(prices))
+		for i, price := range prices {
+			responses[i] = buildPartnerPriceResponse(price, today)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id": partnerID.String(),
+			"prices":     responses,
+		})
+	}
+}
+
+// HandleCreatePartnerPrice handles POST /v1/supplier/price-lists (adds a rule to a partner's price list)
+func HandleCreatePartnerPrice(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var req service.PartnerPriceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+		partnerID, err := uuid.Parse(strings.TrimSpace(req.PartnerID))
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": map[string]string{"partner_id": "must be a UUID"},
+			})
+			return
+		}
+		if _, err := repos.Partner.GetByID(c.Request.Context(), partnerID); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "partner not found"})
+				return
+			}
+			logger.Error("Failed to get partner", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		price, err := service.ParsePartnerPrice(&req, partnerID)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "details": e.Fields})
+				return
+			}
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "details": err.Error()})
+			return
+		}
+		if err := repos.PartnerPrice.Create(c.Request.Context(), price); err != nil {
+			logger.Error("Failed to create partner price", zap.String("partner_id", partnerID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create price list rule"})
+			return
+		}
+
+		logger.Info("Partner price list rule created", zap.String("partner_id", partnerID.String()), zap.String("price_id", price.ID.String()))
+		c.JSON(http.StatusCreated, buildPartnerPriceResponse(price, time.Now().In(cfg.DeliveryWindow.Location)))
+	}
+}
+
+// HandleDeletePartnerPrice handles DELETE /v1/supplier/price-lists/:id
+func HandleDeletePartnerPrice(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid price list rule ID"})
+			return
+		}
+
+		if err := repos.PartnerPrice.Delete(c.Request.Context(), id); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "price list rule not found"})
+				return
+			}
+			logger.Error("Failed to delete partner price", zap.String("price_id", id.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// ApproveReturnRequest represents approve return request. Restock defaults to true.
+type ApproveReturnRequest struct {
+	Restock *bool  `json:"restock,omitempty"`
+	Note    string `json:"note"`
+}
+
+// RejectReturnRequest represents reject return request
+type RejectReturnRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// buildReturnResponse renders a return with its items' SKUs/titles (from the order's items).
+func buildReturnResponse(ret *domain.OrderReturn, orderItems []*domain.SupplierOrderItem) gin.H {
+	itemsByID := make(map[uuid.UUID]*domain.SupplierOrderItem, len(orderItems))
+	for _, item := range orderItems {
+		itemsByID[item.ID] = item
+	}
+	items := make([]gin.H, 0, len(ret.Items))
+	for _, ri := range ret.Items {
+		entry := gin.H{"quantity": ri.Quantity}
+		if item, ok := itemsByID[ri.SupplierOrderItemID]; ok {
+			entry["sku"] = item.SKU
+			entry["title"] = item.Title
+		}
+		items = append(items, entry)
+	}
+
+	resp := gin.H{
+		"id":         ret.ID.String(),
+		"order_id":   ret.SupplierOrderID.String(),
+		"status":     ret.Status,
+		"source":     ret.Source,
+		"reason":     ret.Reason,
+		"note":       ret.Note,
+		"restock":    ret.Restock,
+		"items":      items,
+		"created_at": ret.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if ret.WasselStatus != nil {
+		resp["wassel_status"] = *ret.WasselStatus
+	}
+	if ret.DecisionNote != nil {
+		resp["decision_note"] = *ret.DecisionNote
+	}
+	if ret.DecidedAt != nil {
+		resp["decided_at"] = ret.DecidedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
+}
+
+// notifyPartnerReturnEvent sends a return event to the order's partner webhook if configured (fire-and-forget).
+func notifyPartnerReturnEvent(ctx context.Context, repos *repository.Repositories, order *domain.SupplierOrder, returnResp gin.H, event string, logger *zap.Logger) {
+	partner, err := repos.Partner.GetByID(ctx, order.PartnerID)
+	if err != nil {
+		logger.Warn("Return webhook: partner lookup failed, skipping", zap.String("order_id", order.ID.String()), zap.Error(err))
+		return
+	}
+	if partner.WebhookURL == nil || *partner.WebhookURL == "" {
+		return
+	}
+	webhookPayload := map[string]interface{}{
+		"partner_id":       partner.ID.String(),
+		"order_id":         order.ID.String(),
+		"partner_order_id": order.PartnerOrderID,
+		"shipping_address": order.ShippingAddress,
+		"return":           returnResp,
+		"event":            event,
+	}
+	go service.NotifyDeliveryUpdate(*partner.WebhookURL, webhookPayload, logger)
+}
+
+// HandleCreateReturn handles POST /v1/orders/:id/returns
+// Opens a return for a shipped order; the order moves to RETURN_IN_PROGRESS until supplier staff decide.
+func HandleCreateReturn(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		idParam := c.Param("id")
+		if idParam == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order ID or partner_order_id required"})
+			return
+		}
+
+		var req service.ReturnRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+		reason := domain.ReturnReason(req.Reason)
+		if !reason.IsValid() {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": "invalid reason",
+			})
+			return
+		}
+
+		order, err := resolveOrderByIDOrPartnerOrderID(c.Request.Context(), repos, partner.ID, idParam)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.PartnerID != partner.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if !order.Status.CanTransitionTo(domain.OrderStatusReturnInProgress) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": (&errors.ErrInvalidStateTransition{From: order.Status, To: domain.OrderStatusReturnInProgress}).Error(),
+			})
+			return
+		}
+
+		orderItems, err := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), order.ID)
+		if err != nil {
+			logger.Error("Failed to get order items", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		items, err := service.BuildReturnItems(orderItems, req.Items)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   e.Error(),
+					"details": e.Fields,
+				})
+				return
+			}
+			logger.Error("Failed to build return items", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		ret := &domain.OrderReturn{
+			Source: "partner",
+			Reason: reason,
+			Items:  items,
+		}
+		if req.Note != "" {
+			ret.Note = &req.Note
+		}
+		returnService := service.NewReturnService(repos, logger)
+		if err := returnService.OpenReturn(c.Request.Context(), order, ret); err != nil {
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("Failed to open return", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open return"})
+			return
+		}
+		logger.Info("Return opened by partner", zap.String("order_id", order.ID.String()), zap.String("return_id", ret.ID.String()))
+
+		c.JSON(http.StatusCreated, buildReturnResponse(ret, orderItems))
+	}
+}
+
+// HandleListOrderReturns handles GET /v1/orders/:id/returns
+func HandleListOrderReturns(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		idParam := c.Param("id")
+		if idParam == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order ID or partner_order_id required"})
+			return
+		}
+		order, err := resolveOrderByIDOrPartnerOrderID(c.Request.Context(), repos, partner.ID, idParam)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.PartnerID != partner.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		returns, err := repos.OrderReturn.ListByOrderID(c.Request.Context(), order.ID)
+		if err != nil {
+			logger.Error("Failed to list order returns", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		orderItems, err := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), order.ID)
+		if err != nil {
+			logger.Error("Failed to get order items", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		returnResponses := make([]gin.H, len(returns))
+		for i, ret := range returns {
+			returnResponses[i] = buildReturnResponse(ret, orderItems)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"order_id":         order.ID.String(),
+			"partner_order_id": order.PartnerOrderID,
+			"returns":          returnResponses,
+		})
+	}
+}
+
+// HandleListSupplierReturns handles GET /v1/supplier/returns (all partners, filtered by status; default REQUESTED).
+func HandleListSupplierReturns(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		status := domain.ReturnStatus(c.DefaultQuery("status", string(domain.ReturnStatusRequested)))
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 50
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		returns, err := repos.OrderReturn.ListByStatus(c.Request.Context(), status, limit, offset)
+		if err != nil {
+			logger.Error("Failed to list returns", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		returnResponses := make([]gin.H, 0, len(returns))
+		for _, ret := range returns {
+			orderItems, err := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), ret.SupplierOrderID)
+			if err != nil {
+				logger.Error("Failed to get order items", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			returnResponses = append(returnResponses, buildReturnResponse(ret, orderItems))
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"returns": returnResponses,
+			"status":  status,
+			"limit":   limit,
+			"offset":  offset,
+		})
+	}
+}
+
+// loadReturnForStaff resolves the :id return for a staff request and writes the error response when it fails.
+func loadReturnForStaff(c *gin.Context, repos *repository.Repositories, logger *zap.Logger) (*domain.OrderReturn, bool) {
+	returnID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid return ID"})
+		return nil, false
+	}
+	ret, err := repos.OrderReturn.GetByID(c.Request.Context(), returnID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "return not found"})
+			return nil, false
+		}
+		logger.Error("Failed to get return", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+	return ret, true
+}
+
+// HandleApproveReturn handles POST /v1/supplier/returns/:id/approve
+// With restock (default true), returned items are restocked in Shopify first; if that fails the return stays open.
+func HandleApproveReturn(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, ok := middleware.GetStaffFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Body is optional
+		var req ApproveReturnRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+		restock := req.Restock == nil || *req.Restock
+
+		ret, ok := loadReturnForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+		if ret.Status != domain.ReturnStatusRequested {
+			c.JSON(http.StatusConflict, gin.H{"error": "return already " + string(ret.Status)})
+			return
+		}
+
+		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), ret.SupplierOrderID)
+		if err != nil {
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		orderItems, err := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), order.ID)
+		if err != nil {
+			logger.Error("Failed to get order items", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		// Restock in Shopify first so an approved return always has its stock back
+		if restock && order.ShopifyOrderID != nil && *order.ShopifyOrderID != "" {
+			itemsByID := make(map[uuid.UUID]*domain.SupplierOrderItem, len(orderItems))
+			for _, item := range orderItems {
+				itemsByID[item.ID] = item
+			}
+			var lines []service.ReturnedLine
+			for _, ri := range ret.Items {
+				if item, ok := itemsByID[ri.SupplierOrderItemID]; ok {
+					lines = append(lines, service.ReturnedLine{Item: item, Quantity: ri.Quantity})
+				}
+			}
+			shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
+			if err := shopifyService.RestockReturn(c.Request.Context(), *order.ShopifyOrderID, lines, "Return "+ret.ID.String()+" approved by "+staff.Name); err != nil {
+				logger.Error("Failed to restock return in Shopify", zap.String("return_id", ret.ID.String()), zap.Error(err))
+				repos.OrderEvent.Create(c.Request.Context(), &domain.OrderEvent{
+					SupplierOrderID: order.ID,
+					EventType:       domain.OrderEventShopifySyncFailed,
+					EventData: map[string]interface{}{
+						"action":    "return_restock",
+						"return_id": ret.ID.String(),
+						"error":     err.Error(),
+					},
+				})
+				c.JSON(http.StatusBadGateway, gin.H{
+					"error":   "failed to restock return in Shopify",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		returnService := service.NewReturnService(repos, logger)
+		ret, err = returnService.ApproveReturn(c.Request.Context(), ret.ID, staff.ID, restock, req.Note)
+		if err != nil {
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("Failed to approve return", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to approve return"})
+			return
+		}
+		logger.Info("Return approved by supplier staff", zap.String("return_id", ret.ID.String()), zap.String("staff_id", staff.ID.String()))
+
+		resp := buildReturnResponse(ret, orderItems)
+		c.JSON(http.StatusOK, resp)
+
+		// Response already sent; without the updated order the partner webhook is skipped
+		order, err = repos.SupplierOrder.GetByID(c.Request.Context(), order.ID)
+		if err != nil {
+			logger.Warn("Approve return: order reload failed, skipping webhook", zap.String("order_id", ret.SupplierOrderID.String()), zap.Error(err))
+			return
+		}
+		notifyPartnerReturnEvent(c.Request.Context(), repos, order, resp, "return_approved", logger)
+	}
+}
+
+// HandleRejectReturn handles POST /v1/supplier/returns/:id/reject
+func HandleRejectReturn(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, ok := middleware.GetStaffFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var req RejectReturnRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		ret, ok := loadReturnForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		returnService := service.NewReturnService(repos, logger)
+		ret, err := returnService.RejectReturn(c.Request.Context(), ret.ID, staff.ID, req.Reason)
+		if err != nil {
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("Failed to reject return", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reject return"})
+			return
+		}
+		logger.Info("Return rejected by supplier staff", zap.String("return_id", ret.ID.String()), zap.String("staff_id", staff.ID.String()))
+
+		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), ret.SupplierOrderID)
+		if err != nil {
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		orderItems, _ := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), order.ID)
+
+		resp := buildReturnResponse(ret, orderItems)
+		c.JSON(http.StatusOK, resp)
+
+		notifyPartnerReturnEvent(c.Request.Context(), repos, order, resp, "return_rejected", logger)
+	}
+}
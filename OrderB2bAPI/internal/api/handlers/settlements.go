@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+func buildCODEntryResponse(entry *domain.CODLedgerEntry) gin.H {
+	resp := gin.H{
+		"order_id":         entry.SupplierOrderID.String(),
+		"partner_order_id": entry.PartnerOrderID,
+		"type":             entry.EntryType,
+		"amount":           entry.Amount,
+		"waybill":          entry.Waybill,
+		"remitted":         entry.RemittanceID != nil,
+		"occurred_at":      entry.OccurredAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if entry.OrderReturnID != nil {
+		resp["return_id"] = entry.OrderReturnID.String()
+	}
+	return resp
+}
+
+// writeSettlement renders the partner's COD settlement for ?period= (YYYY-MM, default current month).
+func writeSettlement(c *gin.Context, cfg *config.Config, repos *repository.Repositories, logger *zap.Logger, partnerID uuid.UUID) {
+	from, to, err := service.ParseSettlementPeriod(strings.TrimSpace(c.Query("period")), cfg.DeliveryWindow.Location, time.Now())
+	if err != nil {
+		if e, ok := err.(*errors.ErrValidation); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": e.Message, "details": e.Fields})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codService := service.NewCODService(repos, logger)
+	settlement, err := codService.GetSettlement(c.Request.Context(), partnerID, from, to)
+	if err != nil {
+		logger.Error("Failed to load COD settlement", zap.String("partner_id", partnerID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	entries := make([]gin.H, len(settlement.Entries))
+	for i, entry := range settlement.Entries {
+		entries[i] = buildCODEntryResponse(entry)
+	}
+	totals := settlement.Totals
+	c.JSON(http.StatusOK, gin.H{
+		"partner_id": partnerID.String(),
+		"period":     settlement.Period,
+		"from":       settlement.From.Format("2006-01-02T15:04:05Z07:00"),
+		"to":         settlement.To.Format("2006-01-02T15:04:05Z07:00"),
+		"delivered": gin.H{
+			"orders": totals.CollectedCount,
+			"amount": totals.Collected,
+		},
+		"returns": gin.H{
+			"count":  totals.ReturnedCount,
+			"amount": totals.Returned,
+		},
+		"net_payable":         settlement.NetPayable(),
+		"remitted_by_courier": totals.Remitted,
+		"awaiting_remittance": settlement.AwaitingRemittance(),
+		"entries":             entries,
+	})
+}
+
+// HandleGetSettlement handles GET /v1/settlements?period=YYYY-MM (partner's COD settlement for the month)
+func HandleGetSettlement(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		writeSettlement(c, cfg, repos, logger, partner.ID)
+	}
+}
+
+// HandleGetSupplierSettlement handles GET /v1/supplier/settlements?partner_id=&period=YYYY-MM
+func HandleGetSupplierSettlement(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		partnerID, err := uuid.Parse(strings.TrimSpace(c.Query("partner_id")))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "partner_id must be a UUID"})
+			return
+		}
+		if _, err := repos.Partner.GetByID(c.Request.Context(), partnerID); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "partner not found"})
+				return
+			}
+			logger.Error("Failed to get partner", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		writeSettlement(c, cfg, repos, logger, partnerID)
+	}
+}
+
+// HandleCreateCODRemittance handles POST /v1/supplier/cod/remittances (records a Wassel COD payout and links it
+// to the collected waybills it covers)
+func HandleCreateCODRemittance(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, ok := middleware.GetStaffFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var req service.CODRemittanceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		remittance := &domain.CODRemittance{
+			Reference:         strings.TrimSpace(req.Reference),
+			Amount:            req.Amount,
+			RemittedAt:        time.Now(),
+			RecordedByStaffID: &staff.ID,
+		}
+		if req.RemittedAt != "" {
+			remittedAt, err := time.Parse(time.RFC3339, req.RemittedAt)
+			if err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": map[string]string{"remitted_at": "must be an RFC 3339 timestamp"},
+				})
+				return
+			}
+			remittance.RemittedAt = remittedAt
+		}
+		if note := strings.TrimSpace(req.Note); note != "" {
+			remittance.Note = &note
+		}
+		waybills := make([]string, 0, len(req.Waybills))
+		for _, waybill := range req.Waybills {
+			if waybill = strings.TrimSpace(waybill); waybill != "" {
+				waybills = append(waybills, waybill)
+			}
+		}
+
+		codService := service.NewCODService(repos, logger)
+		linked, unmatched, err := codService.RecordRemittance(c.Request.Context(), remittance, waybills)
+		if err != nil {
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("Failed to record COD remittance", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record remittance"})
+			return
+		}
+
+		var linkedAmount domain.Money
+		entries := make([]gin.H, len(linked))
+		for i, entry := range linked {
+			linkedAmount = linkedAmount.Add(entry.Amount)
+			entries[i] = buildCODEntryResponse(entry)
+		}
+		c.JSON(http.StatusCreated, gin.H{
+			"id":                 remittance.ID.String(),
+			"reference":          remittance.Reference,
+			"amount":             remittance.Amount,
+			"remitted_at":        remittance.RemittedAt.Format("2006-01-02T15:04:05Z07:00"),
+			"linked_count":       len(linked),
+			"linked_amount":      linkedAmount,
+			"difference":         remittance.Amount.Sub(linkedAmount),
+			"unmatched_waybills": unmatched,
+			"entries":            entries,
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 )
 
 type shopifyFulfillmentWebhookBody struct {
+	ID        int64  `json:"id"`
 	OrderID   int64  `json:"order_id"`
 	OrderName string `json:"order_name"`
 	Name      string `json:"name"`
@@ -29,6 +30,14 @@ type shopifyFulfillmentWebhookBody struct {
 	TrackingNumber  string `json:"tracking_number"`
 	TrackingCompany string `json:"tracking_company"`
 	TrackingURL     string `json:"tracking_url"`
+
+	LineItems []shopifyFulfillmentLineItem `json:"line_items"`
+}
+
+type shopifyFulfillmentLineItem struct {
+	VariantID *int64 `json:"variant_id"`
+	Title     string `json:"title"`
+	Quantity  int    `json:"quantity"`
 }
 
 func verifyShopifyHMAC(secret string, body []byte, header string) bool {
@@ -46,7 +55,8 @@ func verifyShopifyHMAC(secret string, body []byte, header string) bool {
 // Configure Shopify webhook topics:
 // - fulfillments/create
 // - fulfillments/update
-// This updates supplier_orders.status to FULFILLED and stores tracking info when provided.
+// Fulfilled line items are recorded per order item (with the fulfillment's tracking), and the order moves to
+// PARTIALLY_FULFILLED or FULFILLED depending on what has shipped. Tracking info is stored when provided.
 func HandleShopifyFulfillmentWebhook(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		secret := strings.TrimSpace(cfg.ShopifyWebhookSecret)
@@ -107,24 +117,57 @@ func HandleShopifyFulfillmentWebhook(cfg *config.Config, repos *repository.Repos
 			return
 		}
 
-		// Mark as fulfilled (webhook is fulfillment-related)
-		_ = repos.SupplierOrder.UpdateStatusFromShopify(c.Request.Context(), order.ID, domain.OrderStatusFulfilled)
+		orderService := service.NewOrderService(repos, logger)
 
 		// Persist tracking when provided; keep existing fields when not provided
+		carrier := order.TrackingCarrier
+		url := order.TrackingURL
+		var num *string
 		trackingNumber := strings.TrimSpace(body.TrackingNumber)
 		if trackingNumber != "" {
-			carrier := order.TrackingCarrier
 			if strings.TrimSpace(body.TrackingCompany) != "" {
 				s := strings.TrimSpace(body.TrackingCompany)
 				carrier = &s
 			}
-			url := order.TrackingURL
 			if strings.TrimSpace(body.TrackingURL) != "" {
 				s := strings.TrimSpace(body.TrackingURL)
 				url = &s
 			}
-			num := trackingNumber
-			_ = repos.SupplierOrder.UpdateTracking(c.Request.Context(), order.ID, carrier, &num, url)
+			num = &trackingNumber
+			if err := orderService.RecordTracking(c.Request.Context(), order, carrier, num, url, "shopify_webhook"); err != nil {
+				logger.Error("Shopify webhook: failed to store tracking", zap.String("shopify_order_name", orderName), zap.Error(err))
+			}
+		}
+
+		switch fulfillmentStatus := strings.ToLower(strings.TrimSpace(body.Status)); {
+		case fulfillmentStatus == "cancelled" || fulfillmentStatus == "error" || fulfillmentStatus == "failure":
+			logger.Info("Shopify webhook: fulfillment not shipped, skipping", zap.String("shopify_order_name", orderName), zap.String("fulfillment_status", fulfillmentStatus))
+		case body.ID == 0 || len(body.LineItems) == 0:
+			// No line items to attribute; treat the fulfillment as covering the whole order
+			if err := orderService.SyncStatusFromShopify(c.Request.Context(), order, domain.OrderStatusFulfilled, "shopify_webhook"); err != nil {
+				if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
+					logger.Info("Shopify webhook: status not applied", zap.String("shopify_order_name", orderName), zap.Error(err))
+				} else {
+					logger.Error("Shopify webhook: failed to update status", zap.String("shopify_order_name", orderName), zap.Error(err))
+				}
+			}
+		default:
+			fulfillment := service.ShopifyFulfillment{ID: body.ID}
+			if num != nil {
+				fulfillment.TrackingCarrier = carrier
+				fulfillment.TrackingNumber = num
+				fulfillment.TrackingURL = url
+			}
+			for _, li := range body.LineItems {
+				fulfillment.LineItems = append(fulfillment.LineItems, service.ShopifyFulfillmentLine{
+					VariantID: li.VariantID,
+					Title:     li.Title,
+					Quantity:  li.Quantity,
+				})
+			}
+			if _, err := orderService.ApplyShopifyFulfillment(c.Request.Context(), order.ID, fulfillment); err != nil {
+				logger.Error("Shopify webhook: failed to apply fulfillment", zap.String("shopify_order_name", orderName), zap.Int64("fulfillment_id", body.ID), zap.Error(err))
+			}
 		}
 
 		c.JSON(http.StatusOK, gin.H{
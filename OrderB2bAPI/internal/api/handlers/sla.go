@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+func buildSLABreachResponse(b *domain.SLABreach, now time.Time) gin.H {
+	// Overdue until the breach was resolved, or until now while it is open
+	end := now
+	if b.ResolvedAt != nil {
+		end = *b.ResolvedAt
+	}
+	overdue := end.Sub(b.Since) - time.Duration(b.ThresholdMinutes)*time.Minute
+
+	resp := gin.H{
+		"id":                b.ID.String(),
+		"supplier_order_id": b.SupplierOrderID.String(),
+		"partner_order_id":  b.PartnerOrderID,
+		"partner_id":        b.PartnerID.String(),
+		"order_status":      b.OrderStatus,
+		"since":             b.Since.Format("2006-01-02T15:04:05Z07:00"),
+		"threshold_minutes": b.ThresholdMinutes,
+		"overdue_minutes":   int(overdue / time.Minute),
+		"summary":           service.SLABreachSummary(b, end),
+		"detected_at":       b.DetectedAt.Format("2006-01-02T15:04:05Z07:00"),
+		"alerted_at":        nil,
+		"resolved_at":       nil,
+	}
+	if b.DeliveryStatus != nil {
+		resp["delivery_status"] = *b.DeliveryStatus
+		resp["delivery_status_label"] = wasselStatusLabel(*b.DeliveryStatus)
+	}
+	if b.AlertedAt != nil {
+		resp["alerted_at"] = b.AlertedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if b.ResolvedAt != nil {
+		resp["resolved_at"] = b.ResolvedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
+}
+
+// HandleListSLABreaches handles GET /v1/supplier/sla-breaches (orders found stuck in a status past their SLA
+// threshold, most recent first; state open (default), resolved or all, optionally for one partner_id)
+func HandleListSLABreaches(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		filter := repository.SLABreachFilter{}
+		state := c.DefaultQuery("state", "open")
+		switch state {
+		case "open", "resolved":
+			open := state == "open"
+			filter.Open = &open
+		case "all":
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "state must be open, resolved or all"})
+			return
+		}
+		if v := strings.TrimSpace(c.Query("partner_id")); v != "" {
+			partnerID, err := uuid.Parse(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "partner_id must be a UUID"})
+				return
+			}
+			filter.PartnerID = &partnerID
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 50
+		}
+		filter.Limit = limit
+
+		breaches, err := repos.SLABreach.List(c.Request.Context(), filter)
+		if err != nil {
+			logger.Error("Failed to list SLA breaches", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		now := time.Now()
+		responses := make([]gin.H, len(breaches))
+		for i, b := range breaches {
+			responses[i] = buildSLABreachResponse(b, now)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"breaches": responses,
+			"state":    state,
+			"limit":    limit,
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// ConfirmOrderRequest represents confirm order request
+type ConfirmOrderRequest struct {
+	// Empty for now, can add fields later
+}
+
+// RejectOrderRequest represents reject order request
+type RejectOrderRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ShipOrderRequest represents ship order request
+type ShipOrderRequest struct {
+	Carrier        string  `json:"carrier" binding:"required"`
+	TrackingNumber string  `json:"tracking_number" binding:"required"`
+	TrackingURL    *string `json:"tracking_url,omitempty"`
+}
+
+// resolveOrderForStaff fetches an order for supplier staff (not scoped to a partner).
+// idParam may be our order UUID; otherwise, with ?partner_id= it is that partner's partner_order_id,
+// and without it we try the Shopify order number (e.g. "1033") and then partner_order_id.
+func resolveOrderForStaff(ctx context.Context, repos *repository.Repositories, idParam, partnerIDParam string) (*domain.SupplierOrder, error) {
+	if orderID, err := uuid.Parse(idParam); err == nil {
+		return repos.SupplierOrder.GetByID(ctx, orderID)
+	}
+	if partnerIDParam != "" {
+		partnerID, err := uuid.Parse(partnerIDParam)
+		if err != nil {
+			return nil, &errors.ErrValidation{Message: "invalid partner_id"}
+		}
+		return repos.SupplierOrder.GetByPartnerIDAndPartnerOrderID(ctx, partnerID, idParam)
+	}
+	order, err := repos.SupplierOrder.GetByShopifyOrderID(ctx, idParam)
+	if _, ok := err.(*errors.ErrNotFound); ok {
+		return repos.SupplierOrder.GetByPartnerOrderID(ctx, idParam)
+	}
+	return order, err
+}
+
+// loadOrderForStaff resolves the :id order for a staff request and writes the error response when it fails.
+func loadOrderForStaff(c *gin.Context, repos *repository.Repositories, logger *zap.Logger) (*domain.SupplierOrder, bool) {
+	idParam := c.Param("id")
+	if idParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order ID, shopify order number or partner_order_id required"})
+		return nil, false
+	}
+	order, err := resolveOrderForStaff(c.Request.Context(), repos, idParam, c.Query("partner_id"))
+	if err != nil {
+		switch e := err.(type) {
+		case *errors.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		case *errors.ErrValidation:
+			c.JSON(http.StatusBadRequest, gin.H{"error": e.Error()})
+		default:
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return nil, false
+	}
+	return order, true
+}
+
+// reloadOrder re-reads an order after a change to build the response. When that fails it writes a 500 (the change
+// itself was made) and reports false.
+func reloadOrder(c *gin.Context, repos *repository.Repositories, logger *zap.Logger, orderID uuid.UUID) (*domain.SupplierOrder, bool) {
+	order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+	if err != nil {
+		logger.Error("Failed to reload order", zap.String("order_id", orderID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "order was updated but could not be reloaded"})
+		return nil, false
+	}
+	return order, true
+}
+
+// HandleConfirmOrder handles POST /v1/supplier/orders/:id/confirm
+// An order held as a possible duplicate was kept out of Shopify and is synced now.
+func HandleConfirmOrder(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, ok := middleware.GetStaffFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		order, ok := loadOrderForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// Confirm order
+		orderService := service.NewOrderService(repos, logger)
+		if err := orderService.ConfirmOrder(c.Request.Context(), order.ID); err != nil {
+			if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("Failed to confirm order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to confirm order"})
+			return
+		}
+		logger.Info("Order confirmed by supplier staff", zap.String("order_id", order.ID.String()), zap.String("staff_id", staff.ID.String()))
+
+		// Get updated order
+		order, ok = reloadOrder(c, repos, logger, order.ID)
+		if !ok {
+			return
+		}
+
+		body := gin.H{
+			"id":         order.ID.String(),
+			"partner_id": order.PartnerID.String(),
+			"status":     order.Status,
+		}
+		if order.PossibleDuplicateOf != nil && order.ShopifyOrderID == nil {
+			partner, err := repos.Partner.GetByID(c.Request.Context(), order.PartnerID)
+			if err != nil {
+				logger.Error("Failed to get partner for Shopify sync", zap.String("order_id", order.ID.String()), zap.Error(err))
+				body["shopify_error"] = "get partner: " + err.Error()
+			} else {
+				resp := buildCartSubmitResponseWithShopifySync(c.Request.Context(), order, partner, repos, cfg, logger)
+				body["shopify_draft_order_id"] = resp.ShopifyDraftOrderID
+				body["shopify_order_id"] = resp.ShopifyOrderID
+				if resp.ShopifyError != "" {
+					body["shopify_error"] = resp.ShopifyError
+				}
+			}
+		}
+		c.JSON(http.StatusOK, body)
+	}
+}
+
+// HandleRejectOrder handles POST /v1/supplier/orders/:id/reject
+func HandleRejectOrder(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, ok := middleware.GetStaffFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req RejectOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		order, ok := loadOrderForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// Reject order
+		orderService := service.NewOrderService(repos, logger)
+		if err := orderService.RejectOrder(c.Request.Context(), order.ID, req.Reason); err != nil {
+			if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("Failed to reject order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reject order"})
+			return
+		}
+		logger.Info("Order rejected by supplier staff", zap.String("order_id", order.ID.String()), zap.String("staff_id", staff.ID.String()))
+
+		// Get updated order
+		order, ok = reloadOrder(c, repos, logger, order.ID)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":               order.ID.String(),
+			"partner_id":       order.PartnerID.String(),
+			"status":           order.Status,
+			"rejection_reason": order.RejectionReason,
+		})
+	}
+}
+
+// HandleShipOrder handles POST /v1/supplier/orders/:id/ship
+func HandleShipOrder(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, ok := middleware.GetStaffFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req ShipOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		order, ok := loadOrderForStaff(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// Ship order
+		orderService := service.NewOrderService(repos, logger)
+		if err := orderService.ShipOrder(c.Request.Context(), order.ID, req.Carrier, req.TrackingNumber, req.TrackingURL); err != nil {
+			if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			logger.Error("Failed to ship order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to ship order"})
+			return
+		}
+		logger.Info("Order shipped by supplier staff", zap.String("order_id", order.ID.String()), zap.String("staff_id", staff.ID.String()))
+
+		// Get updated order
+		order, ok = reloadOrder(c, repos, logger, order.ID)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":               order.ID.String(),
+			"partner_id":       order.PartnerID.String(),
+			"status":           order.Status,
+			"tracking_carrier": order.TrackingCarrier,
+			"tracking_number":  order.TrackingNumber,
+			"tracking_url":     order.TrackingURL,
+		})
+
+		// Notify the owning partner's webhook if configured (fire-and-forget)
+		partner, err := repos.Partner.GetByID(c.Request.Context(), order.PartnerID)
+		if err != nil {
+			logger.Warn("Ship order: partner lookup failed, skipping webhook", zap.String("order_id", order.ID.String()), zap.Error(err))
+			return
+		}
+		if partner.WebhookURL != nil && *partner.WebhookURL != "" {
+			shipmentPayload := map[string]interface{}{
+				"tracking_carrier": order.TrackingCarrier,
+				"tracking_number":  order.TrackingNumber,
+				"tracking_url":     order.TrackingURL,
+			}
+			webhookPayload := map[string]interface{}{
+				"partner_id":       partner.ID.String(),
+				"order_id":         order.ID.String(),
+				"partner_order_id": order.PartnerOrderID,
+				"shipping_address": order.ShippingAddress,
+				"shipment":         shipmentPayload,
+				"event":            "order_shipped",
+			}
+			go service.NotifyDeliveryUpdate(*partner.WebhookURL, webhookPayload, logger)
+		}
+	}
+}
+
+// HandleListSupplierOrders handles GET /v1/supplier/orders (all partners; same filters as /v1/admin/orders plus partner_id).
+// Defaults to INCOMPLETE_CAUTION so the warehouse team sees orders awaiting confirmation.
+func HandleListSupplierOrders(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := middleware.GetStaffFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		filter, verr := parseOrderListFilter(c)
+		if verr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": verr.Error(), "details": verr.Fields})
+			return
+		}
+		if len(filter.Statuses) == 0 {
+			filter.Statuses = []domain.OrderStatus{domain.OrderStatusIncompleteCaution}
+		}
+		if v := c.Query("partner_id"); v != "" {
+			partnerID, err := uuid.Parse(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid partner_id"})
+				return
+			}
+			filter.PartnerID = &partnerID
+		}
+
+		page, err := repos.SupplierOrder.ListOrders(c.Request.Context(), filter)
+		if err != nil {
+			logger.Error("Failed to list supplier orders", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		orderResponses := make([]gin.H, len(page.Orders))
+		for i, order := range page.Orders {
+			orderResponses[i] = gin.H{
+				"id":               order.ID.String(),
+				"partner_id":       order.PartnerID.String(),
+				"partner_order_id": order.PartnerOrderID,
+				"status":           order.Status,
+				"shopify_order_id": order.ShopifyOrderID,
+				"customer_name":    order.CustomerName,
+				"cart_total":       order.CartTotal,
+				"created_at":       order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+				"updated_at":       order.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			}
+			if order.DeliveryWindow != nil {
+				orderResponses[i]["delivery_window"] = buildDeliveryWindowResponse(order.DeliveryWindow)
+			}
+			if order.HoldReason != nil {
+				orderResponses[i]["hold_reason"] = *order.HoldReason
+			}
+			if order.PossibleDuplicateOf != nil {
+				orderResponses[i]["possible_duplicate_of"] = order.PossibleDuplicateOf.String()
+			}
+		}
+
+		c.JSON(http.StatusOK, orderPageResponse(gin.H{
+			"orders": orderResponses,
+			"status": filter.Statuses,
+		}, page, filter.Limit))
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+const StaffContextKey = "supplier_staff"
+
+// StaffAuthMiddleware authenticates supplier staff using their own API key (supplier_staff table).
+// Partner API keys are never accepted here, so partners cannot reach /v1/supplier routes.
+func StaffAuthMiddleware(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing authorization header"})
+			c.Abort()
+			return
+		}
+
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			c.Abort()
+			return
+		}
+
+		apiKey := strings.TrimSpace(parts[1])
+		if apiKey == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing API key"})
+			c.Abort()
+			return
+		}
+
+		staff, err := repos.SupplierStaff.GetByAPIKey(c.Request.Context(), apiKey)
+		if err != nil {
+			logger.Warn("Failed to authenticate supplier staff", zap.Error(err))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+			c.Abort()
+			return
+		}
+
+		if !staff.IsActive {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "staff account is inactive"})
+			c.Abort()
+			return
+		}
+
+		c.Set(StaffContextKey, staff)
+		c.Next()
+	}
+}
+
+// GetStaffFromContext retrieves the supplier staff member from the Gin context
+func GetStaffFromContext(c *gin.Context) (*domain.SupplierStaff, bool) {
+	staff, exists := c.Get(StaffContextKey)
+	if !exists {
+		return nil, false
+	}
+
+	s, ok := staff.(*domain.SupplierStaff)
+	return s, ok
+}
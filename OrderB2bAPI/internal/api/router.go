@@ -3,13 +3,17 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"reflect"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/api/handlers"
 	"github.com/jafarshop/b2bapi/internal/api/middleware"
 	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository"
 )
 
@@ -20,6 +24,7 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories, logger *zap.L
 	}
 
 	router := gin.New()
+	registerValidators()
 
 	// Middleware
 	router.Use(customRecovery(logger))
@@ -35,9 +40,45 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories, logger *zap.L
 				"POST /internal/webhooks/delivery",
 				"GET /v1/catalog/products",
 				"POST /v1/carts/submit",
+				"POST /v1/carts/submit-batch",
+				"GET /v1/orders/export",
 				"GET /v1/orders/:id",
 				"GET /v1/orders/:id/delivery-status",
+				"POST /v1/orders/:id/cancel",
+				"PATCH /v1/orders/:id",
+				"POST /v1/orders/:id/returns",
+				"GET /v1/orders/:id/returns",
+				"GET /v1/orders/:id/events",
+				"POST /v1/orders/:id/notes",
+				"GET /v1/orders/:id/notes",
+				"GET /v1/orders/:id/invoice",
+				"GET /v1/settlements",
+				"GET /v1/credit",
+				"GET /v1/statements/:month",
 				"GET /v1/admin/orders",
+				"GET /v1/supplier/orders",
+				"POST /v1/supplier/orders/:id/confirm",
+				"POST /v1/supplier/orders/:id/reject",
+				"POST /v1/supplier/orders/:id/ship",
+				"POST /v1/supplier/orders/:id/mark-paid",
+				"POST /v1/supplier/orders/:id/notes",
+				"GET /v1/supplier/orders/:id/notes",
+				"GET /v1/supplier/orders/:id/invoice",
+				"GET /v1/supplier/returns",
+				"POST /v1/supplier/returns/:id/approve",
+				"POST /v1/supplier/returns/:id/reject",
+				"GET /v1/supplier/settlements",
+				"POST /v1/supplier/cod/remittances",
+				"GET /v1/supplier/price-lists",
+				"POST /v1/supplier/price-lists",
+				"DELETE /v1/supplier/price-lists/:id",
+				"GET /v1/supplier/order-rules",
+				"POST /v1/supplier/order-rules",
+				"POST /v1/supplier/order-rules/dry-run",
+				"DELETE /v1/supplier/order-rules/:id",
+				"GET /v1/supplier/credit",
+				"GET /v1/supplier/sla-breaches",
+				"GET /v1/supplier/statements/:month",
 			},
 		})
 	})
@@ -63,8 +104,21 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories, logger *zap.L
 		{
 			partnerRoutes.GET("/catalog/products", handlers.HandleGetCatalogProducts(cfg, repos, logger))
 			partnerRoutes.POST("/carts/submit", handlers.HandleCartSubmit(cfg, repos, logger))
+			partnerRoutes.POST("/carts/submit-batch", handlers.HandleCartSubmitBatch(cfg, repos, logger))
+			partnerRoutes.GET("/orders/export", handlers.HandleExportOrders(repos, logger))
 			partnerRoutes.GET("/orders/:id", handlers.HandleGetOrder(cfg, repos, logger))
 			partnerRoutes.GET("/orders/:id/delivery-status", handlers.HandleGetOrderDeliveryStatus(cfg, repos, logger))
+			partnerRoutes.POST("/orders/:id/cancel", handlers.HandleCancelOrder(cfg, repos, logger))
+			partnerRoutes.PATCH("/orders/:id", handlers.HandleAmendOrder(cfg, repos, logger))
+			partnerRoutes.POST("/orders/:id/returns", handlers.HandleCreateReturn(repos, logger))
+			partnerRoutes.GET("/orders/:id/returns", handlers.HandleListOrderReturns(repos, logger))
+			partnerRoutes.GET("/orders/:id/events", handlers.HandleGetOrderEvents(repos, logger))
+			partnerRoutes.POST("/orders/:id/notes", handlers.HandleCreateOrderNote(cfg, repos, logger))
+			partnerRoutes.GET("/orders/:id/notes", handlers.HandleListOrderNotes(repos, logger))
+			partnerRoutes.GET("/orders/:id/invoice", handlers.HandleGetOrderInvoice(cfg, repos, logger))
+			partnerRoutes.GET("/settlements", handlers.HandleGetSettlement(cfg, repos, logger))
+			partnerRoutes.GET("/credit", handlers.HandleGetCredit(repos, logger))
+			partnerRoutes.GET("/statements/:month", handlers.HandleGetStatement(cfg, repos, logger))
 		}
 
 		// Partner order listing (check status only - no confirm/reject/ship)
@@ -73,11 +127,49 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories, logger *zap.L
 		{
 			adminRoutes.GET("/orders", handlers.HandleListOrders(repos, logger))
 		}
+
+		// Supplier staff routes (warehouse team; separate credentials, acts across all partners)
+		supplierRoutes := v1.Group("/supplier")
+		supplierRoutes.Use(middleware.StaffAuthMiddleware(repos, logger))
+		{
+			supplierRoutes.GET("/orders", handlers.HandleListSupplierOrders(repos, logger))
+			supplierRoutes.POST("/orders/:id/confirm", handlers.HandleConfirmOrder(cfg, repos, logger))
+			supplierRoutes.POST("/orders/:id/reject", handlers.HandleRejectOrder(repos, logger))
+			supplierRoutes.POST("/orders/:id/ship", handlers.HandleShipOrder(repos, logger))
+			supplierRoutes.POST("/orders/:id/mark-paid", handlers.HandleMarkOrderPaid(cfg, repos, logger))
+			supplierRoutes.POST("/orders/:id/notes", handlers.HandleCreateSupplierOrderNote(cfg, repos, logger))
+			supplierRoutes.GET("/orders/:id/notes", handlers.HandleListSupplierOrderNotes(repos, logger))
+			supplierRoutes.GET("/orders/:id/invoice", handlers.HandleGetSupplierOrderInvoice(cfg, repos, logger))
+			supplierRoutes.GET("/returns", handlers.HandleListSupplierReturns(repos, logger))
+			supplierRoutes.POST("/returns/:id/approve", handlers.HandleApproveReturn(cfg, repos, logger))
+			supplierRoutes.POST("/returns/:id/reject", handlers.HandleRejectReturn(repos, logger))
+			supplierRoutes.GET("/settlements", handlers.HandleGetSupplierSettlement(cfg, repos, logger))
+			supplierRoutes.POST("/cod/remittances", handlers.HandleCreateCODRemittance(repos, logger))
+			supplierRoutes.GET("/price-lists", handlers.HandleListPartnerPrices(cfg, repos, logger))
+			supplierRoutes.POST("/price-lists", handlers.HandleCreatePartnerPrice(cfg, repos, logger))
+			supplierRoutes.DELETE("/price-lists/:id", handlers.HandleDeletePartnerPrice(repos, logger))
+			supplierRoutes.GET("/order-rules", handlers.HandleListOrderRules(repos, logger))
+			supplierRoutes.POST("/order-rules", handlers.HandleCreateOrderRule(repos, logger))
+			supplierRoutes.POST("/order-rules/dry-run", handlers.HandleDryRunOrderRules(cfg, repos, logger))
+			supplierRoutes.DELETE("/order-rules/:id", handlers.HandleDeleteOrderRule(repos, logger))
+			supplierRoutes.GET("/credit", handlers.HandleGetSupplierCredit(repos, logger))
+			supplierRoutes.GET("/sla-breaches", handlers.HandleListSLABreaches(repos, logger))
+			supplierRoutes.GET("/statements/:month", handlers.HandleGetSupplierStatement(cfg, repos, logger))
+		}
 	}
 
 	return router
 }
 
+// registerValidators lets binding tags such as required and min=0 check domain.Money fields by their amount.
+func registerValidators() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+			return field.Interface().(domain.Money).Float64()
+		}, domain.Money{})
+	}
+}
+
 // customRecovery is a custom recovery middleware that logs panics
 func customRecovery(logger *zap.Logger) gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
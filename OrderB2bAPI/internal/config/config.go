@@ -3,9 +3,14 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata" // DELIVERY_TIMEZONE must load in minimal containers without a zoneinfo database
 
 	"github.com/spf13/viper"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
 )
 
 type Config struct {
@@ -20,6 +25,41 @@ type Config struct {
 	DeliveryWebhookSecret   string // DELIVERY_WEBHOOK_SECRET: auth for POST /internal/webhooks/delivery from GetDeliveryStatus
 	ShopifyWebhookSecret    string // SHOPIFY_WEBHOOK_SECRET: verify incoming Shopify webhooks (X-Shopify-Hmac-Sha256)
 	WasselDefaultPartnerID  string // WASSEL_DEFAULT_PARTNER_ID: optional UUID; when set, unknown ItemReferenceNo creates minimal order under this partner
+	CartBatchMaxSize        int    // CART_BATCH_MAX_SIZE: max carts per POST /v1/carts/submit-batch (default 50)
+	StockCacheTTL           time.Duration // STOCK_CACHE_TTL_SECONDS: how long Shopify stock levels are reused for cart stock checks (default 60)
+	DuplicateOrderWindow    time.Duration // DUPLICATE_ORDER_WINDOW_MINUTES: carts matching an order this recent are held as possible duplicates (default 60; 0 disables)
+	DeliveryWindow          DeliveryWindowConfig
+	Invoice                 InvoiceConfig
+	SLA                     SLAConfig
+}
+
+// SLAConfig drives the SLA monitor, which records orders that stay in a status too long and alerts staff
+type SLAConfig struct {
+	CheckInterval      time.Duration                        // SLA_CHECK_INTERVAL_MINUTES: how often orders are scanned (default 15; 0 disables the monitor)
+	StatusThresholds   map[domain.OrderStatus]time.Duration // SLA_THRESHOLDS: comma-separated STATUS=duration defaults (e.g. UNFULFILLED=72h, FULFILLED=7d)
+	DeliveryThresholds map[int]time.Duration                // SLA_THRESHOLDS entries keyed by a Wassel delivery status (e.g. 130=24h)
+	AlertWebhookURL    string                               // STAFF_ALERT_WEBHOOK_URL: new breaches are POSTed here (optional)
+}
+
+// InvoiceConfig is the seller information and formatting used for tax invoices and statements
+type InvoiceConfig struct {
+	SellerName      string         // INVOICE_SELLER_NAME (default JafarShop)
+	SellerNameAr    string         // INVOICE_SELLER_NAME_AR: Arabic seller name (printed when a font is set)
+	SellerAddress   string         // INVOICE_SELLER_ADDRESS
+	SellerTaxNumber string         // INVOICE_SELLER_TAX_NUMBER: sales tax registration number
+	TaxRate         float64        // INVOICE_TAX_RATE: sales tax percent included in item prices (default 16)
+	Currency        string         // INVOICE_CURRENCY: for orders without a currency (default JOD)
+	NumberPrefix    string         // INVOICE_NUMBER_PREFIX: printed before the sequence number (default INV-)
+	Font            []byte         // INVOICE_FONT_PATH: TrueType font with Arabic glyphs; without one, English only (no Arabic names)
+	Location        *time.Location // dates are printed in DELIVERY_TIMEZONE
+}
+
+// DeliveryWindowConfig limits the delivery date partners may request on cart submission
+type DeliveryWindowConfig struct {
+	Location         *time.Location  // DELIVERY_TIMEZONE: dates are local to the warehouse (default Asia/Amman)
+	BlackoutWeekdays []time.Weekday  // DELIVERY_BLACKOUT_WEEKDAYS: comma-separated day names with no deliveries (default Friday)
+	BlackoutDates    map[string]bool // DELIVERY_BLACKOUT_DATES: comma-separated YYYY-MM-DD holidays
+	MaxDaysAhead     int             // DELIVERY_WINDOW_MAX_DAYS: how far ahead a date may be requested (default 30)
 }
 
 // GetDeliveryStatusConfig is used to call GetDeliveryStatus (Wassel) for shipment/delivery status
@@ -107,7 +147,35 @@ func Load() (*Config, error) {
 		DeliveryWebhookSecret:   strings.TrimSpace(getEnvOrViper("DELIVERY_WEBHOOK_SECRET", "")),
 		ShopifyWebhookSecret:    strings.TrimSpace(getEnvOrViper("SHOPIFY_WEBHOOK_SECRET", "")),
 		WasselDefaultPartnerID:  strings.TrimSpace(getEnvOrViper("WASSEL_DEFAULT_PARTNER_ID", "")),
+		CartBatchMaxSize:        getIntEnvOrViper("CART_BATCH_MAX_SIZE", 50),
+		StockCacheTTL:           time.Duration(getIntEnvOrViper("STOCK_CACHE_TTL_SECONDS", 60)) * time.Second,
+	}
+
+	window := strings.TrimSpace(getEnvOrViper("DUPLICATE_ORDER_WINDOW_MINUTES", "60"))
+	minutes, err := strconv.Atoi(window)
+	if err != nil || minutes < 0 {
+		return nil, fmt.Errorf("invalid DUPLICATE_ORDER_WINDOW_MINUTES %q (want minutes, 0 to disable)", window)
+	}
+	cfg.DuplicateOrderWindow = time.Duration(minutes) * time.Minute
+
+	deliveryWindow, err := loadDeliveryWindowConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.DeliveryWindow = deliveryWindow
+
+	invoice, err := loadInvoiceConfig()
+	if err != nil {
+		return nil, err
+	}
+	invoice.Location = deliveryWindow.Location
+	cfg.Invoice = invoice
+
+	sla, err := loadSLAConfig()
+	if err != nil {
+		return nil, err
 	}
+	cfg.SLA = sla
 
 	// Validate required fields
 	if cfg.Shopify.ShopDomain == "" {
@@ -129,3 +197,144 @@ func getEnvOrViper(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getIntEnvOrViper(key string, defaultValue int) int {
+	n, err := strconv.Atoi(strings.TrimSpace(getEnvOrViper(key, "")))
+	if err != nil || n <= 0 {
+		return defaultValue
+	}
+	return n
+}
+
+func loadDeliveryWindowConfig() (DeliveryWindowConfig, error) {
+	cfg := DeliveryWindowConfig{
+		BlackoutDates: make(map[string]bool),
+		MaxDaysAhead:  getIntEnvOrViper("DELIVERY_WINDOW_MAX_DAYS", 30),
+	}
+
+	tz := strings.TrimSpace(getEnvOrViper("DELIVERY_TIMEZONE", "Asia/Amman"))
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid DELIVERY_TIMEZONE %q: %w", tz, err)
+	}
+	cfg.Location = loc
+
+	for _, name := range strings.Split(getEnvOrViper("DELIVERY_BLACKOUT_WEEKDAYS", "Friday"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		weekday, ok := parseWeekday(name)
+		if !ok {
+			return cfg, fmt.Errorf("invalid DELIVERY_BLACKOUT_WEEKDAYS entry %q", name)
+		}
+		cfg.BlackoutWeekdays = append(cfg.BlackoutWeekdays, weekday)
+	}
+
+	for _, date := range strings.Split(getEnvOrViper("DELIVERY_BLACKOUT_DATES", ""), ",") {
+		if date = strings.TrimSpace(date); date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return cfg, fmt.Errorf("invalid DELIVERY_BLACKOUT_DATES entry %q (want YYYY-MM-DD)", date)
+		}
+		cfg.BlackoutDates[date] = true
+	}
+	return cfg, nil
+}
+
+func loadInvoiceConfig() (InvoiceConfig, error) {
+	cfg := InvoiceConfig{
+		SellerName:      strings.TrimSpace(getEnvOrViper("INVOICE_SELLER_NAME", "JafarShop")),
+		SellerNameAr:    strings.TrimSpace(getEnvOrViper("INVOICE_SELLER_NAME_AR", "")),
+		SellerAddress:   strings.TrimSpace(getEnvOrViper("INVOICE_SELLER_ADDRESS", "")),
+		SellerTaxNumber: strings.TrimSpace(getEnvOrViper("INVOICE_SELLER_TAX_NUMBER", "")),
+		Currency:        strings.ToUpper(strings.TrimSpace(getEnvOrViper("INVOICE_CURRENCY", "JOD"))),
+		NumberPrefix:    strings.TrimSpace(getEnvOrViper("INVOICE_NUMBER_PREFIX", "INV-")),
+	}
+
+	rate := strings.TrimSpace(getEnvOrViper("INVOICE_TAX_RATE", "16"))
+	taxRate, err := strconv.ParseFloat(rate, 64)
+	if err != nil || taxRate < 0 || taxRate >= 100 {
+		return cfg, fmt.Errorf("invalid INVOICE_TAX_RATE %q (want a percent, e.g. 16)", rate)
+	}
+	cfg.TaxRate = taxRate
+
+	if path := strings.TrimSpace(getEnvOrViper("INVOICE_FONT_PATH", "")); path != "" {
+		font, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid INVOICE_FONT_PATH: %w", err)
+		}
+		cfg.Font = font
+	}
+	return cfg, nil
+}
+
+// defaultSLAThresholds: new orders reviewed within 4 hours, fulfilled within 3 days, delivered within a week of
+// shipping, returns closed within a week, and no more than a day out for delivery
+const defaultSLAThresholds = "INCOMPLETE_CAUTION=4h,UNFULFILLED=72h,PARTIALLY_FULFILLED=72h,FULFILLED=7d,RETURN_IN_PROGRESS=7d,130=24h"
+
+func loadSLAConfig() (SLAConfig, error) {
+	cfg := SLAConfig{
+		StatusThresholds:   make(map[domain.OrderStatus]time.Duration),
+		DeliveryThresholds: make(map[int]time.Duration),
+		AlertWebhookURL:    strings.TrimSpace(getEnvOrViper("STAFF_ALERT_WEBHOOK_URL", "")),
+	}
+
+	interval := strings.TrimSpace(getEnvOrViper("SLA_CHECK_INTERVAL_MINUTES", "15"))
+	minutes, err := strconv.Atoi(interval)
+	if err != nil || minutes < 0 {
+		return cfg, fmt.Errorf("invalid SLA_CHECK_INTERVAL_MINUTES %q (want minutes, 0 to disable)", interval)
+	}
+	cfg.CheckInterval = time.Duration(minutes) * time.Minute
+
+	for _, entry := range strings.Split(getEnvOrViper("SLA_THRESHOLDS", defaultSLAThresholds), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return cfg, fmt.Errorf("invalid SLA_THRESHOLDS entry %q (want STATUS=duration)", entry)
+		}
+		d, err := ParseSLADuration(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid SLA_THRESHOLDS entry %q: %w", entry, err)
+		}
+		if code, err := strconv.Atoi(key); err == nil {
+			cfg.DeliveryThresholds[code] = d
+			continue
+		}
+		if status := domain.OrderStatus(key); status.IsValid() {
+			cfg.StatusThresholds[status] = d
+			continue
+		}
+		return cfg, fmt.Errorf("invalid SLA_THRESHOLDS entry %q (unknown order status)", entry)
+	}
+	return cfg, nil
+}
+
+// ParseSLADuration parses an SLA threshold: a Go duration (90m, 72h) or a number of days (7d). 0 means no check.
+func ParseSLADuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d.Truncate(time.Minute), nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(name, d.String()) || strings.EqualFold(name, d.String()[:3]) {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "strings"
+
 // OrderStatus represents the status of a supplier order (Shopify-aligned)
 type OrderStatus string
 
@@ -8,6 +10,8 @@ const (
 	OrderStatusIncompleteCaution OrderStatus = "INCOMPLETE_CAUTION"
 	// UNFULFILLED - Order confirmed, awaiting shipment (was CONFIRMED)
 	OrderStatusUnfulfilled OrderStatus = "UNFULFILLED"
+	// PARTIALLY_FULFILLED - Some items shipped, others still pending
+	OrderStatusPartiallyFulfilled OrderStatus = "PARTIALLY_FULFILLED"
 	// FULFILLED - Order shipped
 	OrderStatusFulfilled OrderStatus = "FULFILLED"
 	// COMPLETE - Order delivered
@@ -16,61 +20,75 @@ const (
 	OrderStatusRejected OrderStatus = "REJECTED"
 	// CANCELED - Order canceled
 	OrderStatusCanceled OrderStatus = "CANCELED"
+	// RETURN_IN_PROGRESS - A return is open (Wassel return status or partner request), awaiting staff decision
+	OrderStatusReturnInProgress OrderStatus = "RETURN_IN_PROGRESS"
+	// RETURNED - Return approved; items back with the supplier
+	OrderStatusReturned OrderStatus = "RETURNED"
 	// REFUNDED - Order refunded
 	OrderStatusRefunded OrderStatus = "REFUNDED"
 	// ARCHIVED - Order archived
 	OrderStatusArchived OrderStatus = "ARCHIVED"
-
-	// Legacy aliases for backward compatibility (DB migration maps these)
-	OrderStatusPendingConfirmation OrderStatus = "PENDING_CONFIRMATION"
-	OrderStatusConfirmed           OrderStatus = "CONFIRMED"
-	OrderStatusShipped             OrderStatus = "SHIPPED"
-	OrderStatusDelivered           OrderStatus = "DELIVERED"
-	OrderStatusCancelled           OrderStatus = "CANCELLED"
 )
 
-// IsValid checks if the order status is valid
+// OrderStatuses lists every status an order may hold; it must match the chk_supplier_orders_status constraint.
+var OrderStatuses = []OrderStatus{
+	OrderStatusIncompleteCaution,
+	OrderStatusUnfulfilled,
+	OrderStatusPartiallyFulfilled,
+	OrderStatusFulfilled,
+	OrderStatusComplete,
+	OrderStatusRejected,
+	OrderStatusCanceled,
+	OrderStatusReturnInProgress,
+	OrderStatusReturned,
+	OrderStatusRefunded,
+	OrderStatusArchived,
+}
+
+// IsValid checks if the order status is valid (legacy names rewritten by migrations 000004 and 000014 are not)
 func (s OrderStatus) IsValid() bool {
-	switch s {
-	case OrderStatusIncompleteCaution,
-		OrderStatusUnfulfilled,
-		OrderStatusFulfilled,
-		OrderStatusComplete,
-		OrderStatusRejected,
-		OrderStatusCanceled,
-		OrderStatusRefunded,
-		OrderStatusArchived,
-		// Legacy (accepted from DB until migrated)
-		OrderStatusPendingConfirmation,
-		OrderStatusConfirmed,
-		OrderStatusShipped,
-		OrderStatusDelivered,
-		OrderStatusCancelled:
-		return true
-	default:
-		return false
+	for _, status := range OrderStatuses {
+		if s == status {
+			return true
+		}
 	}
+	return false
 }
 
 // CanTransitionTo checks if a status transition is valid
-func (s OrderStatus) CanTransitionTo(newStatus OrderStatus) bool {
-	// Normalize legacy statuses for transition check
-	from := s.normalize()
-	to := newStatus.normalize()
-
-	switch from {
+func (s OrderStatus) CanTransitionTo(to OrderStatus) bool {
+	switch s {
 	case OrderStatusIncompleteCaution:
+		// Completed in Shopify at submit, so the warehouse can ship an order before it is confirmed here
 		return to == OrderStatusUnfulfilled ||
+			to == OrderStatusPartiallyFulfilled ||
+			to == OrderStatusFulfilled ||
 			to == OrderStatusRejected ||
 			to == OrderStatusCanceled
 	case OrderStatusUnfulfilled:
-		return to == OrderStatusFulfilled ||
+		return to == OrderStatusPartiallyFulfilled ||
+			to == OrderStatusFulfilled ||
 			to == OrderStatusCanceled ||
 			to == OrderStatusRefunded
+	case OrderStatusPartiallyFulfilled:
+		return to == OrderStatusFulfilled ||
+			to == OrderStatusReturnInProgress ||
+			to == OrderStatusRefunded
 	case OrderStatusFulfilled:
 		return to == OrderStatusComplete ||
+			to == OrderStatusReturnInProgress ||
 			to == OrderStatusRefunded
 	case OrderStatusComplete:
+		return to == OrderStatusReturnInProgress ||
+			to == OrderStatusRefunded ||
+			to == OrderStatusArchived
+	case OrderStatusReturnInProgress:
+		// Approved returns end RETURNED; rejected returns restore the status the order had before
+		return to == OrderStatusReturned ||
+			to == OrderStatusPartiallyFulfilled ||
+			to == OrderStatusFulfilled ||
+			to == OrderStatusComplete
+	case OrderStatusReturned:
 		return to == OrderStatusRefunded ||
 			to == OrderStatusArchived
 	case OrderStatusRejected, OrderStatusCanceled, OrderStatusRefunded, OrderStatusArchived:
@@ -80,20 +98,252 @@ func (s OrderStatus) CanTransitionTo(newStatus OrderStatus) bool {
 	}
 }
 
-// normalize maps legacy statuses to new ones for transition logic
-func (s OrderStatus) normalize() OrderStatus {
+// ReturnStatus represents the status of an order return (RMA)
+type ReturnStatus string
+
+const (
+	// REQUESTED - Return opened, awaiting supplier staff decision
+	ReturnStatusRequested ReturnStatus = "REQUESTED"
+	// APPROVED - Return accepted (items restocked in Shopify when restock was chosen)
+	ReturnStatusApproved ReturnStatus = "APPROVED"
+	// REJECTED - Return refused; order restored to its previous status
+	ReturnStatusRejected ReturnStatus = "REJECTED"
+)
+
+// IsValid checks if the return status is valid
+func (s ReturnStatus) IsValid() bool {
 	switch s {
-	case OrderStatusPendingConfirmation:
-		return OrderStatusIncompleteCaution
-	case OrderStatusConfirmed:
-		return OrderStatusUnfulfilled
-	case OrderStatusShipped:
-		return OrderStatusFulfilled
-	case OrderStatusDelivered:
-		return OrderStatusComplete
-	case OrderStatusCancelled:
-		return OrderStatusCanceled
+	case ReturnStatusRequested, ReturnStatusApproved, ReturnStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// NoteAuthorType is who wrote an order note
+type NoteAuthorType string
+
+const (
+	NoteAuthorPartner NoteAuthorType = "partner"
+	NoteAuthorStaff   NoteAuthorType = "staff"
+)
+
+// PricePolicy is what happens to a partner's cart when its prices or totals disagree with ours
+type PricePolicy string
+
+const (
+	// reject - the cart is refused with 422
+	PricePolicyReject PricePolicy = "reject"
+	// flag - the partner's prices are kept and the discrepancy recorded
+	PricePolicyFlag PricePolicy = "flag"
+	// override - our prices replace the partner's and totals are recomputed
+	PricePolicyOverride PricePolicy = "override"
+)
+
+// IsValid checks if the price policy is known
+func (p PricePolicy) IsValid() bool {
+	switch p {
+	case PricePolicyReject, PricePolicyFlag, PricePolicyOverride:
+		return true
+	default:
+		return false
+	}
+}
+
+// StockPolicy is what happens to a partner's cart when a quantity exceeds the Shopify stock
+type StockPolicy string
+
+const (
+	// off - stock is not checked
+	StockPolicyOff StockPolicy = "off"
+	// report - availability is returned, the order is created as usual
+	StockPolicyReport StockPolicy = "report"
+	// hold - the order is created and held in INCOMPLETE_CAUTION with a hold reason
+	StockPolicyHold StockPolicy = "hold"
+	// reject - the cart is refused with 422
+	StockPolicyReject StockPolicy = "reject"
+)
+
+// IsValid checks if the stock policy is known
+func (p StockPolicy) IsValid() bool {
+	switch p {
+	case StockPolicyOff, StockPolicyReport, StockPolicyHold, StockPolicyReject:
+		return true
+	default:
+		return false
+	}
+}
+
+// CreditPolicy is what happens to a partner's cart when it would take the partner over its credit limit
+type CreditPolicy string
+
+const (
+	// reject - the cart is refused with 402 (code credit_limit_exceeded)
+	CreditPolicyReject CreditPolicy = "reject"
+	// hold - the order is created and held in INCOMPLETE_CAUTION with a hold reason
+	CreditPolicyHold CreditPolicy = "hold"
+)
+
+// IsValid checks if the credit policy is known
+func (p CreditPolicy) IsValid() bool {
+	switch p {
+	case CreditPolicyReject, CreditPolicyHold:
+		return true
+	default:
+		return false
+	}
+}
+
+// AddressPolicy is what happens to a partner's cart when its shipping city is not in the Jordan gazetteer
+type AddressPolicy string
+
+const (
+	// lenient - the city is stored as sent, without city/area codes
+	AddressPolicyLenient AddressPolicy = "lenient"
+	// strict - the cart is refused with 422
+	AddressPolicyStrict AddressPolicy = "strict"
+)
+
+// IsValid checks if the address policy is known
+func (p AddressPolicy) IsValid() bool {
+	switch p {
+	case AddressPolicyLenient, AddressPolicyStrict:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderRuleAction is what a partner's auto-confirmation rule does with a new order it matches
+type OrderRuleAction string
+
+const (
+	// confirm - the order is confirmed (UNFULFILLED) without waiting for supplier staff
+	OrderRuleActionConfirm OrderRuleAction = "confirm"
+	// hold - the order stays in INCOMPLETE_CAUTION with a hold reason naming the rule
+	OrderRuleActionHold OrderRuleAction = "hold"
+)
+
+// IsValid checks if the rule action is known
+func (a OrderRuleAction) IsValid() bool {
+	switch a {
+	case OrderRuleActionConfirm, OrderRuleActionHold:
+		return true
+	default:
+		return false
+	}
+}
+
+// Payment statuses stored on supplier_orders.payment_status
+const (
+	PaymentStatusPending = "Payment pending"
+	PaymentStatusPaid    = "Paid"
+)
+
+// IsPaidOnDelivery reports whether the courier collects payment for the method ("Cash On Delivery (COD)",
+// "Card On Delivery"). Orders without a payment method are treated as cash on delivery.
+func IsPaidOnDelivery(paymentMethod *string) bool {
+	if paymentMethod == nil || strings.TrimSpace(*paymentMethod) == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(*paymentMethod), "on delivery")
+}
+
+// CODEntryType is the kind of a cash-on-delivery ledger entry
+type CODEntryType string
+
+const (
+	// collected - cash taken by Wassel when the order was delivered
+	CODEntryCollected CODEntryType = "collected"
+	// returned - refund owed for items returned from an order whose cash was collected
+	CODEntryReturned CODEntryType = "returned"
+)
+
+// ReturnReason is why items are coming back
+type ReturnReason string
+
+const (
+	ReturnReasonCustomerReturned ReturnReason = "customer_returned" // Wassel 180/190: returned from customer
+	ReturnReasonReturnToShipper  ReturnReason = "return_to_shipper" // Wassel 210: undeliverable, returned to shipper (RTO)
+	ReturnReasonDamaged          ReturnReason = "damaged"
+	ReturnReasonWrongItem        ReturnReason = "wrong_item"
+	ReturnReasonNotAsDescribed   ReturnReason = "not_as_described"
+	ReturnReasonChangedMind      ReturnReason = "changed_mind"
+	ReturnReasonOther            ReturnReason = "other"
+)
+
+// IsValid checks if the return reason is valid
+func (r ReturnReason) IsValid() bool {
+	switch r {
+	case ReturnReasonCustomerReturned,
+		ReturnReasonReturnToShipper,
+		ReturnReasonDamaged,
+		ReturnReasonWrongItem,
+		ReturnReasonNotAsDescribed,
+		ReturnReasonChangedMind,
+		ReturnReasonOther:
+		return true
 	default:
-		return s
+		return false
+	}
+}
+
+// OrderEventType identifies an order_events entry (the order's audit trail / partner timeline)
+type OrderEventType string
+
+const (
+	OrderEventCreated           OrderEventType = "order_created"
+	OrderEventStatusChange      OrderEventType = "status_change"
+	OrderEventTrackingUpdated   OrderEventType = "tracking_updated"
+	OrderEventDeliveryStatus    OrderEventType = "delivery_status"
+	OrderEventItemsFulfilled    OrderEventType = "items_fulfilled"
+	OrderEventAmended           OrderEventType = "order_amended"
+	OrderEventReturnOpened      OrderEventType = "return_opened"
+	OrderEventReturnApproved    OrderEventType = "return_approved"
+	OrderEventReturnRejected    OrderEventType = "return_rejected"
+	OrderEventShopifySyncFailed OrderEventType = "shopify_sync_failed"
+	// Illegal status change that was refused (order left unchanged)
+	OrderEventStatusChangeRejected OrderEventType = "status_change_rejected"
+	OrderEventNoteAdded            OrderEventType = "note_added"
+	OrderEventPaymentCollected     OrderEventType = "payment_collected"
+	OrderEventPriceDiscrepancy     OrderEventType = "price_discrepancy"
+	OrderEventStockShortage        OrderEventType = "stock_shortage"
+	OrderEventCreditLimitExceeded  OrderEventType = "credit_limit_exceeded"
+	OrderEventInvoiceIssued        OrderEventType = "invoice_issued"
+	OrderEventPossibleDuplicate    OrderEventType = "possible_duplicate"
+	OrderEventRuleMatched          OrderEventType = "rule_matched"
+)
+
+// OrderEventTypes lists every event type written to order_events; IsValid and the timeline's type filter accept
+// only these, so a new type must be added here.
+var OrderEventTypes = []OrderEventType{
+	OrderEventCreated,
+	OrderEventStatusChange,
+	OrderEventTrackingUpdated,
+	OrderEventDeliveryStatus,
+	OrderEventItemsFulfilled,
+	OrderEventAmended,
+	OrderEventReturnOpened,
+	OrderEventReturnApproved,
+	OrderEventReturnRejected,
+	OrderEventShopifySyncFailed,
+	OrderEventStatusChangeRejected,
+	OrderEventNoteAdded,
+	OrderEventPaymentCollected,
+	OrderEventPriceDiscrepancy,
+	OrderEventStockShortage,
+	OrderEventCreditLimitExceeded,
+	OrderEventInvoiceIssued,
+	OrderEventPossibleDuplicate,
+	OrderEventRuleMatched,
+}
+
+// IsValid checks if the event type is known
+func (t OrderEventType) IsValid() bool {
+	for _, eventType := range OrderEventTypes {
+		if t == eventType {
+			return true
+		}
 	}
+	return false
 }
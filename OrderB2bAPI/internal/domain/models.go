@@ -14,11 +14,30 @@ type Partner struct {
 	APIKeyLookup     string // SHA256(apiKey) hex for fast lookup; optional, set on create
 	WebhookURL       *string
 	CollectionHandle *string // Shopify collection handle for this partner's catalog
+	PricePolicy      PricePolicy
+	StockPolicy      StockPolicy
+	CreditLimit      *Money // maximum exposure (open unpaid orders) when ordering on credit; nil = no limit
+	CreditPolicy     CreditPolicy
+	Currency         string // ISO 4217 code of the partner's prices and orders; only DefaultCurrency is supported
+	AddressPolicy    AddressPolicy
 	IsActive         bool
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 }
 
+// SupplierStaff represents a supplier-side (warehouse) staff member.
+// Staff act on orders across all partners; partners never authenticate as staff.
+type SupplierStaff struct {
+	ID           uuid.UUID
+	Name         string
+	Email        *string
+	APIKeyHash   string
+	APIKeyLookup string // SHA256(apiKey) hex for lookup; verified with bcrypt against APIKeyHash
+	IsActive     bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
 // SupplierOrder represents an order from a partner
 type SupplierOrder struct {
 	ID                  uuid.UUID
@@ -30,10 +49,13 @@ type SupplierOrder struct {
 	CustomerName        string
 	CustomerPhone       string
 	ShippingAddress     map[string]interface{} // JSONB
-	CartTotal           float64
+	CartTotal           Money
+	Currency            string // ISO 4217 code of CartTotal and item prices (the partner's currency)
 	PaymentStatus       string
 	PaymentMethod       *string
 	RejectionReason     *string
+	HoldReason          *string // why an INCOMPLETE_CAUTION order needs review before confirming (e.g. insufficient stock)
+	PossibleDuplicateOf *uuid.UUID // earlier order this one looks like a retry of (held for review)
 	TrackingCarrier     *string
 	TrackingNumber      *string
 	TrackingURL         *string
@@ -43,22 +65,153 @@ type SupplierOrder struct {
 	LastDeliveryWaybill      *string
 	LastDeliveryImageURL     *string
 	LastDeliveryAt           *time.Time
+	// Requested by the partner on cart submission (nil when none was asked for)
+	DeliveryWindow      *DeliveryWindow
+	StatusChangedAt     time.Time // when the order entered its current status (SLA monitoring)
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 }
 
+// DeliveryWindow is a requested delivery date with an optional time range (warehouse local time, "HH:MM").
+// Either bound may be missing, e.g. only From for "after 17:00".
+type DeliveryWindow struct {
+	Date time.Time // date only
+	From *string
+	To   *string
+}
+
+// DateString returns the date as YYYY-MM-DD.
+func (w *DeliveryWindow) DateString() string {
+	return w.Date.Format("2006-01-02")
+}
+
+// TimeRange renders the time bounds, e.g. "17:00-21:00", "after 17:00" or "before 12:00"; empty when there are none.
+func (w *DeliveryWindow) TimeRange() string {
+	switch {
+	case w.From != nil && w.To != nil:
+		return *w.From + "-" + *w.To
+	case w.From != nil:
+		return "after " + *w.From
+	case w.To != nil:
+		return "before " + *w.To
+	}
+	return ""
+}
+
 // SupplierOrderItem represents an item in a supplier order
 type SupplierOrderItem struct {
-	ID               uuid.UUID
-	SupplierOrderID  uuid.UUID
-	SKU              string
-	Title            string
-	Price            float64
-	Quantity         int
-	ProductURL       *string
-	IsSupplierItem   bool
-	ShopifyVariantID *int64
-	CreatedAt        time.Time
+	ID                uuid.UUID
+	SupplierOrderID   uuid.UUID
+	SKU               string
+	Title             string
+	Price             Money
+	Quantity          int
+	ProductURL        *string
+	IsSupplierItem    bool
+	ShopifyVariantID  *int64
+	FulfilledQuantity int    // Quantity shipped so far (Shopify fulfillments)
+	ListPrice         *Money // Shopify price when priced from the partner's price list
+	WholesalePrice    *Money // agreed price from the partner's price list (Shopify gets the difference as a discount)
+	CreatedAt         time.Time
+}
+
+// OrderItemFulfillment records a quantity of an order item shipped under one Shopify fulfillment
+type OrderItemFulfillment struct {
+	ID                   uuid.UUID
+	SupplierOrderID      uuid.UUID
+	SupplierOrderItemID  uuid.UUID
+	ShopifyFulfillmentID int64
+	Quantity             int
+	TrackingCarrier      *string
+	TrackingNumber       *string
+	TrackingURL          *string
+	CreatedAt            time.Time
+}
+
+// OrderReturn is a return (RMA) for an order. Opened by a Wassel return status or by the partner;
+// supplier staff approve (optionally restocking in Shopify) or reject it.
+type OrderReturn struct {
+	ID                  uuid.UUID
+	SupplierOrderID     uuid.UUID
+	Status              ReturnStatus
+	Source              string // "wassel" or "partner"
+	Reason              ReturnReason
+	Note                *string
+	WasselStatus        *int
+	PreviousOrderStatus OrderStatus // Restored when the return is rejected
+	Restock             bool
+	DecisionNote        *string
+	DecidedByStaffID    *uuid.UUID
+	DecidedAt           *time.Time
+	Items               []*OrderReturnItem
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// OrderNote is one message in an order's note thread (partner <-> supplier staff)
+type OrderNote struct {
+	ID              uuid.UUID
+	SupplierOrderID uuid.UUID
+	AuthorType      NoteAuthorType
+	PartnerID       *uuid.UUID // set when AuthorType is partner
+	StaffID         *uuid.UUID // set when AuthorType is staff
+	Body            string
+	CreatedAt       time.Time
+}
+
+// CODLedgerEntry is one cash-on-delivery movement on an order: cash collected by Wassel on delivery, or a refund
+// owed for items returned after collection. Amount is always positive; EntryType gives the direction.
+type CODLedgerEntry struct {
+	ID              uuid.UUID
+	PartnerID       uuid.UUID
+	SupplierOrderID uuid.UUID
+	PartnerOrderID  string // filled by listings (joined from the order)
+	OrderReturnID   *uuid.UUID
+	EntryType       CODEntryType
+	Amount          Money
+	Waybill         *string
+	RemittanceID    *uuid.UUID // set once Wassel has paid the collected cash over
+	OccurredAt      time.Time
+	CreatedAt       time.Time
+}
+
+// CODRemittance is a payout from Wassel covering the cash collected for a set of waybills
+type CODRemittance struct {
+	ID                uuid.UUID
+	Reference         string
+	Amount            Money
+	RemittedAt        time.Time
+	Note              *string
+	RecordedByStaffID *uuid.UUID
+	CreatedAt         time.Time
+}
+
+// Invoice is the tax invoice issued for an order. Sequence numbers invoices without gaps; Number is the printed
+// invoice number. Amounts cover the order's supplier items (prices include tax; Tax is the part of Total at
+// TaxRate percent). PDF is the document as issued. Invoices never change once issued.
+type Invoice struct {
+	ID              uuid.UUID
+	Sequence        int64
+	Number          string
+	SupplierOrderID uuid.UUID
+	PartnerID       uuid.UUID
+	Currency        string
+	Subtotal        Money // excluding tax
+	TaxRate         float64
+	Tax             Money
+	Total           Money
+	PDF             []byte
+	IssuedAt        time.Time
+	CreatedAt       time.Time
+}
+
+// OrderReturnItem is a quantity of an order item included in a return
+type OrderReturnItem struct {
+	ID                  uuid.UUID
+	OrderReturnID       uuid.UUID
+	SupplierOrderItemID uuid.UUID
+	Quantity            int
+	CreatedAt           time.Time
 }
 
 // IdempotencyKey stores idempotency information
@@ -81,6 +234,84 @@ type SKUMapping struct {
 	UpdatedAt        time.Time
 }
 
+// PartnerPrice is a partner price list rule: an agreed price for one SKU, or for every SKU of a catalog collection,
+// as a fixed price or a percentage off the Shopify price. Exactly one of SKU/CollectionHandle and one of
+// FixedPrice/DiscountPercent is set. ValidFrom/ValidTo are inclusive dates; nil is open-ended.
+type PartnerPrice struct {
+	ID               uuid.UUID
+	PartnerID        uuid.UUID
+	SKU              *string
+	CollectionHandle *string
+	FixedPrice       *Money
+	DiscountPercent  *float64
+	ValidFrom        *time.Time
+	ValidTo          *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// ActiveOn reports whether the rule is in effect on day (compared by calendar date).
+func (p *PartnerPrice) ActiveOn(day time.Time) bool {
+	date := day.Format("2006-01-02")
+	if p.ValidFrom != nil && date < p.ValidFrom.Format("2006-01-02") {
+		return false
+	}
+	if p.ValidTo != nil && date > p.ValidTo.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// PartnerOrderRule is one of a partner's auto-confirmation rules. New orders are checked against the partner's
+// rules in priority order (lowest first); the first rule whose conditions all hold fires. A nil or empty condition
+// always holds.
+type PartnerOrderRule struct {
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+	Name      string
+	Action    OrderRuleAction
+	Priority  int
+	// Conditions
+	MinCartTotal   *Money
+	MaxCartTotal   *Money
+	CityCodes      []string // gazetteer city codes of the shipping city
+	MinItemCount   *int     // total quantity of the order's items
+	MaxItemCount   *int
+	MinDelivered   *int     // customer's earlier delivered orders (any partner)
+	MaxReturned    *int     // customer's earlier orders returned to shipper (RTO, any partner)
+	PaymentMethods []string // compared case-insensitively; orders without a payment method never match
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// PartnerSLAThreshold overrides the SLA_THRESHOLDS default for one of a partner's order statuses or Wassel
+// delivery statuses; exactly one of OrderStatus and DeliveryStatus is set. MaxMinutes 0 turns the check off.
+type PartnerSLAThreshold struct {
+	ID             uuid.UUID
+	PartnerID      uuid.UUID
+	OrderStatus    *OrderStatus
+	DeliveryStatus *int
+	MaxMinutes     int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SLABreach is an order found by the SLA monitor to have stayed in an order status (or, for shipped orders, at a
+// Wassel delivery status) longer than its threshold. It stays open until the order moves on.
+type SLABreach struct {
+	ID               uuid.UUID
+	SupplierOrderID  uuid.UUID
+	PartnerID        uuid.UUID
+	PartnerOrderID   string      // loaded with the breach, not stored
+	OrderStatus      OrderStatus // order status when the breach was found
+	DeliveryStatus   *int        // set for breaches of a delivery status threshold
+	Since            time.Time   // when the order entered the status
+	ThresholdMinutes int
+	DetectedAt       time.Time
+	AlertedAt        *time.Time // when staff were sent the breach (nil until the alert webhook succeeds)
+	ResolvedAt       *time.Time
+}
+
 // PartnerSKUMapping maps partner-scoped SKUs to Shopify variants
 type PartnerSKUMapping struct {
 	ID               uuid.UUID
@@ -89,7 +320,7 @@ type PartnerSKUMapping struct {
 	ShopifyProductID int64
 	ShopifyVariantID int64
 	Title            *string
-	Price            *string
+	Price            *Money // synced Shopify variant price
 	ImageURL         *string
 	IsActive         bool
 	CreatedAt        time.Time
@@ -100,7 +331,7 @@ type PartnerSKUMapping struct {
 type OrderEvent struct {
 	ID              uuid.UUID
 	SupplierOrderID uuid.UUID
-	EventType       string
+	EventType       OrderEventType
 	EventData       map[string]interface{} // JSONB
 	CreatedAt       time.Time
 }
@@ -0,0 +1,315 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the shop currency: Shopify prices are in it, and partners and orders that do not name a
+// currency use it. Amounts are not converted between currencies, so it is also the only currency a partner can have.
+const DefaultCurrency = "JOD"
+
+// millsPerUnit is how many stored subunits make one currency unit: amounts are exact to three decimals, the
+// precision of JOD (fils) and of the NUMERIC(14, 3) money columns.
+const millsPerUnit = 1000
+
+// Money is an exact decimal amount in an ISO 4217 currency, held as an integer number of thousandths of the
+// currency unit. An empty currency means the amount is in the currency of the partner or order it belongs to
+// (item prices are stored without one). Stored in NUMERIC columns and written to JSON as a plain number.
+type Money struct {
+	mills    int64
+	currency string
+}
+
+// NewMoney returns mills thousandths of currency.
+func NewMoney(mills int64, currency string) Money {
+	return Money{mills: mills, currency: currency}
+}
+
+// MoneyFromFloat converts a float amount, rounded to the nearest thousandth.
+func MoneyFromFloat(amount float64, currency string) Money {
+	return Money{mills: int64(math.Round(amount * millsPerUnit)), currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "12.5", "-3.125" or "1e3". Digits past the third decimal are
+// rounded half away from zero.
+func ParseMoney(s, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	mills, ok := parseMills(s)
+	if !ok {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) || math.Abs(f) > math.MaxInt64/millsPerUnit {
+			return Money{}, fmt.Errorf("invalid amount %q", s)
+		}
+		return MoneyFromFloat(f, currency), nil
+	}
+	return Money{mills: mills, currency: currency}, nil
+}
+
+// parseMills parses a plain decimal ([-+]digits[.digits]) exactly. Reports false for anything else, including
+// exponents and amounts out of range.
+func parseMills(s string) (int64, bool) {
+	negative := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, false
+	}
+	for _, part := range []string{whole, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return 0, false
+			}
+		}
+	}
+
+	var units int64
+	if whole != "" {
+		var err error
+		if units, err = strconv.ParseInt(whole, 10, 64); err != nil || units > math.MaxInt64/millsPerUnit-1 {
+			return 0, false
+		}
+	}
+	var mills int64
+	for i := 0; i < 3; i++ {
+		mills *= 10
+		if i < len(frac) {
+			mills += int64(frac[i] - '0')
+		}
+	}
+	if len(frac) > 3 && frac[3] >= '5' {
+		mills++
+	}
+	mills += units * millsPerUnit
+	if negative {
+		mills = -mills
+	}
+	return mills, true
+}
+
+// Currency returns the ISO 4217 code; empty when the amount takes its owner's currency.
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// In returns the same amount in currency.
+func (m Money) In(currency string) Money {
+	m.currency = currency
+	return m
+}
+
+// Mills returns the amount in thousandths of the currency unit.
+func (m Money) Mills() int64 {
+	return m.mills
+}
+
+// combine returns the currency of an operation on m and other. An empty currency takes the other's; amounts in
+// two different currencies cannot be combined and panic, as that is a programming error.
+func (m Money) combine(other Money) string {
+	switch {
+	case m.currency == "":
+		return other.currency
+	case other.currency == "" || other.currency == m.currency:
+		return m.currency
+	}
+	panic(fmt.Sprintf("money: mixing %s and %s amounts", m.currency, other.currency))
+}
+
+// Add returns m + other.
+func (m Money) Add(other Money) Money {
+	return Money{mills: m.mills + other.mills, currency: m.combine(other)}
+}
+
+// Sub returns m - other.
+func (m Money) Sub(other Money) Money {
+	return Money{mills: m.mills - other.mills, currency: m.combine(other)}
+}
+
+// Mul returns m times a quantity.
+func (m Money) Mul(quantity int) Money {
+	return Money{mills: m.mills * int64(quantity), currency: m.currency}
+}
+
+// Percent returns percent % of m, rounded to the currency's precision (see Round).
+func (m Money) Percent(percent float64) Money {
+	return Money{mills: int64(math.Round(float64(m.mills) * percent / 100)), currency: m.currency}.Round()
+}
+
+// Cmp compares m with other: -1 if m is less, 0 if equal, +1 if greater.
+func (m Money) Cmp(other Money) int {
+	m.combine(other)
+	switch {
+	case m.mills < other.mills:
+		return -1
+	case m.mills > other.mills:
+		return 1
+	}
+	return 0
+}
+
+// Equal reports whether m and other are the same amount.
+func (m Money) Equal(other Money) bool {
+	return m.Cmp(other) == 0
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.mills == 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.mills < 0
+}
+
+// Min returns the smaller of m and other.
+func (m Money) Min(other Money) Money {
+	if m.Cmp(other) <= 0 {
+		return m
+	}
+	return other.In(m.combine(other))
+}
+
+// Decimals returns the number of decimals of the currency's minor unit: 3 for the dinar currencies (JOD, KWD,
+// BHD, IQD, LYD, TND) and OMR, 0 for JPY and KRW, 2 otherwise. Amounts without a currency use the default one.
+func (m Money) Decimals() int {
+	currency := m.currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	switch strings.ToUpper(currency) {
+	case "JOD", "KWD", "BHD", "IQD", "LYD", "TND", "OMR":
+		return 3
+	case "JPY", "KRW":
+		return 0
+	}
+	return 2
+}
+
+// Round rounds the amount to the currency's minor unit, half away from zero.
+func (m Money) Round() Money {
+	step := int64(1)
+	for i := m.Decimals(); i < 3; i++ {
+		step *= 10
+	}
+	if step == 1 {
+		return m
+	}
+	half := step / 2
+	if m.mills < 0 {
+		half = -half
+	}
+	m.mills = (m.mills + half) / step * step
+	return m
+}
+
+// Float64 returns the amount as a float, for display and logging only.
+func (m Money) Float64() float64 {
+	return float64(m.mills) / millsPerUnit
+}
+
+// String formats the amount with the currency's decimals (rounded), e.g. "12.500" for JOD or "12.50" for USD.
+func (m Money) String() string {
+	return formatMills(m.Round().mills, m.Decimals())
+}
+
+// Display formats the amount followed by its currency, e.g. "12.500 JOD".
+func (m Money) Display() string {
+	if m.currency == "" {
+		return m.String()
+	}
+	return m.String() + " " + m.currency
+}
+
+// exact formats the amount with as few decimals as needed (at most 3), e.g. "12.5".
+func (m Money) exact() string {
+	decimals := 3
+	for step := int64(10); decimals > 0 && m.mills%step == 0; step *= 10 {
+		decimals--
+	}
+	return formatMills(m.mills, decimals)
+}
+
+// formatMills formats an amount in thousandths with decimals (0-3) decimals, truncating the rest; callers round
+// first.
+func formatMills(mills int64, decimals int) string {
+	for i := decimals; i < 3; i++ {
+		mills /= 10
+	}
+	negative := mills < 0
+	if negative {
+		mills = -mills
+	}
+	digits := strconv.FormatInt(mills, 10)
+	if decimals > 0 {
+		for len(digits) <= decimals {
+			digits = "0" + digits
+		}
+		digits = digits[:len(digits)-decimals] + "." + digits[len(digits)-decimals:]
+	}
+	if negative {
+		digits = "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON writes the exact amount as a JSON number.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.exact()), nil
+}
+
+// UnmarshalJSON reads a JSON number or numeric string. The currency is left unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseMoney(s, m.currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount as an exact decimal string for NUMERIC columns.
+func (m Money) Value() (driver.Value, error) {
+	return m.exact(), nil
+}
+
+// Scan reads a NUMERIC column (lib/pq returns []byte). The currency is left unchanged.
+func (m *Money) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		m.mills = v * millsPerUnit
+		return nil
+	case float64:
+		m.mills = MoneyFromFloat(v, "").mills
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+	parsed, err := ParseMoney(s, m.currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
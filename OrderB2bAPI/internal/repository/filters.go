@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// OrderListFilter selects orders for SupplierOrderRepository.ListOrders. Zero-valued fields are not applied.
+type OrderListFilter struct {
+	PartnerID      *uuid.UUID
+	Statuses       []domain.OrderStatus
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	UpdatedFrom    *time.Time
+	UpdatedTo      *time.Time
+	CustomerPhone  string
+	ShopifyOrderID string
+	DeliveryStatus *int
+	// StatusChangedBefore and LastDeliveryBefore select orders that have been in their status, or at their last
+	// delivery status, since before the given time
+	StatusChangedBefore *time.Time
+	LastDeliveryBefore  *time.Time
+	// Search matches customer name (case-insensitive substring)
+	Search string
+	// After continues a listing from the last order of the previous page
+	After *OrderCursor
+	Limit int
+	// SkipTotal leaves OrderPage.Total at 0 (saves the COUNT query when paging through everything)
+	SkipTotal bool
+}
+
+// OrderPage is one page of ListOrders. Total counts every order matching the filter, ignoring the cursor.
+type OrderPage struct {
+	Orders []*domain.SupplierOrder
+	Total  int
+	// Next is set when more orders follow this page
+	Next *OrderCursor
+}
+
+// OrderCursor is a keyset position in the order listing (newest first: created_at DESC, id DESC).
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorAfter returns the cursor positioned after order.
+func CursorAfter(order *domain.SupplierOrder) *OrderCursor {
+	return &OrderCursor{CreatedAt: order.CreatedAt, ID: order.ID}
+}
+
+// Encode returns the opaque cursor string handed to API clients.
+func (c *OrderCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeOrderCursor parses a cursor produced by OrderCursor.Encode.
+func DecodeOrderCursor(s string) (*OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &OrderCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// CODTotals sums a partner's COD ledger entries over a period (CODLedgerRepository.Totals).
+type CODTotals struct {
+	CollectedCount int
+	Collected      domain.Money
+	// Remitted is the part of Collected that Wassel has already paid over
+	Remitted      domain.Money
+	ReturnedCount int
+	Returned      domain.Money
+}
+
+// CreditExposure is what a partner owes on its open unpaid orders (SupplierOrderRepository.CreditExposure).
+type CreditExposure struct {
+	Orders int
+	Amount domain.Money
+}
+
+// CustomerHistory counts a customer's earlier orders, across partners, by outcome
+// (SupplierOrderRepository.CustomerHistory).
+type CustomerHistory struct {
+	Delivered int // delivered (COMPLETE or Wassel status 170)
+	Returned  int // returned to shipper (a return with reason return_to_shipper, i.e. RTO)
+}
+
+// SLABreachFilter selects breaches for SLABreachRepository.List, most recently detected first. Zero-valued fields
+// are not applied.
+type SLABreachFilter struct {
+	PartnerID *uuid.UUID
+	// Open selects unresolved (true) or resolved (false) breaches
+	Open  *bool
+	Limit int
+}
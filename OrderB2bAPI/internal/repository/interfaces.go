@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jafarshop/b2bapi/internal/domain"
@@ -17,24 +18,45 @@ type PartnerRepository interface {
 	Update(ctx context.Context, partner *domain.Partner) error
 }
 
+// SupplierStaffRepository defines supplier staff data access methods
+type SupplierStaffRepository interface {
+	GetByAPIKey(ctx context.Context, apiKey string) (*domain.SupplierStaff, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierStaff, error)
+	List(ctx context.Context) ([]*domain.SupplierStaff, error)
+	Create(ctx context.Context, staff *domain.SupplierStaff) error
+}
+
 // SupplierOrderRepository defines supplier order data access methods
 type SupplierOrderRepository interface {
 	Create(ctx context.Context, order *domain.SupplierOrder) error
+	// CreateWithinCredit creates the order after check has accepted the partner's credit exposure (summed over
+	// statuses), holding a lock on the partner so concurrent orders are checked one at a time. An error from check
+	// is returned as is and nothing is created.
+	CreateWithinCredit(ctx context.Context, order *domain.SupplierOrder, statuses []domain.OrderStatus, check func(*CreditExposure) error) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error)
 	GetByPartnerIDAndPartnerOrderID(ctx context.Context, partnerID uuid.UUID, partnerOrderID string) (*domain.SupplierOrder, error)
 	GetByPartnerOrderID(ctx context.Context, partnerOrderID string) (*domain.SupplierOrder, error)
 	GetByShopifyOrderID(ctx context.Context, shopifyOrderID string) (*domain.SupplierOrder, error)
 	GetByShopifyOrderIDPreferredPartner(ctx context.Context, shopifyOrderID string, excludePartnerID uuid.UUID) (*domain.SupplierOrder, error)
 	Update(ctx context.Context, order *domain.SupplierOrder) error
-	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus, rejectionReason *string) error
-	UpdateStatusFromShopify(ctx context.Context, id uuid.UUID, status domain.OrderStatus) error
+	// Amend stores an amended INCOMPLETE_CAUTION order's shipping address and cart total and, when items is not
+	// nil, replaces its items, in one transaction. Returns ErrConflict when the order is no longer INCOMPLETE_CAUTION.
+	Amend(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) error
+	// AmendWithinCredit amends the order as Amend after check has accepted the partner's credit exposure without
+	// this order, holding the same lock on the partner as CreateWithinCredit.
+	AmendWithinCredit(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem, statuses []domain.OrderStatus, check func(*CreditExposure) error) error
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.OrderStatus, rejectionReason *string) (bool, error)
+	CountUnknownStatuses(ctx context.Context, known []domain.OrderStatus) (map[domain.OrderStatus]int, error)
 	UpdateTracking(ctx context.Context, id uuid.UUID, carrier, trackingNumber, trackingURL *string) error
 	UpdateLastDeliveryStatus(ctx context.Context, id uuid.UUID, status int, statusLabel, waybill, imageURL string) error
 	UpdateShopifyDraftOrderID(ctx context.Context, id uuid.UUID, draftOrderID int64) error
 	UpdateShopifyOrderID(ctx context.Context, id uuid.UUID, orderID string) error
-	ListByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*domain.SupplierOrder, error)
-	ListByPartnerIDAndStatus(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error)
-	ListByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error)
+	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string) error
+	UpdateHoldReason(ctx context.Context, id uuid.UUID, holdReason *string) error
+	MarkPossibleDuplicate(ctx context.Context, id, duplicateOf uuid.UUID, holdReason string) error
+	CreditExposure(ctx context.Context, partnerID uuid.UUID, statuses []domain.OrderStatus, excludeID uuid.UUID) (*CreditExposure, error)
+	CustomerHistory(ctx context.Context, customerPhone string, before time.Time, excludeID uuid.UUID) (*CustomerHistory, error)
+	ListOrders(ctx context.Context, filter OrderListFilter) (*OrderPage, error)
 }
 
 // SupplierOrderItemRepository defines order item data access methods
@@ -42,6 +64,9 @@ type SupplierOrderItemRepository interface {
 	Create(ctx context.Context, item *domain.SupplierOrderItem) error
 	CreateBatch(ctx context.Context, items []*domain.SupplierOrderItem) error
 	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.SupplierOrderItem, error)
+	GetByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]*domain.SupplierOrderItem, error)
+	RecordFulfillment(ctx context.Context, f *domain.OrderItemFulfillment) (bool, error)
+	ListFulfillmentsByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderItemFulfillment, error)
 }
 
 // IdempotencyKeyRepository defines idempotency key data access methods
@@ -64,6 +89,37 @@ type SKUMappingRepository interface {
 type OrderEventRepository interface {
 	Create(ctx context.Context, event *domain.OrderEvent) error
 	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderEvent, error)
+	ListByOrderID(ctx context.Context, orderID uuid.UUID, eventTypes []domain.OrderEventType, limit, offset int) ([]*domain.OrderEvent, error)
+	CountByOrderID(ctx context.Context, orderID uuid.UUID, eventTypes []domain.OrderEventType) (int, error)
+	GetLatestByOrderID(ctx context.Context, orderID uuid.UUID, eventType domain.OrderEventType) (*domain.OrderEvent, error)
+}
+
+// OrderReturnRepository defines order return (RMA) data access methods
+type OrderReturnRepository interface {
+	Create(ctx context.Context, ret *domain.OrderReturn) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderReturn, error)
+	GetOpenByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.OrderReturn, error)
+	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderReturn, error)
+	ListByStatus(ctx context.Context, status domain.ReturnStatus, limit, offset int) ([]*domain.OrderReturn, error)
+	UpdateDecision(ctx context.Context, ret *domain.OrderReturn) error
+}
+
+// OrderNoteRepository defines order note (message thread) data access methods
+type OrderNoteRepository interface {
+	Create(ctx context.Context, note *domain.OrderNote) error
+	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderNote, error)
+}
+
+// CODLedgerRepository defines cash-on-delivery ledger and Wassel remittance data access methods
+type CODLedgerRepository interface {
+	CreateEntry(ctx context.Context, entry *domain.CODLedgerEntry) (bool, error)
+	// CreateReturnEntry inserts a returned entry for entry.OrderReturnID, capping entry.Amount so the order's
+	// refunds never exceed the cash collected for it
+	CreateReturnEntry(ctx context.Context, entry *domain.CODLedgerEntry) (bool, error)
+	GetCollectedByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.CODLedgerEntry, error)
+	ListEntries(ctx context.Context, partnerID uuid.UUID, from, to time.Time) ([]*domain.CODLedgerEntry, error)
+	Totals(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (*CODTotals, error)
+	CreateRemittance(ctx context.Context, remittance *domain.CODRemittance, waybills []string) ([]*domain.CODLedgerEntry, error)
 }
 
 // PartnerSKUMappingRepository defines partner-scoped SKU mapping data access
@@ -74,13 +130,67 @@ type PartnerSKUMappingRepository interface {
 	UpsertBatch(ctx context.Context, partnerID uuid.UUID, mappings []*domain.PartnerSKUMapping) error
 }
 
+// PartnerPriceRepository defines partner price list data access methods
+type PartnerPriceRepository interface {
+	Create(ctx context.Context, price *domain.PartnerPrice) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PartnerPrice, error)
+	ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.PartnerPrice, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// PartnerOrderRuleRepository defines partner auto-confirmation rule data access methods
+type PartnerOrderRuleRepository interface {
+	Create(ctx context.Context, rule *domain.PartnerOrderRule) error
+	ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.PartnerOrderRule, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// PartnerSLAThresholdRepository defines per-partner SLA threshold data access methods
+type PartnerSLAThresholdRepository interface {
+	// Upsert sets the partner's threshold for the threshold's order status or delivery status
+	Upsert(ctx context.Context, threshold *domain.PartnerSLAThreshold) error
+	// Delete removes the partner's threshold for an order status or a delivery status (the default applies again)
+	Delete(ctx context.Context, partnerID uuid.UUID, orderStatus *domain.OrderStatus, deliveryStatus *int) error
+	List(ctx context.Context) ([]*domain.PartnerSLAThreshold, error)
+}
+
+// SLABreachRepository defines SLA breach data access methods
+type SLABreachRepository interface {
+	// Create records a breach; false when an open breach with the same order, status and since already exists
+	Create(ctx context.Context, breach *domain.SLABreach) (bool, error)
+	// ListOpen returns every unresolved breach
+	ListOpen(ctx context.Context) ([]*domain.SLABreach, error)
+	List(ctx context.Context, filter SLABreachFilter) ([]*domain.SLABreach, error)
+	Resolve(ctx context.Context, id uuid.UUID, resolvedAt time.Time) error
+	MarkAlerted(ctx context.Context, ids []uuid.UUID, alertedAt time.Time) error
+}
+
+// InvoiceRepository defines invoice data access methods. Invoices are only ever added, never changed.
+type InvoiceRepository interface {
+	// Issue takes the next invoice sequence number, calls render (which sets Number and PDF) and stores the
+	// invoice, in one transaction so numbers have no gaps. Returns ErrConflict when the order already has one.
+	Issue(ctx context.Context, invoice *domain.Invoice, render func(*domain.Invoice) error) error
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.Invoice, error)
+	// ListByOrderIDs returns the invoices of the given orders by order ID, without their PDF.
+	ListByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID]*domain.Invoice, error)
+}
+
 // Repositories aggregates all repositories
 type Repositories struct {
-	Partner           PartnerRepository
-	SupplierOrder     SupplierOrderRepository
-	SupplierOrderItem SupplierOrderItemRepository
-	IdempotencyKey    IdempotencyKeyRepository
-	SKUMapping        SKUMappingRepository
-	PartnerSKUMapping PartnerSKUMappingRepository
-	OrderEvent        OrderEventRepository
+	Partner             PartnerRepository
+	SupplierStaff       SupplierStaffRepository
+	SupplierOrder       SupplierOrderRepository
+	SupplierOrderItem   SupplierOrderItemRepository
+	IdempotencyKey      IdempotencyKeyRepository
+	SKUMapping          SKUMappingRepository
+	PartnerSKUMapping   PartnerSKUMappingRepository
+	PartnerPrice        PartnerPriceRepository
+	PartnerOrderRule    PartnerOrderRuleRepository
+	PartnerSLAThreshold PartnerSLAThresholdRepository
+	SLABreach           SLABreachRepository
+	OrderEvent          OrderEventRepository
+	OrderReturn         OrderReturnRepository
+	OrderNote           OrderNoteRepository
+	CODLedger           CODLedgerRepository
+	Invoice             InvoiceRepository
 }
@@ -0,0 +1,335 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type codLedgerRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewCODLedgerRepository creates a new COD ledger repository
+func NewCODLedgerRepository(db *sql.DB, logger *zap.Logger) *codLedgerRepository {
+	return &codLedgerRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const codLedgerColumns = `
+	e.id, e.partner_id, e.supplier_order_id, o.partner_order_id, e.order_return_id, e.entry_type,
+	e.amount, e.waybill, e.remittance_id, e.occurred_at, e.created_at
+`
+
+// CreateEntry inserts the entry. Returns false (and leaves entry.ID unset) when the order already has a
+// collection, or the return already has a refund entry.
+func (r *codLedgerRepository) CreateEntry(ctx context.Context, entry *domain.CODLedgerEntry) (bool, error) {
+	query := `
+		INSERT INTO cod_ledger_entries (
+			id, partner_id, supplier_order_id, order_return_id, entry_type, amount, waybill, occurred_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT DO NOTHING
+	`
+
+	id := uuid.New()
+	now := time.Now()
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = now
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		id,
+		entry.PartnerID,
+		entry.SupplierOrderID,
+		entry.OrderReturnID,
+		entry.EntryType,
+		entry.Amount,
+		entry.Waybill,
+		entry.OccurredAt,
+		now,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create COD ledger entry", zap.Error(err))
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	entry.ID = id
+	entry.CreatedAt = now
+	return true, nil
+}
+
+// CreateReturnEntry locks the order's collection, so concurrent refunds for the order are capped one after the
+// other, and inserts the entry with Amount reduced to the collected cash not yet refunded. Returns false when
+// nothing is left to refund, the order has no collection, or the return already has a refund entry.
+func (r *codLedgerRepository) CreateReturnEntry(ctx context.Context, entry *domain.CODLedgerEntry) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var collected, returned domain.Money
+	err = tx.QueryRowContext(ctx, `
+		SELECT amount, waybill FROM cod_ledger_entries
+		WHERE supplier_order_id = $1 AND entry_type = $2
+		FOR UPDATE
+	`, entry.SupplierOrderID, domain.CODEntryCollected).Scan(&collected, &entry.Waybill)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to lock COD collection", zap.Error(err))
+		return false, err
+	}
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM cod_ledger_entries
+		WHERE supplier_order_id = $1 AND entry_type = $2
+	`, entry.SupplierOrderID, domain.CODEntryReturned).Scan(&returned)
+	if err != nil {
+		r.logger.Error("Failed to sum COD refunds", zap.Error(err))
+		return false, err
+	}
+
+	entry.EntryType = domain.CODEntryReturned
+	entry.Amount = entry.Amount.Min(collected.Sub(returned))
+	if entry.Amount.Cmp(domain.Money{}) <= 0 {
+		return false, nil
+	}
+
+	id := uuid.New()
+	now := time.Now()
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = now
+	}
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO cod_ledger_entries (
+			id, partner_id, supplier_order_id, order_return_id, entry_type, amount, waybill, occurred_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT DO NOTHING
+	`,
+		id,
+		entry.PartnerID,
+		entry.SupplierOrderID,
+		entry.OrderReturnID,
+		entry.EntryType,
+		entry.Amount,
+		entry.Waybill,
+		entry.OccurredAt,
+		now,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create COD refund entry", zap.Error(err))
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	entry.ID = id
+	entry.CreatedAt = now
+	return true, nil
+}
+
+func (r *codLedgerRepository) GetCollectedByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.CODLedgerEntry, error) {
+	query := `
+		SELECT ` + codLedgerColumns + `
+		FROM cod_ledger_entries e
+		JOIN supplier_orders o ON o.id = e.supplier_order_id
+		WHERE e.supplier_order_id = $1 AND e.entry_type = $2
+	`
+
+	entry, err := scanCODLedgerEntry(r.db.QueryRowContext(ctx, query, orderID, domain.CODEntryCollected))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "cod_ledger_entry", ID: orderID.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get COD collection", zap.Error(err))
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ListEntries returns the partner's entries with occurred_at in [from, to), oldest first.
+func (r *codLedgerRepository) ListEntries(ctx context.Context, partnerID uuid.UUID, from, to time.Time) ([]*domain.CODLedgerEntry, error) {
+	query := `
+		SELECT ` + codLedgerColumns + `
+		FROM cod_ledger_entries e
+		JOIN supplier_orders o ON o.id = e.supplier_order_id
+		WHERE e.partner_id = $1 AND e.occurred_at >= $2 AND e.occurred_at < $3
+		ORDER BY e.occurred_at ASC, e.id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID, from, to)
+	if err != nil {
+		r.logger.Error("Failed to list COD ledger entries", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.CODLedgerEntry
+	for rows.Next() {
+		entry, err := scanCODLedgerEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// Totals sums the partner's entries with occurred_at in [from, to).
+func (r *codLedgerRepository) Totals(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (*repository.CODTotals, error) {
+	query := `
+		SELECT entry_type, COUNT(*), COALESCE(SUM(amount), 0),
+			COALESCE(SUM(amount) FILTER (WHERE remittance_id IS NOT NULL), 0)
+		FROM cod_ledger_entries
+		WHERE partner_id = $1 AND occurred_at >= $2 AND occurred_at < $3
+		GROUP BY entry_type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID, from, to)
+	if err != nil {
+		r.logger.Error("Failed to sum COD ledger entries", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals repository.CODTotals
+	for rows.Next() {
+		var entryType domain.CODEntryType
+		var count int
+		var amount, remitted domain.Money
+		if err := rows.Scan(&entryType, &count, &amount, &remitted); err != nil {
+			return nil, err
+		}
+		switch entryType {
+		case domain.CODEntryCollected:
+			totals.CollectedCount = count
+			totals.Collected = amount
+			totals.Remitted = remitted
+		case domain.CODEntryReturned:
+			totals.ReturnedCount = count
+			totals.Returned = amount
+		}
+	}
+	return &totals, rows.Err()
+}
+
+// CreateRemittance stores the remittance and links it to the not yet remitted collections for waybills, in one
+// transaction. Returns the linked entries. Returns ErrConflict when the reference was already recorded.
+func (r *codLedgerRepository) CreateRemittance(ctx context.Context, remittance *domain.CODRemittance, waybills []string) ([]*domain.CODLedgerEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if remittance.ID == uuid.Nil {
+		remittance.ID = uuid.New()
+	}
+	remittance.CreatedAt = time.Now()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO cod_remittances (id, reference, amount, remitted_at, note, recorded_by_staff_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (reference) DO NOTHING
+	`,
+		remittance.ID,
+		remittance.Reference,
+		remittance.Amount,
+		remittance.RemittedAt,
+		remittance.Note,
+		remittance.RecordedByStaffID,
+		remittance.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create COD remittance", zap.Error(err))
+		return nil, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, &errors.ErrConflict{Message: "remittance reference already recorded"}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		WITH linked AS (
+			UPDATE cod_ledger_entries
+			SET remittance_id = $1
+			WHERE entry_type = $2 AND remittance_id IS NULL AND waybill = ANY($3)
+			RETURNING *
+		)
+		SELECT `+codLedgerColumns+`
+		FROM linked e
+		JOIN supplier_orders o ON o.id = e.supplier_order_id
+		ORDER BY e.occurred_at ASC
+	`, remittance.ID, domain.CODEntryCollected, pq.Array(waybills))
+	if err != nil {
+		r.logger.Error("Failed to link COD collections to remittance", zap.Error(err))
+		return nil, err
+	}
+	var linked []*domain.CODLedgerEntry
+	for rows.Next() {
+		entry, err := scanCODLedgerEntry(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		linked = append(linked, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return linked, tx.Commit()
+}
+
+func scanCODLedgerEntry(row rowScanner) (*domain.CODLedgerEntry, error) {
+	var entry domain.CODLedgerEntry
+	var orderReturnID, remittanceID uuid.NullUUID
+	var waybill sql.NullString
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.PartnerID,
+		&entry.SupplierOrderID,
+		&entry.PartnerOrderID,
+		&orderReturnID,
+		&entry.EntryType,
+		&entry.Amount,
+		&waybill,
+		&remittanceID,
+		&entry.OccurredAt,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if orderReturnID.Valid {
+		entry.OrderReturnID = &orderReturnID.UUID
+	}
+	if waybill.Valid {
+		entry.Waybill = &waybill.String
+	}
+	if remittanceID.Valid {
+		entry.RemittanceID = &remittanceID.UUID
+	}
+	return &entry, nil
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type invoiceRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *sql.DB, logger *zap.Logger) *invoiceRepository {
+	return &invoiceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const invoiceColumns = `
+	id, sequence, invoice_number, supplier_order_id, partner_id, currency,
+	subtotal, tax_rate, tax, total, issued_at, created_at
+`
+
+// Issue numbers and stores the invoice. The counter row stays locked until commit, so concurrent issues are
+// serialized and a failed render or insert gives its number back.
+func (r *invoiceRepository) Issue(ctx context.Context, invoice *domain.Invoice, render func(*domain.Invoice) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var sequence int64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE invoice_counter SET last_sequence = last_sequence + 1 RETURNING last_sequence
+	`).Scan(&sequence)
+	if err != nil {
+		r.logger.Error("Failed to take invoice sequence number", zap.Error(err))
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM invoices WHERE supplier_order_id = $1)`, invoice.SupplierOrderID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return &errors.ErrConflict{Message: "order already has an invoice"}
+	}
+
+	invoice.ID = uuid.New()
+	invoice.Sequence = sequence
+	if invoice.IssuedAt.IsZero() {
+		invoice.IssuedAt = time.Now()
+	}
+	invoice.CreatedAt = time.Now()
+	if err := render(invoice); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO invoices (
+			id, sequence, invoice_number, supplier_order_id, partner_id, currency,
+			subtotal, tax_rate, tax, total, pdf, issued_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
+		invoice.ID,
+		invoice.Sequence,
+		invoice.Number,
+		invoice.SupplierOrderID,
+		invoice.PartnerID,
+		invoice.Currency,
+		invoice.Subtotal,
+		invoice.TaxRate,
+		invoice.Tax,
+		invoice.Total,
+		invoice.PDF,
+		invoice.IssuedAt,
+		invoice.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create invoice", zap.Error(err))
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *invoiceRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + `, pdf FROM invoices WHERE supplier_order_id = $1`
+
+	var invoice domain.Invoice
+	err := r.db.QueryRowContext(ctx, query, orderID).Scan(
+		&invoice.ID,
+		&invoice.Sequence,
+		&invoice.Number,
+		&invoice.SupplierOrderID,
+		&invoice.PartnerID,
+		&invoice.Currency,
+		&invoice.Subtotal,
+		&invoice.TaxRate,
+		&invoice.Tax,
+		&invoice.Total,
+		&invoice.IssuedAt,
+		&invoice.CreatedAt,
+		&invoice.PDF,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "invoice", ID: orderID.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get invoice", zap.Error(err))
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) ListByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID]*domain.Invoice, error) {
+	invoices := make(map[uuid.UUID]*domain.Invoice)
+	if len(orderIDs) == 0 {
+		return invoices, nil
+	}
+
+	ids := make([]string, len(orderIDs))
+	for i, id := range orderIDs {
+		ids[i] = id.String()
+	}
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE supplier_order_id = ANY($1::uuid[])`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		r.logger.Error("Failed to list invoices", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var invoice domain.Invoice
+		if err := rows.Scan(
+			&invoice.ID,
+			&invoice.Sequence,
+			&invoice.Number,
+			&invoice.SupplierOrderID,
+			&invoice.PartnerID,
+			&invoice.Currency,
+			&invoice.Subtotal,
+			&invoice.TaxRate,
+			&invoice.Tax,
+			&invoice.Total,
+			&invoice.IssuedAt,
+			&invoice.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		invoices[invoice.SupplierOrderID] = &invoice
+	}
+	return invoices, rows.Err()
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/domain"
@@ -29,9 +30,9 @@ func (r *supplierOrderItemRepository) Create(ctx context.Context, item *domain.S
 	query := `
 		INSERT INTO supplier_order_items (
 			id, supplier_order_id, sku, title, price, quantity,
-			product_url, is_supplier_item, shopify_variant_id, created_at
+			product_url, is_supplier_item, shopify_variant_id, list_price, wholesale_price, created_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
 	now := time.Now()
@@ -52,6 +53,8 @@ func (r *supplierOrderItemRepository) Create(ctx context.Context, item *domain.S
 		item.ProductURL,
 		item.IsSupplierItem,
 		item.ShopifyVariantID,
+		item.ListPrice,
+		item.WholesalePrice,
 		item.CreatedAt,
 	)
 
@@ -71,19 +74,19 @@ func (r *supplierOrderItemRepository) CreateBatch(ctx context.Context, items []*
 	query := `
 		INSERT INTO supplier_order_items (
 			id, supplier_order_id, sku, title, price, quantity,
-			product_url, is_supplier_item, shopify_variant_id, created_at
+			product_url, is_supplier_item, shopify_variant_id, list_price, wholesale_price, created_at
 		)
 		VALUES `
 
-	args := make([]interface{}, 0, len(items)*10)
+	args := make([]interface{}, 0, len(items)*12)
 	now := time.Now()
 
 	for i, item := range items {
 		if i > 0 {
 			query += ", "
 		}
-		query += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-			i*10+1, i*10+2, i*10+3, i*10+4, i*10+5, i*10+6, i*10+7, i*10+8, i*10+9, i*10+10)
+		query += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			i*12+1, i*12+2, i*12+3, i*12+4, i*12+5, i*12+6, i*12+7, i*12+8, i*12+9, i*12+10, i*12+11, i*12+12)
 
 		if item.ID == uuid.Nil {
 			item.ID = uuid.New()
@@ -102,6 +105,8 @@ func (r *supplierOrderItemRepository) CreateBatch(ctx context.Context, items []*
 			item.ProductURL,
 			item.IsSupplierItem,
 			item.ShopifyVariantID,
+			item.ListPrice,
+			item.WholesalePrice,
 			item.CreatedAt,
 		)
 	}
@@ -115,10 +120,14 @@ func (r *supplierOrderItemRepository) CreateBatch(ctx context.Context, items []*
 	return nil
 }
 
+const supplierOrderItemColumns = `
+	id, supplier_order_id, sku, title, price, quantity,
+	product_url, is_supplier_item, shopify_variant_id, fulfilled_quantity, list_price, wholesale_price, created_at
+`
+
 func (r *supplierOrderItemRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.SupplierOrderItem, error) {
 	query := `
-		SELECT id, supplier_order_id, sku, title, price, quantity,
-			product_url, is_supplier_item, shopify_variant_id, created_at
+		SELECT ` + supplierOrderItemColumns + `
 		FROM supplier_order_items
 		WHERE supplier_order_id = $1
 		ORDER BY created_at ASC
@@ -133,36 +142,233 @@ func (r *supplierOrderItemRepository) GetByOrderID(ctx context.Context, orderID
 
 	var items []*domain.SupplierOrderItem
 	for rows.Next() {
-		var item domain.SupplierOrderItem
-		var productURL sql.NullString
-		var shopifyVariantID sql.NullInt64
+		item, err := scanOrderItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
 
-		err := rows.Scan(
-			&item.ID,
-			&item.SupplierOrderID,
-			&item.SKU,
-			&item.Title,
-			&item.Price,
-			&item.Quantity,
-			&productURL,
-			&item.IsSupplierItem,
-			&shopifyVariantID,
-			&item.CreatedAt,
+	return items, rows.Err()
+}
+
+// GetByOrderIDs returns the items of several orders in one query, keyed by order ID.
+func (r *supplierOrderItemRepository) GetByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]*domain.SupplierOrderItem, error) {
+	itemsByOrder := make(map[uuid.UUID][]*domain.SupplierOrderItem, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return itemsByOrder, nil
+	}
+
+	ids := make([]string, len(orderIDs))
+	for i, id := range orderIDs {
+		ids[i] = id.String()
+	}
+	query := `
+		SELECT ` + supplierOrderItemColumns + `
+		FROM supplier_order_items
+		WHERE supplier_order_id = ANY($1::uuid[])
+		ORDER BY supplier_order_id, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		r.logger.Error("Failed to get supplier order items by order IDs", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanOrderItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		itemsByOrder[item.SupplierOrderID] = append(itemsByOrder[item.SupplierOrderID], item)
+	}
+
+	return itemsByOrder, rows.Err()
+}
+
+func scanOrderItem(row rowScanner) (*domain.SupplierOrderItem, error) {
+	var item domain.SupplierOrderItem
+	var productURL sql.NullString
+	var shopifyVariantID sql.NullInt64
+
+	err := row.Scan(
+		&item.ID,
+		&item.SupplierOrderID,
+		&item.SKU,
+		&item.Title,
+		&item.Price,
+		&item.Quantity,
+		&productURL,
+		&item.IsSupplierItem,
+		&shopifyVariantID,
+		&item.FulfilledQuantity,
+		&item.ListPrice,
+		&item.WholesalePrice,
+		&item.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if productURL.Valid {
+		item.ProductURL = &productURL.String
+	}
+	if shopifyVariantID.Valid {
+		item.ShopifyVariantID = &shopifyVariantID.Int64
+	}
+	return &item, nil
+}
+
+// replaceOrderItems deletes the order's items and inserts items within tx (used for order amendments).
+func replaceOrderItems(ctx context.Context, tx *sql.Tx, logger *zap.Logger, orderID uuid.UUID, items []*domain.SupplierOrderItem) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM supplier_order_items WHERE supplier_order_id = $1`, orderID); err != nil {
+		logger.Error("Failed to delete supplier order items", zap.Error(err))
+		return err
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+		if item.CreatedAt.IsZero() {
+			item.CreatedAt = now
+		}
+		item.SupplierOrderID = orderID
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO supplier_order_items (
+				id, supplier_order_id, sku, title, price, quantity,
+				product_url, is_supplier_item, shopify_variant_id, list_price, wholesale_price, created_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`,
+			item.ID,
+			item.SupplierOrderID,
+			item.SKU,
+			item.Title,
+			item.Price,
+			item.Quantity,
+			item.ProductURL,
+			item.IsSupplierItem,
+			item.ShopifyVariantID,
+			item.ListPrice,
+			item.WholesalePrice,
+			item.CreatedAt,
 		)
+		if err != nil {
+			logger.Error("Failed to insert supplier order item", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordFulfillment stores a per-item fulfillment and adds its quantity to the item's fulfilled_quantity.
+// Returns false when this Shopify fulfillment was already recorded for the item (webhook redelivery / fulfillments/update).
+func (r *supplierOrderItemRepository) RecordFulfillment(ctx context.Context, f *domain.OrderItemFulfillment) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = time.Now()
+	}
 
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO supplier_order_item_fulfillments (
+			id, supplier_order_id, supplier_order_item_id, shopify_fulfillment_id, quantity,
+			tracking_carrier, tracking_number, tracking_url, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (shopify_fulfillment_id, supplier_order_item_id) DO NOTHING
+	`,
+		f.ID,
+		f.SupplierOrderID,
+		f.SupplierOrderItemID,
+		f.ShopifyFulfillmentID,
+		f.Quantity,
+		f.TrackingCarrier,
+		f.TrackingNumber,
+		f.TrackingURL,
+		f.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create order item fulfillment", zap.Error(err))
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE supplier_order_items
+		SET fulfilled_quantity = LEAST(quantity, fulfilled_quantity + $2)
+		WHERE id = $1
+	`, f.SupplierOrderItemID, f.Quantity)
+	if err != nil {
+		r.logger.Error("Failed to update item fulfilled quantity", zap.Error(err))
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *supplierOrderItemRepository) ListFulfillmentsByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderItemFulfillment, error) {
+	query := `
+		SELECT id, supplier_order_id, supplier_order_item_id, shopify_fulfillment_id, quantity,
+			tracking_carrier, tracking_number, tracking_url, created_at
+		FROM supplier_order_item_fulfillments
+		WHERE supplier_order_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error("Failed to get order item fulfillments", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fulfillments []*domain.OrderItemFulfillment
+	for rows.Next() {
+		var f domain.OrderItemFulfillment
+		var carrier, number, url sql.NullString
+
+		err := rows.Scan(
+			&f.ID,
+			&f.SupplierOrderID,
+			&f.SupplierOrderItemID,
+			&f.ShopifyFulfillmentID,
+			&f.Quantity,
+			&carrier,
+			&number,
+			&url,
+			&f.CreatedAt,
+		)
 		if err != nil {
 			return nil, err
 		}
 
-		if productURL.Valid {
-			item.ProductURL = &productURL.String
+		if carrier.Valid {
+			f.TrackingCarrier = &carrier.String
+		}
+		if number.Valid {
+			f.TrackingNumber = &number.String
 		}
-		if shopifyVariantID.Valid {
-			item.ShopifyVariantID = &shopifyVariantID.Int64
+		if url.Valid {
+			f.TrackingURL = &url.String
 		}
 
-		items = append(items, &item)
+		fulfillments = append(fulfillments, &f)
 	}
 
-	return items, rows.Err()
+	return fulfillments, rows.Err()
 }
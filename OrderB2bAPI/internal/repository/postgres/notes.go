@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+type orderNoteRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewOrderNoteRepository creates a new order note repository
+func NewOrderNoteRepository(db *sql.DB, logger *zap.Logger) *orderNoteRepository {
+	return &orderNoteRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *orderNoteRepository) Create(ctx context.Context, note *domain.OrderNote) error {
+	query := `
+		INSERT INTO order_notes (id, supplier_order_id, author_type, partner_id, staff_id, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if note.ID == uuid.Nil {
+		note.ID = uuid.New()
+	}
+	note.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, query,
+		note.ID,
+		note.SupplierOrderID,
+		note.AuthorType,
+		note.PartnerID,
+		note.StaffID,
+		note.Body,
+		note.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create order note", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListByOrderID returns the order's notes, oldest first.
+func (r *orderNoteRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderNote, error) {
+	query := `
+		SELECT id, supplier_order_id, author_type, partner_id, staff_id, body, created_at
+		FROM order_notes
+		WHERE supplier_order_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error("Failed to list order notes", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []*domain.OrderNote
+	for rows.Next() {
+		var note domain.OrderNote
+		var partnerID, staffID uuid.NullUUID
+		if err := rows.Scan(
+			&note.ID,
+			&note.SupplierOrderID,
+			&note.AuthorType,
+			&partnerID,
+			&staffID,
+			&note.Body,
+			&note.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if partnerID.Valid {
+			note.PartnerID = &partnerID.UUID
+		}
+		if staffID.Valid {
+			note.StaffID = &staffID.UUID
+		}
+		notes = append(notes, &note)
+	}
+	return notes, rows.Err()
+}
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
 )
 
 type orderEventRepository struct {
@@ -79,6 +81,81 @@ func (r *orderEventRepository) GetByOrderID(ctx context.Context, orderID uuid.UU
 	}
 	defer rows.Close()
 
+	return scanOrderEvents(rows)
+}
+
+// ListByOrderID returns a page of the order's events, oldest first, optionally filtered to eventTypes.
+func (r *orderEventRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID, eventTypes []domain.OrderEventType, limit, offset int) ([]*domain.OrderEvent, error) {
+	query := `
+		SELECT id, supplier_order_id, event_type, event_data, created_at
+		FROM order_events
+		WHERE supplier_order_id = $1 AND (cardinality($2::text[]) = 0 OR event_type = ANY($2))
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID, pq.Array(eventTypeStrings(eventTypes)), limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to list order events", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrderEvents(rows)
+}
+
+// CountByOrderID counts the order's events, optionally filtered to eventTypes.
+func (r *orderEventRepository) CountByOrderID(ctx context.Context, orderID uuid.UUID, eventTypes []domain.OrderEventType) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM order_events
+		WHERE supplier_order_id = $1 AND (cardinality($2::text[]) = 0 OR event_type = ANY($2))
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, orderID, pq.Array(eventTypeStrings(eventTypes))).Scan(&count); err != nil {
+		r.logger.Error("Failed to count order events", zap.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetLatestByOrderID returns the order's most recent event of eventType.
+func (r *orderEventRepository) GetLatestByOrderID(ctx context.Context, orderID uuid.UUID, eventType domain.OrderEventType) (*domain.OrderEvent, error) {
+	query := `
+		SELECT id, supplier_order_id, event_type, event_data, created_at
+		FROM order_events
+		WHERE supplier_order_id = $1 AND event_type = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID, eventType)
+	if err != nil {
+		r.logger.Error("Failed to get latest order event", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	events, err := scanOrderEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, &errors.ErrNotFound{Resource: "order_event", ID: orderID.String()}
+	}
+	return events[0], nil
+}
+
+func eventTypeStrings(eventTypes []domain.OrderEventType) []string {
+	out := make([]string, len(eventTypes))
+	for i, t := range eventTypes {
+		out[i] = string(t)
+	}
+	return out
+}
+
+func scanOrderEvents(rows *sql.Rows) ([]*domain.OrderEvent, error) {
 	var events []*domain.OrderEvent
 	for rows.Next() {
 		var event domain.OrderEvent
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
 	"github.com/jafarshop/b2bapi/pkg/errors"
 )
 
@@ -27,14 +29,62 @@ func NewSupplierOrderRepository(db *sql.DB, logger *zap.Logger) *supplierOrderRe
 }
 
 func (r *supplierOrderRepository) Create(ctx context.Context, order *domain.SupplierOrder) error {
+	return r.insert(ctx, r.db, order)
+}
+
+// CreateWithinCredit locks the partner row, sums its credit exposure and calls check with it, then creates the
+// order unless check returned an error, in one transaction so concurrent carts can't both pass the check.
+func (r *supplierOrderRepository) CreateWithinCredit(ctx context.Context, order *domain.SupplierOrder, statuses []domain.OrderStatus, check func(*repository.CreditExposure) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.checkCredit(ctx, tx, order.PartnerID, statuses, uuid.Nil, check); err != nil {
+		return err
+	}
+	if err := r.insert(ctx, tx, order); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkCredit locks the partner row for the rest of tx and calls check with its credit exposure, not counting
+// excludeID (uuid.Nil counts every order).
+func (r *supplierOrderRepository) checkCredit(ctx context.Context, tx *sql.Tx, partnerID uuid.UUID, statuses []domain.OrderStatus, excludeID uuid.UUID, check func(*repository.CreditExposure) error) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM partners WHERE id = $1 FOR UPDATE`, partnerID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return &errors.ErrNotFound{Resource: "partner", ID: partnerID.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to lock partner for credit check", zap.Error(err))
+		return err
+	}
+
+	exposure, err := r.creditExposure(ctx, tx, partnerID, statuses, excludeID)
+	if err != nil {
+		return err
+	}
+	return check(exposure)
+}
+
+// dbConn is satisfied by *sql.DB and *sql.Tx.
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (r *supplierOrderRepository) insert(ctx context.Context, db dbConn, order *domain.SupplierOrder) error {
 	query := `
 		INSERT INTO supplier_orders (
 			id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, created_at, updated_at
+			customer_name, customer_phone, shipping_address, cart_total, currency,
+			payment_status, payment_method, rejection_reason, hold_reason, tracking_carrier, tracking_number,
+			tracking_url, delivery_date, delivery_window_from, delivery_window_to, status_changed_at, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 	`
 
 	now := time.Now()
@@ -47,13 +97,24 @@ func (r *supplierOrderRepository) Create(ctx context.Context, order *domain.Supp
 	if order.UpdatedAt.IsZero() {
 		order.UpdatedAt = now
 	}
+	order.StatusChangedAt = order.CreatedAt
+	if order.Currency == "" {
+		order.Currency = domain.DefaultCurrency
+	}
 
 	shippingAddressJSON, err := json.Marshal(order.ShippingAddress)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query,
+	var deliveryDate interface{}
+	var deliveryFrom, deliveryTo *string
+	if w := order.DeliveryWindow; w != nil {
+		deliveryDate = w.DateString()
+		deliveryFrom, deliveryTo = w.From, w.To
+	}
+
+	_, err = db.ExecContext(ctx, query,
 		order.ID,
 		order.PartnerID,
 		order.PartnerOrderID,
@@ -64,12 +125,18 @@ func (r *supplierOrderRepository) Create(ctx context.Context, order *domain.Supp
 		order.CustomerPhone,
 		shippingAddressJSON,
 		order.CartTotal,
+		order.Currency,
 		order.PaymentStatus,
 		order.PaymentMethod,
 		order.RejectionReason,
+		order.HoldReason,
 		order.TrackingCarrier,
 		order.TrackingNumber,
 		order.TrackingURL,
+		deliveryDate,
+		deliveryFrom,
+		deliveryTo,
+		order.StatusChangedAt,
 		order.CreatedAt,
 		order.UpdatedAt,
 	)
@@ -82,60 +149,18 @@ func (r *supplierOrderRepository) Create(ctx context.Context, order *domain.Supp
 	return nil
 }
 
-func (r *supplierOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error) {
-	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, last_delivery_status, last_delivery_status_label, last_delivery_waybill, last_delivery_image_url, last_delivery_at,
-			created_at, updated_at
-		FROM supplier_orders
-		WHERE id = $1
-	`
-
-	var order domain.SupplierOrder
-	var shippingAddressJSON []byte
-	var shopifyDraftOrderID sql.NullInt64
-	var shopifyOrderID sql.NullString
-	var customerPhone sql.NullString
-	var paymentStatus sql.NullString
-	var paymentMethod sql.NullString
-	var rejectionReason sql.NullString
-	var trackingCarrier sql.NullString
-	var trackingNumber sql.NullString
-	var trackingURL sql.NullString
-	var lastDeliveryStatus sql.NullInt64
-	var lastDeliveryStatusLabel sql.NullString
-	var lastDeliveryWaybill sql.NullString
-	var lastDeliveryImageURL sql.NullString
-	var lastDeliveryAt sql.NullTime
+const supplierOrderColumns = `
+	id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
+	customer_name, customer_phone, shipping_address, cart_total, currency,
+	payment_status, payment_method, rejection_reason, hold_reason, possible_duplicate_of, tracking_carrier, tracking_number,
+	tracking_url, last_delivery_status, last_delivery_status_label, last_delivery_waybill, last_delivery_image_url, last_delivery_at,
+	delivery_date, delivery_window_from, delivery_window_to, status_changed_at, created_at, updated_at
+`
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&order.ID,
-		&order.PartnerID,
-		&order.PartnerOrderID,
-		&order.Status,
-		&shopifyDraftOrderID,
-		&shopifyOrderID,
-		&order.CustomerName,
-		&customerPhone,
-		&shippingAddressJSON,
-		&order.CartTotal,
-		&paymentStatus,
-		&paymentMethod,
-		&rejectionReason,
-		&trackingCarrier,
-		&trackingNumber,
-		&trackingURL,
-		&lastDeliveryStatus,
-		&lastDeliveryStatusLabel,
-		&lastDeliveryWaybill,
-		&lastDeliveryImageURL,
-		&lastDeliveryAt,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
+func (r *supplierOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error) {
+	query := `SELECT ` + supplierOrderColumns + ` FROM supplier_orders WHERE id = $1`
 
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: id.String()}
 	}
@@ -143,370 +168,55 @@ func (r *supplierOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 		r.logger.Error("Failed to get supplier order by ID", zap.Error(err))
 		return nil, err
 	}
-
-	if shopifyDraftOrderID.Valid {
-		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
-	}
-	if shopifyOrderID.Valid {
-		order.ShopifyOrderID = &shopifyOrderID.String
-	}
-	if customerPhone.Valid {
-		order.CustomerPhone = customerPhone.String
-	}
-	if paymentStatus.Valid {
-		order.PaymentStatus = paymentStatus.String
-	}
-	if paymentMethod.Valid {
-		order.PaymentMethod = &paymentMethod.String
-	}
-	if rejectionReason.Valid {
-		order.RejectionReason = &rejectionReason.String
-	}
-	if trackingCarrier.Valid {
-		order.TrackingCarrier = &trackingCarrier.String
-	}
-	if trackingNumber.Valid {
-		order.TrackingNumber = &trackingNumber.String
-	}
-	if trackingURL.Valid {
-		order.TrackingURL = &trackingURL.String
-	}
-	if lastDeliveryStatus.Valid {
-		s := int(lastDeliveryStatus.Int64)
-		order.LastDeliveryStatus = &s
-	}
-	if lastDeliveryStatusLabel.Valid {
-		order.LastDeliveryStatusLabel = &lastDeliveryStatusLabel.String
-	}
-	if lastDeliveryWaybill.Valid {
-		order.LastDeliveryWaybill = &lastDeliveryWaybill.String
-	}
-	if lastDeliveryImageURL.Valid {
-		order.LastDeliveryImageURL = &lastDeliveryImageURL.String
-	}
-	if lastDeliveryAt.Valid {
-		order.LastDeliveryAt = &lastDeliveryAt.Time
-	}
-
-	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
-		return nil, err
-	}
-
-	return &order, nil
+	return order, nil
 }
 
 func (r *supplierOrderRepository) GetByPartnerIDAndPartnerOrderID(ctx context.Context, partnerID uuid.UUID, partnerOrderID string) (*domain.SupplierOrder, error) {
-	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, last_delivery_status, last_delivery_status_label, last_delivery_waybill, last_delivery_image_url, last_delivery_at,
-			created_at, updated_at
-		FROM supplier_orders
-		WHERE partner_id = $1 AND partner_order_id = $2
-	`
-
-	var order domain.SupplierOrder
-	var shippingAddressJSON []byte
-	var shopifyDraftOrderID sql.NullInt64
-	var shopifyOrderID sql.NullString
-	var customerPhone sql.NullString
-	var paymentStatus sql.NullString
-	var paymentMethod sql.NullString
-	var rejectionReason sql.NullString
-	var trackingCarrier sql.NullString
-	var trackingNumber sql.NullString
-	var trackingURL sql.NullString
-	var lastDeliveryStatus sql.NullInt64
-	var lastDeliveryStatusLabel sql.NullString
-	var lastDeliveryWaybill sql.NullString
-	var lastDeliveryImageURL sql.NullString
-	var lastDeliveryAt sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, partnerID, partnerOrderID).Scan(
-		&order.ID,
-		&order.PartnerID,
-		&order.PartnerOrderID,
-		&order.Status,
-		&shopifyDraftOrderID,
-		&shopifyOrderID,
-		&order.CustomerName,
-		&customerPhone,
-		&shippingAddressJSON,
-		&order.CartTotal,
-		&paymentStatus,
-		&paymentMethod,
-		&rejectionReason,
-		&trackingCarrier,
-		&trackingNumber,
-		&trackingURL,
-		&lastDeliveryStatus,
-		&lastDeliveryStatusLabel,
-		&lastDeliveryWaybill,
-		&lastDeliveryImageURL,
-		&lastDeliveryAt,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
+	query := `SELECT ` + supplierOrderColumns + ` FROM supplier_orders WHERE partner_id = $1 AND partner_order_id = $2`
 
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, partnerID, partnerOrderID))
 	if err == sql.ErrNoRows {
-		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: partnerOrderID}
-	}
-	if err != nil {
-		r.logger.Error("Failed to get supplier order by partner ID and order ID", zap.Error(err))
-		return nil, err
-	}
-
-	if shopifyDraftOrderID.Valid {
-		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
-	}
-	if shopifyOrderID.Valid {
-		order.ShopifyOrderID = &shopifyOrderID.String
-	}
-	if customerPhone.Valid {
-		order.CustomerPhone = customerPhone.String
-	}
-	if paymentStatus.Valid {
-		order.PaymentStatus = paymentStatus.String
-	}
-	if paymentMethod.Valid {
-		order.PaymentMethod = &paymentMethod.String
-	}
-	if rejectionReason.Valid {
-		order.RejectionReason = &rejectionReason.String
-	}
-	if trackingCarrier.Valid {
-		order.TrackingCarrier = &trackingCarrier.String
-	}
-	if trackingNumber.Valid {
-		order.TrackingNumber = &trackingNumber.String
-	}
-	if trackingURL.Valid {
-		order.TrackingURL = &trackingURL.String
-	}
-	if lastDeliveryStatus.Valid {
-		s := int(lastDeliveryStatus.Int64)
-		order.LastDeliveryStatus = &s
-	}
-	if lastDeliveryStatusLabel.Valid {
-		order.LastDeliveryStatusLabel = &lastDeliveryStatusLabel.String
-	}
-	if lastDeliveryWaybill.Valid {
-		order.LastDeliveryWaybill = &lastDeliveryWaybill.String
-	}
-	if lastDeliveryImageURL.Valid {
-		order.LastDeliveryImageURL = &lastDeliveryImageURL.String
-	}
-	if lastDeliveryAt.Valid {
-		order.LastDeliveryAt = &lastDeliveryAt.Time
-	}
-
-	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
-		return nil, err
-	}
-
-	return &order, nil
-}
-
-func (r *supplierOrderRepository) GetByPartnerOrderID(ctx context.Context, partnerOrderID string) (*domain.SupplierOrder, error) {
-	if partnerOrderID == "" {
-		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: "partner_order_id empty"}
-	}
-	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, created_at, updated_at
-		FROM supplier_orders
-		WHERE partner_order_id = $1
-		LIMIT 1
-	`
-	var order domain.SupplierOrder
-	var shippingAddressJSON []byte
-	var shopifyDraftOrderID sql.NullInt64
-	var shopifyOrderIDVal sql.NullString
-	var customerPhone sql.NullString
-	var paymentStatus sql.NullString
-	var paymentMethod sql.NullString
-	var rejectionReason sql.NullString
-	var trackingCarrier sql.NullString
-	var trackingNumber sql.NullString
-	var trackingURL sql.NullString
-	err := r.db.QueryRowContext(ctx, query, partnerOrderID).Scan(
-		&order.ID,
-		&order.PartnerID,
-		&order.PartnerOrderID,
-		&order.Status,
-		&shopifyDraftOrderID,
-		&shopifyOrderIDVal,
-		&order.CustomerName,
-		&customerPhone,
-		&shippingAddressJSON,
-		&order.CartTotal,
-		&paymentStatus,
-		&paymentMethod,
-		&rejectionReason,
-		&trackingCarrier,
-		&trackingNumber,
-		&trackingURL,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: partnerOrderID}
-	}
-	if err != nil {
-		r.logger.Error("Failed to get supplier order by partner order ID", zap.Error(err), zap.String("partner_order_id", partnerOrderID))
-		return nil, err
-	}
-	if shopifyDraftOrderID.Valid {
-		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
-	}
-	if shopifyOrderIDVal.Valid {
-		order.ShopifyOrderID = &shopifyOrderIDVal.String
-	}
-	if customerPhone.Valid {
-		order.CustomerPhone = customerPhone.String
-	}
-	if paymentStatus.Valid {
-		order.PaymentStatus = paymentStatus.String
-	}
-	if paymentMethod.Valid {
-		order.PaymentMethod = &paymentMethod.String
-	}
-	if rejectionReason.Valid {
-		order.RejectionReason = &rejectionReason.String
-	}
-	if trackingCarrier.Valid {
-		order.TrackingCarrier = &trackingCarrier.String
-	}
-	if trackingNumber.Valid {
-		order.TrackingNumber = &trackingNumber.String
-	}
-	if trackingURL.Valid {
-		order.TrackingURL = &trackingURL.String
-	}
-	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
-		return nil, err
-	}
-	return &order, nil
-}
-
-func (r *supplierOrderRepository) GetByShopifyOrderID(ctx context.Context, shopifyOrderID string) (*domain.SupplierOrder, error) {
-	if shopifyOrderID == "" {
-		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: "shopify_order_id empty"}
-	}
-	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, last_delivery_status, last_delivery_status_label, last_delivery_waybill, last_delivery_image_url, last_delivery_at,
-			created_at, updated_at
-		FROM supplier_orders
-		WHERE shopify_order_id = $1
-		LIMIT 1
-	`
-
-	var order domain.SupplierOrder
-	var shippingAddressJSON []byte
-	var shopifyDraftOrderID sql.NullInt64
-	var shopifyOrderIDVal sql.NullString
-	var customerPhone sql.NullString
-	var paymentStatus sql.NullString
-	var paymentMethod sql.NullString
-	var rejectionReason sql.NullString
-	var trackingCarrier sql.NullString
-	var trackingNumber sql.NullString
-	var trackingURL sql.NullString
-	var lastDeliveryStatus sql.NullInt64
-	var lastDeliveryStatusLabel sql.NullString
-	var lastDeliveryWaybill sql.NullString
-	var lastDeliveryImageURL sql.NullString
-	var lastDeliveryAt sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, shopifyOrderID).Scan(
-		&order.ID,
-		&order.PartnerID,
-		&order.PartnerOrderID,
-		&order.Status,
-		&shopifyDraftOrderID,
-		&shopifyOrderIDVal,
-		&order.CustomerName,
-		&customerPhone,
-		&shippingAddressJSON,
-		&order.CartTotal,
-		&paymentStatus,
-		&paymentMethod,
-		&rejectionReason,
-		&trackingCarrier,
-		&trackingNumber,
-		&trackingURL,
-		&lastDeliveryStatus,
-		&lastDeliveryStatusLabel,
-		&lastDeliveryWaybill,
-		&lastDeliveryImageURL,
-		&lastDeliveryAt,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: shopifyOrderID}
-	}
-	if err != nil {
-		r.logger.Error("Failed to get supplier order by Shopify order ID", zap.Error(err), zap.String("shopify_order_id", shopifyOrderID))
-		return nil, err
-	}
-
-	if shopifyDraftOrderID.Valid {
-		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
-	}
-	if shopifyOrderIDVal.Valid {
-		order.ShopifyOrderID = &shopifyOrderIDVal.String
-	}
-	if customerPhone.Valid {
-		order.CustomerPhone = customerPhone.String
-	}
-	if paymentStatus.Valid {
-		order.PaymentStatus = paymentStatus.String
-	}
-	if paymentMethod.Valid {
-		order.PaymentMethod = &paymentMethod.String
-	}
-	if rejectionReason.Valid {
-		order.RejectionReason = &rejectionReason.String
-	}
-	if trackingCarrier.Valid {
-		order.TrackingCarrier = &trackingCarrier.String
-	}
-	if trackingNumber.Valid {
-		order.TrackingNumber = &trackingNumber.String
-	}
-	if trackingURL.Valid {
-		order.TrackingURL = &trackingURL.String
-	}
-	if lastDeliveryStatus.Valid {
-		s := int(lastDeliveryStatus.Int64)
-		order.LastDeliveryStatus = &s
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: partnerOrderID}
 	}
-	if lastDeliveryStatusLabel.Valid {
-		order.LastDeliveryStatusLabel = &lastDeliveryStatusLabel.String
+	if err != nil {
+		r.logger.Error("Failed to get supplier order by partner ID and order ID", zap.Error(err))
+		return nil, err
 	}
-	if lastDeliveryWaybill.Valid {
-		order.LastDeliveryWaybill = &lastDeliveryWaybill.String
+	return order, nil
+}
+
+func (r *supplierOrderRepository) GetByPartnerOrderID(ctx context.Context, partnerOrderID string) (*domain.SupplierOrder, error) {
+	if partnerOrderID == "" {
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: "partner_order_id empty"}
 	}
-	if lastDeliveryImageURL.Valid {
-		order.LastDeliveryImageURL = &lastDeliveryImageURL.String
+	query := `SELECT ` + supplierOrderColumns + ` FROM supplier_orders WHERE partner_order_id = $1 LIMIT 1`
+
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, partnerOrderID))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: partnerOrderID}
 	}
-	if lastDeliveryAt.Valid {
-		order.LastDeliveryAt = &lastDeliveryAt.Time
+	if err != nil {
+		r.logger.Error("Failed to get supplier order by partner order ID", zap.Error(err), zap.String("partner_order_id", partnerOrderID))
+		return nil, err
 	}
+	return order, nil
+}
 
-	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
-		return nil, err
+func (r *supplierOrderRepository) GetByShopifyOrderID(ctx context.Context, shopifyOrderID string) (*domain.SupplierOrder, error) {
+	if shopifyOrderID == "" {
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: "shopify_order_id empty"}
 	}
+	query := `SELECT ` + supplierOrderColumns + ` FROM supplier_orders WHERE shopify_order_id = $1 LIMIT 1`
 
-	return &order, nil
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, shopifyOrderID))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: shopifyOrderID}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get supplier order by Shopify order ID", zap.Error(err), zap.String("shopify_order_id", shopifyOrderID))
+		return nil, err
+	}
+	return order, nil
 }
 
 func (r *supplierOrderRepository) GetByShopifyOrderIDPreferredPartner(ctx context.Context, shopifyOrderID string, excludePartnerID uuid.UUID) (*domain.SupplierOrder, error) {
@@ -514,60 +224,14 @@ func (r *supplierOrderRepository) GetByShopifyOrderIDPreferredPartner(ctx contex
 		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: "shopify_order_id empty"}
 	}
 	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, last_delivery_status, last_delivery_status_label, last_delivery_waybill, last_delivery_image_url, last_delivery_at,
-			created_at, updated_at
+		SELECT ` + supplierOrderColumns + `
 		FROM supplier_orders
 		WHERE shopify_order_id = $1
 		ORDER BY (partner_id = $2) ASC
 		LIMIT 1
 	`
 
-	var order domain.SupplierOrder
-	var shippingAddressJSON []byte
-	var shopifyDraftOrderID sql.NullInt64
-	var shopifyOrderIDVal sql.NullString
-	var customerPhone sql.NullString
-	var paymentStatus sql.NullString
-	var paymentMethod sql.NullString
-	var rejectionReason sql.NullString
-	var trackingCarrier sql.NullString
-	var trackingNumber sql.NullString
-	var trackingURL sql.NullString
-	var lastDeliveryStatus sql.NullInt64
-	var lastDeliveryStatusLabel sql.NullString
-	var lastDeliveryWaybill sql.NullString
-	var lastDeliveryImageURL sql.NullString
-	var lastDeliveryAt sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, shopifyOrderID, excludePartnerID).Scan(
-		&order.ID,
-		&order.PartnerID,
-		&order.PartnerOrderID,
-		&order.Status,
-		&shopifyDraftOrderID,
-		&shopifyOrderIDVal,
-		&order.CustomerName,
-		&customerPhone,
-		&shippingAddressJSON,
-		&order.CartTotal,
-		&paymentStatus,
-		&paymentMethod,
-		&rejectionReason,
-		&trackingCarrier,
-		&trackingNumber,
-		&trackingURL,
-		&lastDeliveryStatus,
-		&lastDeliveryStatusLabel,
-		&lastDeliveryWaybill,
-		&lastDeliveryImageURL,
-		&lastDeliveryAt,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
-
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, shopifyOrderID, excludePartnerID))
 	if err == sql.ErrNoRows {
 		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: shopifyOrderID}
 	}
@@ -575,65 +239,17 @@ func (r *supplierOrderRepository) GetByShopifyOrderIDPreferredPartner(ctx contex
 		r.logger.Error("Failed to get supplier order by Shopify order ID (preferred partner)", zap.Error(err), zap.String("shopify_order_id", shopifyOrderID))
 		return nil, err
 	}
-
-	if shopifyDraftOrderID.Valid {
-		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
-	}
-	if shopifyOrderIDVal.Valid {
-		order.ShopifyOrderID = &shopifyOrderIDVal.String
-	}
-	if customerPhone.Valid {
-		order.CustomerPhone = customerPhone.String
-	}
-	if paymentStatus.Valid {
-		order.PaymentStatus = paymentStatus.String
-	}
-	if paymentMethod.Valid {
-		order.PaymentMethod = &paymentMethod.String
-	}
-	if rejectionReason.Valid {
-		order.RejectionReason = &rejectionReason.String
-	}
-	if trackingCarrier.Valid {
-		order.TrackingCarrier = &trackingCarrier.String
-	}
-	if trackingNumber.Valid {
-		order.TrackingNumber = &trackingNumber.String
-	}
-	if trackingURL.Valid {
-		order.TrackingURL = &trackingURL.String
-	}
-	if lastDeliveryStatus.Valid {
-		s := int(lastDeliveryStatus.Int64)
-		order.LastDeliveryStatus = &s
-	}
-	if lastDeliveryStatusLabel.Valid {
-		order.LastDeliveryStatusLabel = &lastDeliveryStatusLabel.String
-	}
-	if lastDeliveryWaybill.Valid {
-		order.LastDeliveryWaybill = &lastDeliveryWaybill.String
-	}
-	if lastDeliveryImageURL.Valid {
-		order.LastDeliveryImageURL = &lastDeliveryImageURL.String
-	}
-	if lastDeliveryAt.Valid {
-		order.LastDeliveryAt = &lastDeliveryAt.Time
-	}
-
-	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
-		return nil, err
-	}
-
-	return &order, nil
+	return order, nil
 }
 
+// Update stores the order's editable fields. Status and rejection reason only change through TransitionStatus.
 func (r *supplierOrderRepository) Update(ctx context.Context, order *domain.SupplierOrder) error {
 	query := `
 		UPDATE supplier_orders
-		SET status = $2, shopify_draft_order_id = $3, customer_name = $4,
-			customer_phone = $5, shipping_address = $6, cart_total = $7,
-			payment_status = $8, payment_method = $9, rejection_reason = $10, tracking_carrier = $11,
-			tracking_number = $12, tracking_url = $13, updated_at = $14
+		SET shopify_draft_order_id = $2, customer_name = $3,
+			customer_phone = $4, shipping_address = $5, cart_total = $6,
+			payment_status = $7, payment_method = $8, tracking_carrier = $9,
+			tracking_number = $10, tracking_url = $11, updated_at = $12
 		WHERE id = $1
 	`
 
@@ -645,7 +261,6 @@ func (r *supplierOrderRepository) Update(ctx context.Context, order *domain.Supp
 
 	_, err = r.db.ExecContext(ctx, query,
 		order.ID,
-		order.Status,
 		order.ShopifyDraftOrderID,
 		order.CustomerName,
 		order.CustomerPhone,
@@ -653,7 +268,6 @@ func (r *supplierOrderRepository) Update(ctx context.Context, order *domain.Supp
 		order.CartTotal,
 		order.PaymentStatus,
 		order.PaymentMethod,
-		order.RejectionReason,
 		order.TrackingCarrier,
 		order.TrackingNumber,
 		order.TrackingURL,
@@ -668,45 +282,122 @@ func (r *supplierOrderRepository) Update(ctx context.Context, order *domain.Supp
 	return nil
 }
 
-func (r *supplierOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus, rejectionReason *string) error {
-	query := `
-		UPDATE supplier_orders
-		SET status = $2, rejection_reason = $3, updated_at = $4
-		WHERE id = $1
-	`
+func (r *supplierOrderRepository) Amend(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.amend(ctx, tx, order, items); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
 
-	_, err := r.db.ExecContext(ctx, query, id, status, rejectionReason, time.Now())
+// AmendWithinCredit locks the partner row, sums its credit exposure without this order and calls check with it,
+// then amends the order unless check returned an error, in one transaction (as CreateWithinCredit).
+func (r *supplierOrderRepository) AmendWithinCredit(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem, statuses []domain.OrderStatus, check func(*repository.CreditExposure) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		r.logger.Error("Failed to update supplier order status", zap.Error(err))
 		return err
 	}
+	defer tx.Rollback()
+
+	if err := r.checkCredit(ctx, tx, order.PartnerID, statuses, order.ID, check); err != nil {
+		return err
+	}
+	if err := r.amend(ctx, tx, order, items); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *supplierOrderRepository) amend(ctx context.Context, tx *sql.Tx, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) error {
+	shippingAddressJSON, err := json.Marshal(order.ShippingAddress)
+	if err != nil {
+		return err
+	}
+
+	order.UpdatedAt = time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE supplier_orders
+		SET shipping_address = $3, cart_total = $4, updated_at = $5
+		WHERE id = $1 AND status = $2
+	`, order.ID, domain.OrderStatusIncompleteCaution, shippingAddressJSON, order.CartTotal, order.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to amend supplier order", zap.Error(err))
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &errors.ErrConflict{Message: "order can no longer be amended"}
+	}
 
+	if items != nil {
+		return replaceOrderItems(ctx, tx, r.logger, order.ID, items)
+	}
 	return nil
 }
 
-func (r *supplierOrderRepository) UpdateStatusFromShopify(ctx context.Context, id uuid.UUID, status domain.OrderStatus) error {
+// TransitionStatus sets the status only if it is still from (compare-and-set), so concurrent writers can't
+// overwrite each other's change. Returns false when the status no longer matched. A nil rejectionReason keeps the current one.
+func (r *supplierOrderRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.OrderStatus, rejectionReason *string) (bool, error) {
 	query := `
 		UPDATE supplier_orders
-		SET status = $2, updated_at = $3
-		WHERE id = $1
+		SET status = $3, rejection_reason = COALESCE($4, rejection_reason), status_changed_at = $5, updated_at = $5
+		WHERE id = $1 AND status = $2
 	`
-	_, err := r.db.ExecContext(ctx, query, id, status, time.Now())
+
+	result, err := r.db.ExecContext(ctx, query, id, from, to, rejectionReason, time.Now())
 	if err != nil {
-		r.logger.Error("Failed to update supplier order status from Shopify", zap.Error(err))
-		return err
+		r.logger.Error("Failed to update supplier order status", zap.Error(err))
+		return false, err
 	}
-	return nil
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// CountUnknownStatuses returns how many orders hold each status that is not in known.
+func (r *supplierOrderRepository) CountUnknownStatuses(ctx context.Context, known []domain.OrderStatus) (map[domain.OrderStatus]int, error) {
+	knownNames := make([]string, len(known))
+	for i, s := range known {
+		knownNames[i] = string(s)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT status, COUNT(*) FROM supplier_orders
+		WHERE status <> ALL($1)
+		GROUP BY status
+	`, pq.Array(knownNames))
+	if err != nil {
+		r.logger.Error("Failed to count unknown order statuses", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.OrderStatus]int)
+	for rows.Next() {
+		var status domain.OrderStatus
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		counts[status] = n
+	}
+	return counts, rows.Err()
 }
 
 func (r *supplierOrderRepository) UpdateTracking(ctx context.Context, id uuid.UUID, carrier, trackingNumber, trackingURL *string) error {
 	query := `
 		UPDATE supplier_orders
-		SET tracking_carrier = $2, tracking_number = $3, tracking_url = $4,
-			status = $5, updated_at = $6
+		SET tracking_carrier = $2, tracking_number = $3, tracking_url = $4, updated_at = $5
 		WHERE id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query, id, carrier, trackingNumber, trackingURL, domain.OrderStatusFulfilled, time.Now())
+	_, err := r.db.ExecContext(ctx, query, id, carrier, trackingNumber, trackingURL, time.Now())
 	if err != nil {
 		r.logger.Error("Failed to update supplier order tracking", zap.Error(err))
 		return err
@@ -763,103 +454,193 @@ func (r *supplierOrderRepository) UpdateShopifyOrderID(ctx context.Context, id u
 	return nil
 }
 
-func (r *supplierOrderRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*domain.SupplierOrder, error) {
+func (r *supplierOrderRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string) error {
 	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, last_delivery_status, last_delivery_status_label, last_delivery_waybill, last_delivery_image_url, last_delivery_at,
-			created_at, updated_at
-		FROM supplier_orders
-		WHERE partner_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		UPDATE supplier_orders
+		SET payment_status = $2, updated_at = $3
+		WHERE id = $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, partnerID, limit, offset)
+	_, err := r.db.ExecContext(ctx, query, id, paymentStatus, time.Now())
 	if err != nil {
-		r.logger.Error("Failed to list supplier orders by partner ID", zap.Error(err))
-		return nil, err
+		r.logger.Error("Failed to update payment status", zap.Error(err))
+		return err
 	}
-	defer rows.Close()
 
-	var orders []*domain.SupplierOrder
-	for rows.Next() {
-		order, err := r.scanOrder(rows)
-		if err != nil {
-			return nil, err
-		}
-		orders = append(orders, order)
+	return nil
+}
+
+func (r *supplierOrderRepository) UpdateHoldReason(ctx context.Context, id uuid.UUID, holdReason *string) error {
+	query := `
+		UPDATE supplier_orders
+		SET hold_reason = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, holdReason, time.Now())
+	if err != nil {
+		r.logger.Error("Failed to update hold reason", zap.Error(err))
+		return err
 	}
 
-	return orders, rows.Err()
+	return nil
 }
 
-func (r *supplierOrderRepository) ListByPartnerIDAndStatus(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error) {
+// MarkPossibleDuplicate records the earlier order the order looks like a duplicate of, and holds it.
+func (r *supplierOrderRepository) MarkPossibleDuplicate(ctx context.Context, id, duplicateOf uuid.UUID, holdReason string) error {
 	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, last_delivery_status, last_delivery_status_label, last_delivery_waybill, last_delivery_image_url, last_delivery_at,
-			created_at, updated_at
-		FROM supplier_orders
-		WHERE partner_id = $1 AND status = $2
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4
+		UPDATE supplier_orders
+		SET possible_duplicate_of = $2, hold_reason = $3, updated_at = $4
+		WHERE id = $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, partnerID, status, limit, offset)
+	_, err := r.db.ExecContext(ctx, query, id, duplicateOf, holdReason, time.Now())
 	if err != nil {
-		r.logger.Error("Failed to list supplier orders by partner ID and status", zap.Error(err))
-		return nil, err
+		r.logger.Error("Failed to mark possible duplicate order", zap.Error(err))
+		return err
 	}
-	defer rows.Close()
 
-	var orders []*domain.SupplierOrder
-	for rows.Next() {
-		order, err := r.scanOrder(rows)
-		if err != nil {
-			return nil, err
-		}
-		orders = append(orders, order)
-	}
+	return nil
+}
 
-	return orders, rows.Err()
+// CreditExposure sums the cart totals of the partner's orders in statuses that are not paid yet, excluding
+// excludeID (uuid.Nil excludes none).
+func (r *supplierOrderRepository) CreditExposure(ctx context.Context, partnerID uuid.UUID, statuses []domain.OrderStatus, excludeID uuid.UUID) (*repository.CreditExposure, error) {
+	return r.creditExposure(ctx, r.db, partnerID, statuses, excludeID)
 }
 
-func (r *supplierOrderRepository) ListByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error) {
+func (r *supplierOrderRepository) creditExposure(ctx context.Context, db dbConn, partnerID uuid.UUID, statuses []domain.OrderStatus, excludeID uuid.UUID) (*repository.CreditExposure, error) {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
 	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, last_delivery_status, last_delivery_status_label, last_delivery_waybill, last_delivery_image_url, last_delivery_at,
-			created_at, updated_at
+		SELECT COUNT(*), COALESCE(SUM(cart_total), 0)
 		FROM supplier_orders
-		WHERE status = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE partner_id = $1 AND status = ANY($2) AND COALESCE(payment_status, '') <> $3 AND id <> $4
+	`
+
+	var exposure repository.CreditExposure
+	err := db.QueryRowContext(ctx, query, partnerID, pq.Array(names), domain.PaymentStatusPaid, excludeID).Scan(&exposure.Orders, &exposure.Amount)
+	if err != nil {
+		r.logger.Error("Failed to sum partner credit exposure", zap.Error(err))
+		return nil, err
+	}
+	return &exposure, nil
+}
+
+// CustomerHistory counts the orders placed for customerPhone before the given time (excluding excludeID) that were
+// delivered and that were returned to shipper. Orders are counted by their current outcome.
+func (r *supplierOrderRepository) CustomerHistory(ctx context.Context, customerPhone string, before time.Time, excludeID uuid.UUID) (*repository.CustomerHistory, error) {
+	// 170 is Wassel's "Delivered to customer"
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE o.status = $4 OR o.last_delivery_status = 170),
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM order_returns r WHERE r.supplier_order_id = o.id AND r.reason = $5
+			))
+		FROM supplier_orders o
+		WHERE o.customer_phone = $1 AND o.created_at < $2 AND o.id <> $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	var history repository.CustomerHistory
+	err := r.db.QueryRowContext(ctx, query, customerPhone, before, excludeID,
+		domain.OrderStatusComplete, domain.ReturnReasonReturnToShipper).Scan(&history.Delivered, &history.Returned)
+	if err != nil {
+		r.logger.Error("Failed to count customer order history", zap.Error(err))
+		return nil, err
+	}
+	return &history, nil
+}
+
+// ListOrders returns one page of orders matching filter, newest first, plus the total match count.
+func (r *supplierOrderRepository) ListOrders(ctx context.Context, filter repository.OrderListFilter) (*repository.OrderPage, error) {
+	var qb queryBuilder
+	if filter.PartnerID != nil {
+		qb.where("partner_id = " + qb.arg(*filter.PartnerID))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, s := range filter.Statuses {
+			statuses[i] = string(s)
+		}
+		qb.where("status = ANY(" + qb.arg(pq.Array(statuses)) + ")")
+	}
+	if filter.CreatedFrom != nil {
+		qb.where("created_at >= " + qb.arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		qb.where("created_at < " + qb.arg(*filter.CreatedTo))
+	}
+	if filter.UpdatedFrom != nil {
+		qb.where("updated_at >= " + qb.arg(*filter.UpdatedFrom))
+	}
+	if filter.UpdatedTo != nil {
+		qb.where("updated_at < " + qb.arg(*filter.UpdatedTo))
+	}
+	if filter.CustomerPhone != "" {
+		qb.where("customer_phone = " + qb.arg(filter.CustomerPhone))
+	}
+	if filter.ShopifyOrderID != "" {
+		qb.where("shopify_order_id = " + qb.arg(filter.ShopifyOrderID))
+	}
+	if filter.DeliveryStatus != nil {
+		qb.where("last_delivery_status = " + qb.arg(*filter.DeliveryStatus))
+	}
+	if filter.StatusChangedBefore != nil {
+		qb.where("status_changed_at < " + qb.arg(*filter.StatusChangedBefore))
+	}
+	if filter.LastDeliveryBefore != nil {
+		qb.where("last_delivery_at < " + qb.arg(*filter.LastDeliveryBefore))
+	}
+	if filter.Search != "" {
+		qb.where("customer_name ILIKE " + qb.arg(containsPattern(filter.Search)))
+	}
+
+	// Total ignores the cursor so every page reports the same count
+	var total int
+	if !filter.SkipTotal {
+		countQuery := `SELECT COUNT(*) FROM supplier_orders` + qb.whereClause()
+		if err := r.db.QueryRowContext(ctx, countQuery, qb.args...).Scan(&total); err != nil {
+			r.logger.Error("Failed to count supplier orders", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if filter.After != nil {
+		qb.where("(created_at, id) < (" + qb.arg(filter.After.CreatedAt) + ", " + qb.arg(filter.After.ID) + ")")
+	}
+	// Fetch one extra row to know whether another page follows
+	query := `SELECT ` + supplierOrderColumns + ` FROM supplier_orders` + qb.whereClause() +
+		` ORDER BY created_at DESC, id DESC LIMIT ` + qb.arg(filter.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, qb.args...)
 	if err != nil {
-		r.logger.Error("Failed to list supplier orders by status", zap.Error(err))
+		r.logger.Error("Failed to list supplier orders", zap.Error(err))
 		return nil, err
 	}
 	defer rows.Close()
 
-	var orders []*domain.SupplierOrder
+	page := &repository.OrderPage{Total: total}
 	for rows.Next() {
 		order, err := r.scanOrder(rows)
 		if err != nil {
 			return nil, err
 		}
-		orders = append(orders, order)
+		page.Orders = append(page.Orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return orders, rows.Err()
+	if len(page.Orders) > filter.Limit {
+		page.Orders = page.Orders[:filter.Limit]
+		page.Next = repository.CursorAfter(page.Orders[len(page.Orders)-1])
+	}
+	return page, nil
 }
 
-func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrder, error) {
+func (r *supplierOrderRepository) scanOrder(row rowScanner) (*domain.SupplierOrder, error) {
 	var order domain.SupplierOrder
 	var shippingAddressJSON []byte
 	var shopifyDraftOrderID sql.NullInt64
@@ -868,6 +649,8 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 	var paymentStatus sql.NullString
 	var paymentMethod sql.NullString
 	var rejectionReason sql.NullString
+	var holdReason sql.NullString
+	var possibleDuplicateOf uuid.NullUUID
 	var trackingCarrier sql.NullString
 	var trackingNumber sql.NullString
 	var trackingURL sql.NullString
@@ -876,8 +659,10 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 	var lastDeliveryWaybill sql.NullString
 	var lastDeliveryImageURL sql.NullString
 	var lastDeliveryAt sql.NullTime
+	var deliveryDate sql.NullTime
+	var deliveryFrom, deliveryTo sql.NullString
 
-	err := rows.Scan(
+	err := row.Scan(
 		&order.ID,
 		&order.PartnerID,
 		&order.PartnerOrderID,
@@ -888,9 +673,12 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 		&customerPhone,
 		&shippingAddressJSON,
 		&order.CartTotal,
+		&order.Currency,
 		&paymentStatus,
 		&paymentMethod,
 		&rejectionReason,
+		&holdReason,
+		&possibleDuplicateOf,
 		&trackingCarrier,
 		&trackingNumber,
 		&trackingURL,
@@ -899,6 +687,10 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 		&lastDeliveryWaybill,
 		&lastDeliveryImageURL,
 		&lastDeliveryAt,
+		&deliveryDate,
+		&deliveryFrom,
+		&deliveryTo,
+		&order.StatusChangedAt,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 	)
@@ -906,6 +698,7 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 	if err != nil {
 		return nil, err
 	}
+	order.CartTotal = order.CartTotal.In(order.Currency)
 
 	if shopifyDraftOrderID.Valid {
 		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
@@ -925,6 +718,12 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 	if rejectionReason.Valid {
 		order.RejectionReason = &rejectionReason.String
 	}
+	if holdReason.Valid {
+		order.HoldReason = &holdReason.String
+	}
+	if possibleDuplicateOf.Valid {
+		order.PossibleDuplicateOf = &possibleDuplicateOf.UUID
+	}
 	if trackingCarrier.Valid {
 		order.TrackingCarrier = &trackingCarrier.String
 	}
@@ -950,6 +749,15 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 	if lastDeliveryAt.Valid {
 		order.LastDeliveryAt = &lastDeliveryAt.Time
 	}
+	if deliveryDate.Valid {
+		order.DeliveryWindow = &domain.DeliveryWindow{Date: deliveryDate.Time}
+		if deliveryFrom.Valid {
+			order.DeliveryWindow.From = &deliveryFrom.String
+		}
+		if deliveryTo.Valid {
+			order.DeliveryWindow.To = &deliveryTo.String
+		}
+	}
 
 	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
 		return nil, err
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type partnerOrderRuleRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewPartnerOrderRuleRepository creates a new partner auto-confirmation rule repository
+func NewPartnerOrderRuleRepository(db *sql.DB, logger *zap.Logger) *partnerOrderRuleRepository {
+	return &partnerOrderRuleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const partnerOrderRuleColumns = `
+	id, partner_id, name, action, priority, min_cart_total, max_cart_total, city_codes,
+	min_item_count, max_item_count, min_delivered, max_returned, payment_methods, created_at, updated_at
+`
+
+func (r *partnerOrderRuleRepository) Create(ctx context.Context, rule *domain.PartnerOrderRule) error {
+	query := `
+		INSERT INTO partner_order_rules (` + partnerOrderRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	now := time.Now()
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		rule.ID,
+		rule.PartnerID,
+		rule.Name,
+		rule.Action,
+		rule.Priority,
+		rule.MinCartTotal,
+		rule.MaxCartTotal,
+		nullArray(rule.CityCodes),
+		rule.MinItemCount,
+		rule.MaxItemCount,
+		rule.MinDelivered,
+		rule.MaxReturned,
+		nullArray(rule.PaymentMethods),
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create partner order rule", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListByPartnerID returns the partner's rules in the order they are checked: priority, then oldest first.
+func (r *partnerOrderRuleRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.PartnerOrderRule, error) {
+	query := `
+		SELECT ` + partnerOrderRuleColumns + `
+		FROM partner_order_rules
+		WHERE partner_id = $1
+		ORDER BY priority, created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID)
+	if err != nil {
+		r.logger.Error("Failed to list partner order rules", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.PartnerOrderRule
+	for rows.Next() {
+		rule, err := scanPartnerOrderRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *partnerOrderRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM partner_order_rules WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete partner order rule", zap.Error(err))
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &errors.ErrNotFound{Resource: "partner_order_rule", ID: id.String()}
+	}
+	return nil
+}
+
+// nullArray stores an empty list as NULL (no condition).
+func nullArray(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return pq.Array(values)
+}
+
+func scanPartnerOrderRule(row rowScanner) (*domain.PartnerOrderRule, error) {
+	var rule domain.PartnerOrderRule
+	var minItemCount, maxItemCount, minDelivered, maxReturned sql.NullInt64
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.PartnerID,
+		&rule.Name,
+		&rule.Action,
+		&rule.Priority,
+		&rule.MinCartTotal,
+		&rule.MaxCartTotal,
+		pq.Array(&rule.CityCodes),
+		&minItemCount,
+		&maxItemCount,
+		&minDelivered,
+		&maxReturned,
+		pq.Array(&rule.PaymentMethods),
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.MinItemCount = intPtr(minItemCount)
+	rule.MaxItemCount = intPtr(maxItemCount)
+	rule.MinDelivered = intPtr(minDelivered)
+	rule.MaxReturned = intPtr(maxReturned)
+	return &rule, nil
+}
+
+// intPtr returns n as *int, nil when NULL.
+func intPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
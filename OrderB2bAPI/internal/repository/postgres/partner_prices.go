@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type partnerPriceRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewPartnerPriceRepository creates a new partner price list repository
+func NewPartnerPriceRepository(db *sql.DB, logger *zap.Logger) *partnerPriceRepository {
+	return &partnerPriceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const partnerPriceColumns = `
+	id, partner_id, sku, collection_handle, fixed_price, discount_percent,
+	valid_from, valid_to, created_at, updated_at
+`
+
+func (r *partnerPriceRepository) Create(ctx context.Context, price *domain.PartnerPrice) error {
+	query := `
+		INSERT INTO partner_prices (` + partnerPriceColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	now := time.Now()
+	if price.ID == uuid.Nil {
+		price.ID = uuid.New()
+	}
+	price.CreatedAt = now
+	price.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		price.ID,
+		price.PartnerID,
+		price.SKU,
+		price.CollectionHandle,
+		price.FixedPrice,
+		price.DiscountPercent,
+		price.ValidFrom,
+		price.ValidTo,
+		price.CreatedAt,
+		price.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create partner price", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *partnerPriceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PartnerPrice, error) {
+	query := `SELECT ` + partnerPriceColumns + ` FROM partner_prices WHERE id = $1`
+
+	price, err := scanPartnerPrice(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "partner_price", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get partner price", zap.Error(err))
+		return nil, err
+	}
+	return price, nil
+}
+
+// ListByPartnerID returns all of the partner's price rules (any dates), SKU rules first, newest first within
+// a target.
+func (r *partnerPriceRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.PartnerPrice, error) {
+	query := `
+		SELECT ` + partnerPriceColumns + `
+		FROM partner_prices
+		WHERE partner_id = $1
+		ORDER BY sku IS NULL, sku, collection_handle, valid_from DESC NULLS LAST, created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID)
+	if err != nil {
+		r.logger.Error("Failed to list partner prices", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []*domain.PartnerPrice
+	for rows.Next() {
+		price, err := scanPartnerPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	return prices, rows.Err()
+}
+
+func (r *partnerPriceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM partner_prices WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete partner price", zap.Error(err))
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &errors.ErrNotFound{Resource: "partner_price", ID: id.String()}
+	}
+	return nil
+}
+
+func scanPartnerPrice(row rowScanner) (*domain.PartnerPrice, error) {
+	var price domain.PartnerPrice
+	var sku, collectionHandle sql.NullString
+	var discountPercent sql.NullFloat64
+	var validFrom, validTo sql.NullTime
+
+	err := row.Scan(
+		&price.ID,
+		&price.PartnerID,
+		&sku,
+		&collectionHandle,
+		&price.FixedPrice,
+		&discountPercent,
+		&validFrom,
+		&validTo,
+		&price.CreatedAt,
+		&price.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if sku.Valid {
+		price.SKU = &sku.String
+	}
+	if collectionHandle.Valid {
+		price.CollectionHandle = &collectionHandle.String
+	}
+	if discountPercent.Valid {
+		price.DiscountPercent = &discountPercent.Float64
+	}
+	if validFrom.Valid {
+		price.ValidFrom = &validFrom.Time
+	}
+	if validTo.Valid {
+		price.ValidTo = &validTo.Time
+	}
+	return &price, nil
+}
@@ -29,10 +29,10 @@ func (r *partnerSKUMappingRepository) GetBySKUAndPartner(ctx context.Context, pa
 		WHERE partner_id = $1 AND sku = $2 AND is_active = true
 	`
 	var m domain.PartnerSKUMapping
-	var title, price, imageURL sql.NullString
+	var title, imageURL sql.NullString
 	err := r.db.QueryRowContext(ctx, query, partnerID, sku).Scan(
 		&m.ID, &m.PartnerID, &m.SKU, &m.ShopifyProductID, &m.ShopifyVariantID,
-		&title, &price, &imageURL, &m.IsActive, &m.CreatedAt, &m.UpdatedAt,
+		&title, &m.Price, &imageURL, &m.IsActive, &m.CreatedAt, &m.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, &errors.ErrNotFound{Resource: "partner_sku_mapping", ID: sku}
@@ -44,9 +44,6 @@ func (r *partnerSKUMappingRepository) GetBySKUAndPartner(ctx context.Context, pa
 	if title.Valid {
 		m.Title = &title.String
 	}
-	if price.Valid {
-		m.Price = &price.String
-	}
 	if imageURL.Valid {
 		m.ImageURL = &imageURL.String
 	}
@@ -70,10 +67,10 @@ func (r *partnerSKUMappingRepository) ListByPartnerID(ctx context.Context, partn
 	var out []*domain.PartnerSKUMapping
 	for rows.Next() {
 		var m domain.PartnerSKUMapping
-		var title, price, imageURL sql.NullString
+		var title, imageURL sql.NullString
 		err := rows.Scan(
 			&m.ID, &m.PartnerID, &m.SKU, &m.ShopifyProductID, &m.ShopifyVariantID,
-			&title, &price, &imageURL, &m.IsActive, &m.CreatedAt, &m.UpdatedAt,
+			&title, &m.Price, &imageURL, &m.IsActive, &m.CreatedAt, &m.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -81,9 +78,6 @@ func (r *partnerSKUMappingRepository) ListByPartnerID(ctx context.Context, partn
 		if title.Valid {
 			m.Title = &title.String
 		}
-		if price.Valid {
-			m.Price = &price.String
-		}
 		if imageURL.Valid {
 			m.ImageURL = &imageURL.String
 		}
@@ -37,7 +37,7 @@ func (r *partnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string)
 	// Prefer direct lookup by api_key_lookup (SHA256 hex) when set; then verify with bcrypt.
 	lookupKey := apiKeyLookupHash(apiKey)
 	queryByLookup := `
-		SELECT id, name, api_key_hash, webhook_url, collection_handle, is_active, created_at, updated_at
+		SELECT id, name, api_key_hash, webhook_url, collection_handle, price_policy, stock_policy, credit_limit, credit_policy, currency, address_policy, is_active, created_at, updated_at
 		FROM partners
 		WHERE is_active = true AND api_key_lookup = $1
 	`
@@ -49,6 +49,12 @@ func (r *partnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string)
 		&partner.APIKeyHash,
 		&webhookURL,
 		&collectionHandle,
+		&partner.PricePolicy,
+		&partner.StockPolicy,
+		&partner.CreditLimit,
+		&partner.CreditPolicy,
+		&partner.Currency,
+		&partner.AddressPolicy,
 		&partner.IsActive,
 		&partner.CreatedAt,
 		&partner.UpdatedAt,
@@ -71,7 +77,7 @@ func (r *partnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string)
 	}
 	// No row or column not yet present: fall back to iterating all active partners (legacy)
 	query := `
-		SELECT id, name, api_key_hash, webhook_url, collection_handle, is_active, created_at, updated_at
+		SELECT id, name, api_key_hash, webhook_url, collection_handle, price_policy, stock_policy, credit_limit, credit_policy, currency, address_policy, is_active, created_at, updated_at
 		FROM partners
 		WHERE is_active = true
 	`
@@ -87,7 +93,7 @@ func (r *partnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string)
 		count++
 		var p domain.Partner
 		var wh, ch sql.NullString
-		if err := rows.Scan(&p.ID, &p.Name, &p.APIKeyHash, &wh, &ch, &p.IsActive, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.APIKeyHash, &wh, &ch, &p.PricePolicy, &p.StockPolicy, &p.CreditLimit, &p.CreditPolicy, &p.Currency, &p.AddressPolicy, &p.IsActive, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			continue
 		}
 		if bcrypt.CompareHashAndPassword([]byte(p.APIKeyHash), []byte(apiKey)) == nil {
@@ -117,7 +123,7 @@ func safePrefix(s string, n int) string {
 
 func (r *partnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Partner, error) {
 	query := `
-		SELECT id, name, api_key_hash, webhook_url, collection_handle, is_active, created_at, updated_at
+		SELECT id, name, api_key_hash, webhook_url, collection_handle, price_policy, stock_policy, credit_limit, credit_policy, currency, address_policy, is_active, created_at, updated_at
 		FROM partners
 		WHERE id = $1
 	`
@@ -131,6 +137,12 @@ func (r *partnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 		&partner.APIKeyHash,
 		&webhookURL,
 		&collectionHandle,
+		&partner.PricePolicy,
+		&partner.StockPolicy,
+		&partner.CreditLimit,
+		&partner.CreditPolicy,
+		&partner.Currency,
+		&partner.AddressPolicy,
 		&partner.IsActive,
 		&partner.CreatedAt,
 		&partner.UpdatedAt,
@@ -150,7 +162,6 @@ func (r *partnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 	if collectionHandle.Valid && collectionHandle.String != "" {
 		partner.CollectionHandle = &collectionHandle.String
 	}
-
 	return &partner, nil
 }
 
@@ -215,8 +226,8 @@ func (r *partnerRepository) ListWithCollectionHandle(ctx context.Context) ([]*do
 
 func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner) error {
 	query := `
-		INSERT INTO partners (id, name, api_key_hash, api_key_lookup, webhook_url, collection_handle, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO partners (id, name, api_key_hash, api_key_lookup, webhook_url, collection_handle, price_policy, stock_policy, credit_limit, credit_policy, currency, address_policy, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
 	now := time.Now()
@@ -229,6 +240,24 @@ func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner)
 	if partner.UpdatedAt.IsZero() {
 		partner.UpdatedAt = now
 	}
+	if partner.PricePolicy == "" {
+		partner.PricePolicy = domain.PricePolicyFlag
+	}
+	if partner.StockPolicy == "" {
+		partner.StockPolicy = domain.StockPolicyReport
+	}
+	if partner.CreditPolicy == "" {
+		partner.CreditPolicy = domain.CreditPolicyReject
+	}
+	if partner.Currency == "" {
+		partner.Currency = domain.DefaultCurrency
+	}
+	if err := checkPartnerCurrency(partner); err != nil {
+		return err
+	}
+	if partner.AddressPolicy == "" {
+		partner.AddressPolicy = domain.AddressPolicyLenient
+	}
 
 	var apiKeyLookup interface{}
 	if partner.APIKeyLookup != "" {
@@ -241,6 +270,12 @@ func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner)
 		apiKeyLookup,
 		partner.WebhookURL,
 		partner.CollectionHandle,
+		partner.PricePolicy,
+		partner.StockPolicy,
+		partner.CreditLimit,
+		partner.CreditPolicy,
+		partner.Currency,
+		partner.AddressPolicy,
 		partner.IsActive,
 		partner.CreatedAt,
 		partner.UpdatedAt,
@@ -257,10 +292,14 @@ func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner)
 func (r *partnerRepository) Update(ctx context.Context, partner *domain.Partner) error {
 	query := `
 		UPDATE partners
-		SET name = $2, api_key_hash = $3, webhook_url = $4, collection_handle = $5, is_active = $6, updated_at = $7
+		SET name = $2, api_key_hash = $3, webhook_url = $4, collection_handle = $5, price_policy = $6, stock_policy = $7,
+			credit_limit = $8, credit_policy = $9, currency = $10, address_policy = $11, is_active = $12, updated_at = $13
 		WHERE id = $1
 	`
 
+	if err := checkPartnerCurrency(partner); err != nil {
+		return err
+	}
 	partner.UpdatedAt = time.Now()
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -269,6 +308,12 @@ func (r *partnerRepository) Update(ctx context.Context, partner *domain.Partner)
 		partner.APIKeyHash,
 		partner.WebhookURL,
 		partner.CollectionHandle,
+		partner.PricePolicy,
+		partner.StockPolicy,
+		partner.CreditLimit,
+		partner.CreditPolicy,
+		partner.Currency,
+		partner.AddressPolicy,
 		partner.IsActive,
 		partner.UpdatedAt,
 	)
@@ -280,3 +325,15 @@ func (r *partnerRepository) Update(ctx context.Context, partner *domain.Partner)
 
 	return nil
 }
+
+// checkPartnerCurrency refuses any currency but the shop's: prices, Shopify orders and credit are not converted
+// between currencies.
+func checkPartnerCurrency(partner *domain.Partner) error {
+	if partner.Currency != domain.DefaultCurrency {
+		return &errors.ErrValidation{
+			Message: "unsupported currency",
+			Fields:  map[string]string{"currency": "must be " + domain.DefaultCurrency},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryBuilder accumulates WHERE conditions and their positional arguments.
+type queryBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+// arg registers a value and returns its placeholder ($n).
+func (b *queryBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// where adds a condition built with placeholders from arg.
+func (b *queryBuilder) where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+// whereClause returns " WHERE a AND b ..." or "" when there are no conditions.
+func (b *queryBuilder) whereClause() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conds, " AND ")
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns an ILIKE pattern matching s anywhere, with LIKE wildcards in s escaped.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
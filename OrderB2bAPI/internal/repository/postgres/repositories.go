@@ -11,12 +11,21 @@ import (
 // NewRepositories creates a new set of repositories
 func NewRepositories(db *sql.DB, logger *zap.Logger) *repository.Repositories {
 	return &repository.Repositories{
-		Partner:           NewPartnerRepository(db, logger),
-		SupplierOrder:     NewSupplierOrderRepository(db, logger),
-		SupplierOrderItem: NewSupplierOrderItemRepository(db, logger),
-		IdempotencyKey:    NewIdempotencyKeyRepository(db, logger),
-		SKUMapping:        NewSKUMappingRepository(db, logger),
-		PartnerSKUMapping: NewPartnerSKUMappingRepository(db, logger),
-		OrderEvent:        NewOrderEventRepository(db, logger),
+		Partner:             NewPartnerRepository(db, logger),
+		SupplierStaff:       NewSupplierStaffRepository(db, logger),
+		SupplierOrder:       NewSupplierOrderRepository(db, logger),
+		SupplierOrderItem:   NewSupplierOrderItemRepository(db, logger),
+		IdempotencyKey:      NewIdempotencyKeyRepository(db, logger),
+		SKUMapping:          NewSKUMappingRepository(db, logger),
+		PartnerSKUMapping:   NewPartnerSKUMappingRepository(db, logger),
+		PartnerPrice:        NewPartnerPriceRepository(db, logger),
+		PartnerOrderRule:    NewPartnerOrderRuleRepository(db, logger),
+		PartnerSLAThreshold: NewPartnerSLAThresholdRepository(db, logger),
+		SLABreach:           NewSLABreachRepository(db, logger),
+		OrderEvent:          NewOrderEventRepository(db, logger),
+		OrderReturn:         NewOrderReturnRepository(db, logger),
+		OrderNote:           NewOrderNoteRepository(db, logger),
+		CODLedger:           NewCODLedgerRepository(db, logger),
+		Invoice:             NewInvoiceRepository(db, logger),
 	}
 }
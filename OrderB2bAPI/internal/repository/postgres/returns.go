@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type orderReturnRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewOrderReturnRepository creates a new order return repository
+func NewOrderReturnRepository(db *sql.DB, logger *zap.Logger) *orderReturnRepository {
+	return &orderReturnRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const orderReturnColumns = `
+	id, supplier_order_id, status, source, reason, note, wassel_status, previous_order_status,
+	restock, decision_note, decided_by_staff_id, decided_at, created_at, updated_at
+`
+
+// Create inserts the return and its items in a single transaction.
+// Returns ErrConflict when the order already has an open (REQUESTED) return.
+func (r *orderReturnRepository) Create(ctx context.Context, ret *domain.OrderReturn) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if ret.ID == uuid.Nil {
+		ret.ID = uuid.New()
+	}
+	if ret.Status == "" {
+		ret.Status = domain.ReturnStatusRequested
+	}
+	ret.CreatedAt = now
+	ret.UpdatedAt = now
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO order_returns (
+			id, supplier_order_id, status, source, reason, note, wassel_status,
+			previous_order_status, restock, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT DO NOTHING
+	`,
+		ret.ID,
+		ret.SupplierOrderID,
+		ret.Status,
+		ret.Source,
+		ret.Reason,
+		ret.Note,
+		ret.WasselStatus,
+		ret.PreviousOrderStatus,
+		ret.Restock,
+		ret.CreatedAt,
+		ret.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create order return", zap.Error(err))
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &errors.ErrConflict{Message: "order already has an open return"}
+	}
+
+	for _, item := range ret.Items {
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+		item.OrderReturnID = ret.ID
+		item.CreatedAt = now
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO order_return_items (id, order_return_id, supplier_order_item_id, quantity, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, item.ID, item.OrderReturnID, item.SupplierOrderItemID, item.Quantity, item.CreatedAt)
+		if err != nil {
+			r.logger.Error("Failed to create order return item", zap.Error(err))
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *orderReturnRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.OrderReturn, error) {
+	query := `SELECT ` + orderReturnColumns + ` FROM order_returns WHERE id = $1`
+
+	ret, err := scanOrderReturn(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "order_return", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get order return", zap.Error(err))
+		return nil, err
+	}
+	if err := r.loadItems(ctx, []*domain.OrderReturn{ret}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// GetOpenByOrderID returns the order's REQUESTED return, if any.
+func (r *orderReturnRepository) GetOpenByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.OrderReturn, error) {
+	query := `SELECT ` + orderReturnColumns + ` FROM order_returns WHERE supplier_order_id = $1 AND status = $2`
+
+	ret, err := scanOrderReturn(r.db.QueryRowContext(ctx, query, orderID, domain.ReturnStatusRequested))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "order_return", ID: orderID.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get open order return", zap.Error(err))
+		return nil, err
+	}
+	if err := r.loadItems(ctx, []*domain.OrderReturn{ret}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *orderReturnRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderReturn, error) {
+	query := `SELECT ` + orderReturnColumns + ` FROM order_returns WHERE supplier_order_id = $1 ORDER BY created_at ASC`
+	return r.list(ctx, query, orderID)
+}
+
+func (r *orderReturnRepository) ListByStatus(ctx context.Context, status domain.ReturnStatus, limit, offset int) ([]*domain.OrderReturn, error) {
+	query := `SELECT ` + orderReturnColumns + ` FROM order_returns WHERE status = $1 ORDER BY created_at ASC LIMIT $2 OFFSET $3`
+	return r.list(ctx, query, status, limit, offset)
+}
+
+// UpdateDecision stores the staff decision (status, restock, decision note, staff, decided_at).
+func (r *orderReturnRepository) UpdateDecision(ctx context.Context, ret *domain.OrderReturn) error {
+	query := `
+		UPDATE order_returns
+		SET status = $2, restock = $3, decision_note = $4, decided_by_staff_id = $5, decided_at = $6, updated_at = $6
+		WHERE id = $1
+	`
+
+	now := time.Now()
+	ret.DecidedAt = &now
+	ret.UpdatedAt = now
+	_, err := r.db.ExecContext(ctx, query, ret.ID, ret.Status, ret.Restock, ret.DecisionNote, ret.DecidedByStaffID, now)
+	if err != nil {
+		r.logger.Error("Failed to update order return decision", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *orderReturnRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.OrderReturn, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list order returns", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var returns []*domain.OrderReturn
+	for rows.Next() {
+		ret, err := scanOrderReturn(rows)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, ret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadItems(ctx, returns); err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+// loadItems fills Items on each return.
+func (r *orderReturnRepository) loadItems(ctx context.Context, returns []*domain.OrderReturn) error {
+	query := `
+		SELECT id, order_return_id, supplier_order_item_id, quantity, created_at
+		FROM order_return_items
+		WHERE order_return_id = $1
+		ORDER BY created_at ASC
+	`
+	for _, ret := range returns {
+		rows, err := r.db.QueryContext(ctx, query, ret.ID)
+		if err != nil {
+			r.logger.Error("Failed to get order return items", zap.Error(err))
+			return err
+		}
+		for rows.Next() {
+			var item domain.OrderReturnItem
+			if err := rows.Scan(&item.ID, &item.OrderReturnID, &item.SupplierOrderItemID, &item.Quantity, &item.CreatedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			ret.Items = append(ret.Items, &item)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOrderReturn(row rowScanner) (*domain.OrderReturn, error) {
+	var ret domain.OrderReturn
+	var note, decisionNote sql.NullString
+	var wasselStatus sql.NullInt64
+	var decidedBy uuid.NullUUID
+	var decidedAt sql.NullTime
+
+	err := row.Scan(
+		&ret.ID,
+		&ret.SupplierOrderID,
+		&ret.Status,
+		&ret.Source,
+		&ret.Reason,
+		&note,
+		&wasselStatus,
+		&ret.PreviousOrderStatus,
+		&ret.Restock,
+		&decisionNote,
+		&decidedBy,
+		&decidedAt,
+		&ret.CreatedAt,
+		&ret.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.Valid {
+		ret.Note = &note.String
+	}
+	if wasselStatus.Valid {
+		v := int(wasselStatus.Int64)
+		ret.WasselStatus = &v
+	}
+	if decisionNote.Valid {
+		ret.DecisionNote = &decisionNote.String
+	}
+	if decidedBy.Valid {
+		ret.DecidedByStaffID = &decidedBy.UUID
+	}
+	if decidedAt.Valid {
+		ret.DecidedAt = &decidedAt.Time
+	}
+	return &ret, nil
+}
@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type partnerSLAThresholdRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewPartnerSLAThresholdRepository creates a new per-partner SLA threshold repository
+func NewPartnerSLAThresholdRepository(db *sql.DB, logger *zap.Logger) *partnerSLAThresholdRepository {
+	return &partnerSLAThresholdRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *partnerSLAThresholdRepository) Upsert(ctx context.Context, threshold *domain.PartnerSLAThreshold) error {
+	// Each target has its own partial unique index, so the conflict target depends on which one is set
+	conflict := `(partner_id, order_status) WHERE order_status IS NOT NULL`
+	if threshold.DeliveryStatus != nil {
+		conflict = `(partner_id, delivery_status) WHERE delivery_status IS NOT NULL`
+	}
+	query := `
+		INSERT INTO partner_sla_thresholds (id, partner_id, order_status, delivery_status, max_minutes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT ` + conflict + `
+		DO UPDATE SET max_minutes = EXCLUDED.max_minutes, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	now := time.Now()
+	if threshold.ID == uuid.Nil {
+		threshold.ID = uuid.New()
+	}
+	threshold.CreatedAt = now
+	threshold.UpdatedAt = now
+
+	err := r.db.QueryRowContext(ctx, query,
+		threshold.ID,
+		threshold.PartnerID,
+		threshold.OrderStatus,
+		threshold.DeliveryStatus,
+		threshold.MaxMinutes,
+		threshold.CreatedAt,
+		threshold.UpdatedAt,
+	).Scan(&threshold.ID, &threshold.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to upsert partner SLA threshold", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *partnerSLAThresholdRepository) Delete(ctx context.Context, partnerID uuid.UUID, orderStatus *domain.OrderStatus, deliveryStatus *int) error {
+	query := `
+		DELETE FROM partner_sla_thresholds
+		WHERE partner_id = $1 AND order_status IS NOT DISTINCT FROM $2 AND delivery_status IS NOT DISTINCT FROM $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, partnerID, orderStatus, deliveryStatus)
+	if err != nil {
+		r.logger.Error("Failed to delete partner SLA threshold", zap.Error(err))
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &errors.ErrNotFound{Resource: "partner_sla_threshold", ID: partnerID.String()}
+	}
+	return nil
+}
+
+// List returns every partner's thresholds.
+func (r *partnerSLAThresholdRepository) List(ctx context.Context) ([]*domain.PartnerSLAThreshold, error) {
+	query := `
+		SELECT id, partner_id, order_status, delivery_status, max_minutes, created_at, updated_at
+		FROM partner_sla_thresholds
+		ORDER BY partner_id, order_status, delivery_status
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("Failed to list partner SLA thresholds", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var thresholds []*domain.PartnerSLAThreshold
+	for rows.Next() {
+		var t domain.PartnerSLAThreshold
+		var orderStatus sql.NullString
+		var deliveryStatus sql.NullInt64
+		if err := rows.Scan(&t.ID, &t.PartnerID, &orderStatus, &deliveryStatus, &t.MaxMinutes, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if orderStatus.Valid {
+			status := domain.OrderStatus(orderStatus.String)
+			t.OrderStatus = &status
+		}
+		t.DeliveryStatus = intPtr(deliveryStatus)
+		thresholds = append(thresholds, &t)
+	}
+	return thresholds, rows.Err()
+}
+
+type slaBreachRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewSLABreachRepository creates a new SLA breach repository
+func NewSLABreachRepository(db *sql.DB, logger *zap.Logger) *slaBreachRepository {
+	return &slaBreachRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const slaBreachColumns = `
+	b.id, b.supplier_order_id, b.partner_id, o.partner_order_id, b.order_status, b.delivery_status, b.since,
+	b.threshold_minutes, b.detected_at, b.alerted_at, b.resolved_at
+`
+
+func (r *slaBreachRepository) Create(ctx context.Context, breach *domain.SLABreach) (bool, error) {
+	query := `
+		INSERT INTO sla_breaches (
+			id, supplier_order_id, partner_id, order_status, delivery_status, since, threshold_minutes, detected_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT DO NOTHING
+	`
+
+	if breach.ID == uuid.Nil {
+		breach.ID = uuid.New()
+	}
+	if breach.DetectedAt.IsZero() {
+		breach.DetectedAt = time.Now()
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		breach.ID,
+		breach.SupplierOrderID,
+		breach.PartnerID,
+		breach.OrderStatus,
+		breach.DeliveryStatus,
+		breach.Since,
+		breach.ThresholdMinutes,
+		breach.DetectedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create SLA breach", zap.Error(err))
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (r *slaBreachRepository) ListOpen(ctx context.Context) ([]*domain.SLABreach, error) {
+	query := `
+		SELECT ` + slaBreachColumns + `
+		FROM sla_breaches b
+		JOIN supplier_orders o ON o.id = b.supplier_order_id
+		WHERE b.resolved_at IS NULL
+		ORDER BY b.detected_at, b.id
+	`
+	return r.query(ctx, query)
+}
+
+func (r *slaBreachRepository) List(ctx context.Context, filter repository.SLABreachFilter) ([]*domain.SLABreach, error) {
+	var qb queryBuilder
+	if filter.PartnerID != nil {
+		qb.where("b.partner_id = " + qb.arg(*filter.PartnerID))
+	}
+	if filter.Open != nil {
+		if *filter.Open {
+			qb.where("b.resolved_at IS NULL")
+		} else {
+			qb.where("b.resolved_at IS NOT NULL")
+		}
+	}
+	query := `
+		SELECT ` + slaBreachColumns + `
+		FROM sla_breaches b
+		JOIN supplier_orders o ON o.id = b.supplier_order_id` + qb.whereClause() + `
+		ORDER BY b.detected_at DESC, b.id DESC
+		LIMIT ` + qb.arg(filter.Limit)
+	return r.query(ctx, query, qb.args...)
+}
+
+func (r *slaBreachRepository) Resolve(ctx context.Context, id uuid.UUID, resolvedAt time.Time) error {
+	query := `UPDATE sla_breaches SET resolved_at = $2 WHERE id = $1 AND resolved_at IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, id, resolvedAt); err != nil {
+		r.logger.Error("Failed to resolve SLA breach", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *slaBreachRepository) MarkAlerted(ctx context.Context, ids []uuid.UUID, alertedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := `UPDATE sla_breaches SET alerted_at = $2 WHERE id = ANY($1::uuid[]) AND alerted_at IS NULL`
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(values), alertedAt); err != nil {
+		r.logger.Error("Failed to mark SLA breaches alerted", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *slaBreachRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.SLABreach, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list SLA breaches", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breaches []*domain.SLABreach
+	for rows.Next() {
+		var b domain.SLABreach
+		var deliveryStatus sql.NullInt64
+		var alertedAt, resolvedAt sql.NullTime
+		err := rows.Scan(
+			&b.ID,
+			&b.SupplierOrderID,
+			&b.PartnerID,
+			&b.PartnerOrderID,
+			&b.OrderStatus,
+			&deliveryStatus,
+			&b.Since,
+			&b.ThresholdMinutes,
+			&b.DetectedAt,
+			&alertedAt,
+			&resolvedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		b.DeliveryStatus = intPtr(deliveryStatus)
+		if alertedAt.Valid {
+			b.AlertedAt = &alertedAt.Time
+		}
+		if resolvedAt.Valid {
+			b.ResolvedAt = &resolvedAt.Time
+		}
+		breaches = append(breaches, &b)
+	}
+	return breaches, rows.Err()
+}
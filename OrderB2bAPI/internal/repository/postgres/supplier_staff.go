@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type supplierStaffRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewSupplierStaffRepository creates a new supplier staff repository
+func NewSupplierStaffRepository(db *sql.DB, logger *zap.Logger) *supplierStaffRepository {
+	return &supplierStaffRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetByAPIKey finds an active staff member by api_key_lookup (SHA256 hex) and verifies the key with bcrypt.
+func (r *supplierStaffRepository) GetByAPIKey(ctx context.Context, apiKey string) (*domain.SupplierStaff, error) {
+	query := `
+		SELECT id, name, email, api_key_hash, api_key_lookup, is_active, created_at, updated_at
+		FROM supplier_staff
+		WHERE is_active = true AND api_key_lookup = $1
+	`
+	var staff domain.SupplierStaff
+	var email sql.NullString
+	err := r.db.QueryRowContext(ctx, query, apiKeyLookupHash(apiKey)).Scan(
+		&staff.ID,
+		&staff.Name,
+		&email,
+		&staff.APIKeyHash,
+		&staff.APIKeyLookup,
+		&staff.IsActive,
+		&staff.CreatedAt,
+		&staff.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrUnauthorized{Message: "invalid API key"}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get supplier staff by API key", zap.Error(err))
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(staff.APIKeyHash), []byte(apiKey)) != nil {
+		r.logger.Debug("Staff API key lookup matched but bcrypt verification failed", zap.String("staff_id", staff.ID.String()))
+		return nil, &errors.ErrUnauthorized{Message: "invalid API key"}
+	}
+	if email.Valid {
+		staff.Email = &email.String
+	}
+	return &staff, nil
+}
+
+func (r *supplierStaffRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierStaff, error) {
+	query := `
+		SELECT id, name, email, api_key_hash, api_key_lookup, is_active, created_at, updated_at
+		FROM supplier_staff
+		WHERE id = $1
+	`
+	var staff domain.SupplierStaff
+	var email sql.NullString
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&staff.ID,
+		&staff.Name,
+		&email,
+		&staff.APIKeyHash,
+		&staff.APIKeyLookup,
+		&staff.IsActive,
+		&staff.CreatedAt,
+		&staff.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "supplier_staff", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get supplier staff by ID", zap.Error(err))
+		return nil, err
+	}
+	if email.Valid {
+		staff.Email = &email.String
+	}
+	return &staff, nil
+}
+
+func (r *supplierStaffRepository) List(ctx context.Context) ([]*domain.SupplierStaff, error) {
+	query := `
+		SELECT id, name, email, is_active, created_at, updated_at
+		FROM supplier_staff
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("Failed to list supplier staff", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*domain.SupplierStaff
+	for rows.Next() {
+		var s domain.SupplierStaff
+		var email sql.NullString
+		if err := rows.Scan(&s.ID, &s.Name, &email, &s.IsActive, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			r.logger.Error("Failed to scan supplier staff", zap.Error(err))
+			return nil, err
+		}
+		if email.Valid {
+			s.Email = &email.String
+		}
+		out = append(out, &s)
+	}
+	return out, rows.Err()
+}
+
+func (r *supplierStaffRepository) Create(ctx context.Context, staff *domain.SupplierStaff) error {
+	query := `
+		INSERT INTO supplier_staff (id, name, email, api_key_hash, api_key_lookup, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	now := time.Now()
+	if staff.ID == uuid.Nil {
+		staff.ID = uuid.New()
+	}
+	if staff.CreatedAt.IsZero() {
+		staff.CreatedAt = now
+	}
+	if staff.UpdatedAt.IsZero() {
+		staff.UpdatedAt = now
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		staff.ID,
+		staff.Name,
+		staff.Email,
+		staff.APIKeyHash,
+		staff.APIKeyLookup,
+		staff.IsActive,
+		staff.CreatedAt,
+		staff.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create supplier staff", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+	"github.com/jafarshop/b2bapi/pkg/gazetteer"
+)
+
+// jordanNames are the country names and codes partners send for Jordan (compared lowercase).
+var jordanNames = map[string]bool{
+	"":                                true, // country defaults to Jordan
+	"jo":                              true,
+	"jor":                             true,
+	"jordan":                          true,
+	"hashemite kingdom of jordan":     true,
+	"the hashemite kingdom of jordan": true,
+	"الأردن":                          true,
+	"الاردن":                          true,
+	"المملكة الأردنية الهاشمية": true,
+	"المملكة الاردنية الهاشمية": true,
+}
+
+// isJordan reports whether country names Jordan; an empty country is taken to be Jordan.
+func isJordan(country string) bool {
+	return jordanNames[strings.ToLower(strings.Join(strings.Fields(country), " "))]
+}
+
+// NormalizedAddress is the canonical city and area of a cart's shipping address, as stored on the order.
+type NormalizedAddress struct {
+	City            string `json:"city"`
+	CityCode        string `json:"city_code,omitempty"`
+	Area            string `json:"area,omitempty"`
+	AreaCode        string `json:"area_code,omitempty"`
+	GovernorateCode string `json:"governorate_code,omitempty"` // ISO 3166-2, e.g. JO-AM
+	Corrected       bool   `json:"corrected,omitempty"`        // a misspelt city or area was matched
+}
+
+// NormalizeShippingAddress matches the city and area of a Jordanian shipping address against the gazetteer and
+// rewrites them to their canonical English names, setting the city, area and governorate codes that
+// BuildShippingAddress stores. An area sent as the city (e.g. "Khalda") resolves to its city and becomes the
+// area unless the area field names another known one. Areas not in the gazetteer are kept as sent. A city not in
+// the gazetteer is kept as sent under the lenient policy, and returns *errors.ErrValidation under the strict
+// policy. Addresses outside Jordan are left unchanged.
+func NormalizeShippingAddress(shipping *ShippingAddress, policy domain.AddressPolicy) (*NormalizedAddress, error) {
+	shipping.City = strings.Join(strings.Fields(shipping.City), " ")
+	shipping.Area = strings.Join(strings.Fields(shipping.Area), " ")
+	if !isJordan(shipping.Country) {
+		return &NormalizedAddress{City: shipping.City, Area: shipping.Area}, nil
+	}
+
+	var area *gazetteer.Area
+	city, corrected, ok := gazetteer.FindCity(shipping.City)
+	if !ok {
+		city, area, corrected, ok = gazetteer.FindAnyArea(shipping.City)
+	}
+	if !ok {
+		if policy == domain.AddressPolicyStrict {
+			return nil, &errors.ErrValidation{
+				Message: "unknown city",
+				Fields:  map[string]string{"shipping.city": "must be a city in Jordan"},
+			}
+		}
+		return &NormalizedAddress{City: shipping.City, Area: shipping.Area}, nil
+	}
+
+	// A known area in the area field wins over one sent as the city
+	if shipping.Area != "" {
+		if found, areaCorrected, ok := city.FindArea(shipping.Area); ok {
+			area = found
+			corrected = corrected || areaCorrected
+		}
+	}
+
+	shipping.City = city.NameEN
+	shipping.CityCode = city.Code
+	shipping.GovernorateCode = city.Governorate
+	shipping.Country = "Jordan"
+	if area != nil {
+		shipping.Area = area.NameEN
+		shipping.AreaCode = area.Code
+	}
+	return &NormalizedAddress{
+		City:            shipping.City,
+		CityCode:        shipping.CityCode,
+		Area:            shipping.Area,
+		AreaCode:        shipping.AreaCode,
+		GovernorateCode: shipping.GovernorateCode,
+		Corrected:       corrected,
+	}, nil
+}
@@ -67,8 +67,8 @@ func RunCatalogSyncOnce(ctx context.Context, cfg *config.Config, repos *reposito
 					if r.Title != "" {
 						m.Title = &r.Title
 					}
-					if r.Price != "" {
-						m.Price = &r.Price
+					if price, err := domain.ParseMoney(r.Price, ""); err == nil {
+						m.Price = &price
 					}
 					m.ImageURL = r.ImageURL
 					allMappings = append(allMappings, m)
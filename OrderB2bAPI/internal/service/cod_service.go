@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// WasselStatusDelivered is the Wassel status for a shipment handed to the customer (cash collected for COD orders)
+const WasselStatusDelivered = 170
+
+type codService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewCODService creates a new cash-on-delivery ledger service
+func NewCODService(repos *repository.Repositories, logger *zap.Logger) *codService {
+	return &codService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// RecordDelivery records the cash Wassel collected when a pay-on-delivery order was delivered, marks the order
+// paid and logs a payment_collected event. Returns true only the first time (repeated delivery webhooks and
+// prepaid orders are a no-op), so the caller knows when to mark the Shopify order paid.
+func (s *codService) RecordDelivery(ctx context.Context, order *domain.SupplierOrder, waybill string) (bool, error) {
+	if !domain.IsPaidOnDelivery(order.PaymentMethod) {
+		return false, nil
+	}
+
+	entry := &domain.CODLedgerEntry{
+		PartnerID:       order.PartnerID,
+		SupplierOrderID: order.ID,
+		EntryType:       domain.CODEntryCollected,
+		Amount:          order.CartTotal,
+	}
+	if waybill != "" {
+		entry.Waybill = &waybill
+	}
+	created, err := s.repos.CODLedger.CreateEntry(ctx, entry)
+	if err != nil || !created {
+		return false, err
+	}
+
+	if err := s.repos.SupplierOrder.UpdatePaymentStatus(ctx, order.ID, domain.PaymentStatusPaid); err != nil {
+		return true, err
+	}
+	order.PaymentStatus = domain.PaymentStatusPaid
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventPaymentCollected,
+		EventData: map[string]interface{}{
+			"amount":  entry.Amount,
+			"waybill": waybill,
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	s.logger.Info("Recorded COD collection",
+		zap.String("order_id", order.ID.String()),
+		zap.Stringer("amount", entry.Amount),
+		zap.String("waybill", waybill),
+	)
+	return true, nil
+}
+
+// RecordReturn records the refund owed to the customer for an approved return, valued at the returned items'
+// prices and capped at what was collected less the order's earlier refunds. Orders whose cash was never
+// collected are skipped.
+func (s *codService) RecordReturn(ctx context.Context, order *domain.SupplierOrder, ret *domain.OrderReturn) error {
+	if _, err := s.repos.CODLedger.GetCollectedByOrderID(ctx, order.ID); err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			return nil
+		}
+		return err
+	}
+
+	orderItems, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	prices := make(map[uuid.UUID]domain.Money, len(orderItems))
+	for _, item := range orderItems {
+		prices[item.ID] = item.Price
+	}
+	var amount domain.Money
+	for _, item := range ret.Items {
+		amount = amount.Add(prices[item.SupplierOrderItemID].Mul(item.Quantity))
+	}
+	if amount.Cmp(domain.Money{}) <= 0 {
+		return nil
+	}
+
+	// Capped against the collection and earlier refunds in the same transaction as the insert
+	_, err = s.repos.CODLedger.CreateReturnEntry(ctx, &domain.CODLedgerEntry{
+		PartnerID:       order.PartnerID,
+		SupplierOrderID: order.ID,
+		OrderReturnID:   &ret.ID,
+		Amount:          amount,
+	})
+	return err
+}
+
+// Settlement is a partner's COD position for one period
+type Settlement struct {
+	PartnerID uuid.UUID
+	Period    string // YYYY-MM
+	From      time.Time
+	To        time.Time // exclusive
+	Totals    *repository.CODTotals
+	Entries   []*domain.CODLedgerEntry
+}
+
+// NetPayable is what is owed to the partner: cash collected on delivery less refunds for returns.
+func (s *Settlement) NetPayable() domain.Money {
+	return s.Totals.Collected.Sub(s.Totals.Returned)
+}
+
+// AwaitingRemittance is collected cash that Wassel has not paid over yet.
+func (s *Settlement) AwaitingRemittance() domain.Money {
+	return s.Totals.Collected.Sub(s.Totals.Remitted)
+}
+
+// ParseSettlementPeriod parses a YYYY-MM period into its month in loc. An empty period is the current month.
+func ParseSettlementPeriod(period string, loc *time.Location, now time.Time) (from, to time.Time, err error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if period == "" {
+		now = now.In(loc)
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		return from, from.AddDate(0, 1, 0), nil
+	}
+	month, err := time.ParseInLocation("2006-01", period, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, &errors.ErrValidation{
+			Message: "invalid period",
+			Fields:  map[string]string{"period": "must be a month (YYYY-MM)"},
+		}
+	}
+	return month, month.AddDate(0, 1, 0), nil
+}
+
+// GetSettlement loads the partner's COD ledger entries and totals for [from, to).
+func (s *codService) GetSettlement(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (*Settlement, error) {
+	totals, err := s.repos.CODLedger.Totals(ctx, partnerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repos.CODLedger.ListEntries(ctx, partnerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &Settlement{
+		PartnerID: partnerID,
+		Period:    from.Format("2006-01"),
+		From:      from,
+		To:        to,
+		Totals:    totals,
+		Entries:   entries,
+	}, nil
+}
+
+// RecordRemittance stores a Wassel payout and links it to the collections for its waybills. Returns the
+// linked entries and the waybills that matched no unremitted collection.
+func (s *codService) RecordRemittance(ctx context.Context, remittance *domain.CODRemittance, waybills []string) ([]*domain.CODLedgerEntry, []string, error) {
+	linked, err := s.repos.CODLedger.CreateRemittance(ctx, remittance, waybills)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matched := make(map[string]bool, len(linked))
+	for _, entry := range linked {
+		if entry.Waybill != nil {
+			matched[*entry.Waybill] = true
+		}
+	}
+	unmatched := []string{}
+	for _, waybill := range waybills {
+		if !matched[waybill] {
+			unmatched = append(unmatched, waybill)
+		}
+	}
+
+	s.logger.Info("Recorded Wassel COD remittance",
+		zap.String("reference", remittance.Reference),
+		zap.Stringer("amount", remittance.Amount),
+		zap.Int("linked", len(linked)),
+		zap.Int("unmatched", len(unmatched)),
+	)
+	return linked, unmatched, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// creditExposureStatuses are the statuses whose unpaid orders count against a partner's credit: everything not
+// yet delivered, plus delivered orders whose payment has not been recorded. Rejected, canceled, returned,
+// refunded and archived orders do not count.
+var creditExposureStatuses = []domain.OrderStatus{
+	domain.OrderStatusIncompleteCaution,
+	domain.OrderStatusUnfulfilled,
+	domain.OrderStatusPartiallyFulfilled,
+	domain.OrderStatusFulfilled,
+	domain.OrderStatusReturnInProgress,
+	domain.OrderStatusComplete,
+}
+
+// CreditAccount is a partner's credit position. Limit is nil when the partner has no credit limit.
+type CreditAccount struct {
+	PartnerID  uuid.UUID
+	Limit      *domain.Money
+	Policy     domain.CreditPolicy
+	Exposure   domain.Money // total of open unpaid orders
+	OpenOrders int
+}
+
+// Available is the credit left (never negative); nil when there is no limit.
+func (a *CreditAccount) Available() *domain.Money {
+	if a.Limit == nil {
+		return nil
+	}
+	available := a.Limit.Sub(a.Exposure)
+	if available.IsNegative() {
+		available = domain.NewMoney(0, available.Currency())
+	}
+	return &available
+}
+
+// CreditCheck is the result of the credit check on a cart that would exceed the limit.
+type CreditCheck struct {
+	Account    *CreditAccount
+	OrderTotal domain.Money
+	HoldReason string // set under the hold policy
+}
+
+type creditService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewCreditService creates a new partner credit service
+func NewCreditService(repos *repository.Repositories, logger *zap.Logger) *creditService {
+	return &creditService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// GetAccount returns the partner's credit limit and current exposure.
+func (s *creditService) GetAccount(ctx context.Context, partner *domain.Partner) (*CreditAccount, error) {
+	exposure, err := s.repos.SupplierOrder.CreditExposure(ctx, partner.ID, creditExposureStatuses, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	return newCreditAccount(partner, exposure), nil
+}
+
+func newCreditAccount(partner *domain.Partner, exposure *repository.CreditExposure) *CreditAccount {
+	policy := partner.CreditPolicy
+	if !policy.IsValid() {
+		policy = domain.CreditPolicyReject
+	}
+	var limit *domain.Money
+	if partner.CreditLimit != nil {
+		l := partner.CreditLimit.In(partner.Currency)
+		limit = &l
+	}
+	return &CreditAccount{
+		PartnerID:  partner.ID,
+		Limit:      limit,
+		Policy:     policy,
+		Exposure:   exposure.Amount.In(partner.Currency),
+		OpenOrders: exposure.Orders,
+	}
+}
+
+// checkCartCredit checks whether an order of orderTotal keeps the partner within its credit limit, given its
+// current exposure. Returns nil when the partner stays within it. When it would not: reject returns
+// *errors.ErrCreditLimitExceeded, hold returns a check with HoldReason set.
+func checkCartCredit(partner *domain.Partner, exposure *repository.CreditExposure, orderTotal domain.Money) (*CreditCheck, error) {
+	account := newCreditAccount(partner, exposure)
+	if account.Limit == nil || account.Exposure.Add(orderTotal).Cmp(*account.Limit) <= 0 {
+		return nil, nil
+	}
+
+	if account.Policy == domain.CreditPolicyReject {
+		return nil, &errors.ErrCreditLimitExceeded{
+			Limit:     *account.Limit,
+			Exposure:  account.Exposure,
+			Requested: orderTotal,
+		}
+	}
+	return &CreditCheck{
+		Account:    account,
+		OrderTotal: orderTotal,
+		HoldReason: fmt.Sprintf("Credit limit exceeded: order %s, available credit %s of %s", orderTotal, account.Available(), account.Limit),
+	}, nil
+}
+
+// CheckAmendmentCredit checks an amendment raising order's total to total against the partner's credit limit
+// (without taking the lock AmendOrder checks it under), so an amendment the reject policy would refuse can be
+// refused before Shopify is edited. Returns nil when the total does not go up or the partner stays within its limit.
+func (s *orderService) CheckAmendmentCredit(ctx context.Context, partner *domain.Partner, order *domain.SupplierOrder, total domain.Money) (*CreditCheck, error) {
+	if partner.CreditLimit == nil || total.Cmp(order.CartTotal) <= 0 {
+		return nil, nil
+	}
+	exposure, err := s.repos.SupplierOrder.CreditExposure(ctx, partner.ID, creditExposureStatuses, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	return checkCartCredit(partner, exposure, total)
+}
+
+// RecordCreditCheck holds the order (added to any hold reason it already has) and logs a credit_limit_exceeded
+// event.
+func (s *orderService) RecordCreditCheck(ctx context.Context, order *domain.SupplierOrder, check *CreditCheck) error {
+	if check == nil {
+		return nil
+	}
+
+	holdReason := check.HoldReason
+	if order.HoldReason != nil && *order.HoldReason != "" {
+		holdReason = *order.HoldReason + "; " + holdReason
+	}
+	if err := s.repos.SupplierOrder.UpdateHoldReason(ctx, order.ID, &holdReason); err != nil {
+		return err
+	}
+	order.HoldReason = &holdReason
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventCreditLimitExceeded,
+		EventData: map[string]interface{}{
+			"credit_limit": *check.Account.Limit,
+			"exposure":     check.Account.Exposure,
+			"order_total":  check.OrderTotal,
+			"held":         true,
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+	return nil
+}
+
+// RecordPayment marks an unpaid order paid (e.g. the partner settled it on account) and logs a payment_collected
+// event, which frees its amount from the partner's credit exposure. Returns false when it was already paid.
+func (s *creditService) RecordPayment(ctx context.Context, order *domain.SupplierOrder, staffID uuid.UUID, reference string) (bool, error) {
+	if order.PaymentStatus == domain.PaymentStatusPaid {
+		return false, nil
+	}
+	if err := s.repos.SupplierOrder.UpdatePaymentStatus(ctx, order.ID, domain.PaymentStatusPaid); err != nil {
+		return false, err
+	}
+	order.PaymentStatus = domain.PaymentStatusPaid
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventPaymentCollected,
+		EventData: map[string]interface{}{
+			"amount":   order.CartTotal,
+			"source":   "staff",
+			"staff_id": staffID.String(),
+		},
+	}
+	if reference != "" {
+		event.EventData["reference"] = reference
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	s.logger.Info("Recorded order payment",
+		zap.String("order_id", order.ID.String()),
+		zap.Stringer("amount", order.CartTotal),
+		zap.String("staff_id", staffID.String()),
+	)
+	return true, nil
+}
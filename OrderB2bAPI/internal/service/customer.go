@@ -0,0 +1,21 @@
+package service
+
+import (
+	"github.com/jafarshop/b2bapi/pkg/errors"
+	"github.com/jafarshop/b2bapi/pkg/phone"
+)
+
+// NormalizeCustomerPhone rewrites the cart's customer phone to E.164, the form it is stored and matched to Shopify
+// customers in. Returns *errors.ErrValidation when it is not a valid number, or is a Jordanian landline (the
+// courier calls and texts the customer).
+func NormalizeCustomerPhone(req *CartSubmitRequest) error {
+	number, err := phone.Parse(req.Customer.Phone)
+	if err != nil || !phone.IsMobile(number) {
+		return &errors.ErrValidation{
+			Message: "invalid phone number",
+			Fields:  map[string]string{"customer.phone_number": "must be a mobile number, e.g. 0791234567 or +962791234567"},
+		}
+	}
+	req.Customer.Phone = number
+	return nil
+}
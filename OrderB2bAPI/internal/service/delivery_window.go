@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// ParseDeliveryWindow validates a requested delivery window against cfg: the date must be between today and
+// MaxDaysAhead days from now (in cfg.Location) and not a blackout weekday or holiday; times must be HH:MM with
+// from before to. A nil req returns nil. Errors are *errors.ErrValidation keyed by field.
+func ParseDeliveryWindow(req *DeliveryWindowRequest, cfg config.DeliveryWindowConfig, now time.Time) (*domain.DeliveryWindow, error) {
+	if req == nil {
+		return nil, nil
+	}
+	fields := make(map[string]string)
+	loc := cfg.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		fields["delivery_window.date"] = "must be a date (YYYY-MM-DD)"
+	} else {
+		y, m, d := now.In(loc).Date()
+		today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		switch {
+		case date.Before(today):
+			fields["delivery_window.date"] = "must not be in the past"
+		case cfg.MaxDaysAhead > 0 && date.After(today.AddDate(0, 0, cfg.MaxDaysAhead)):
+			fields["delivery_window.date"] = fmt.Sprintf("must be within %d days", cfg.MaxDaysAhead)
+		case cfg.BlackoutDates[req.Date]:
+			fields["delivery_window.date"] = fmt.Sprintf("no deliveries on %s (holiday)", req.Date)
+		default:
+			for _, weekday := range cfg.BlackoutWeekdays {
+				if date.Weekday() == weekday {
+					fields["delivery_window.date"] = fmt.Sprintf("no deliveries on %ss", weekday)
+					break
+				}
+			}
+		}
+	}
+
+	window := &domain.DeliveryWindow{Date: date}
+	parseTime := func(field, value string) *string {
+		if value == "" {
+			return nil
+		}
+		t, err := time.Parse("15:04", value)
+		if err != nil {
+			fields[field] = "must be a time (HH:MM, 24-hour)"
+			return nil
+		}
+		s := t.Format("15:04")
+		return &s
+	}
+	window.From = parseTime("delivery_window.from", req.From)
+	window.To = parseTime("delivery_window.to", req.To)
+	// HH:MM strings compare in time order
+	if window.From != nil && window.To != nil && *window.From >= *window.To {
+		fields["delivery_window.to"] = "must be after from"
+	}
+
+	if len(fields) > 0 {
+		return nil, &errors.ErrValidation{Message: "invalid delivery window", Fields: fields}
+	}
+	return window, nil
+}
+
+// deliveryWindowAttributes are the draft order custom attributes the dispatch team reads in Shopify admin.
+func deliveryWindowAttributes(w *domain.DeliveryWindow) []shopify.DraftOrderAttributeInput {
+	attrs := []shopify.DraftOrderAttributeInput{
+		{Key: "Delivery date", Value: w.DateString()},
+	}
+	if r := w.TimeRange(); r != "" {
+		attrs = append(attrs, shopify.DraftOrderAttributeInput{Key: "Delivery window", Value: r})
+	}
+	return attrs
+}
@@ -1,5 +1,7 @@
 package service
 
+import "github.com/jafarshop/b2bapi/internal/domain"
+
 // CartSubmitRequest represents the cart submission payload
 type CartSubmitRequest struct {
 	PartnerOrderID string                 `json:"partner_order_id" binding:"required"`
@@ -8,14 +10,23 @@ type CartSubmitRequest struct {
 	Shipping       ShippingAddress         `json:"shipping" binding:"required"`
 	Totals         CartTotals             `json:"totals" binding:"required"`
 	PaymentMethod  *string                `json:"payment_method,omitempty"`
+	DeliveryWindow *DeliveryWindowRequest `json:"delivery_window,omitempty"`
+}
+
+// DeliveryWindowRequest is the partner's preferred delivery date (YYYY-MM-DD) and optional time range (HH:MM,
+// warehouse local time). Checked by ParseDeliveryWindow.
+type DeliveryWindowRequest struct {
+	Date string `json:"date" binding:"required"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
 }
 
 type CartItem struct {
-	SKU        string  `json:"sku" binding:"required"`
-	Title      string  `json:"title" binding:"required"`
-	Price      float64 `json:"price" binding:"required,min=0"`
-	Quantity   int     `json:"quantity" binding:"required,min=1"`
-	ProductURL *string `json:"product_url,omitempty"`
+	SKU        string       `json:"sku" binding:"required"`
+	Title      string       `json:"title" binding:"required"`
+	Price      domain.Money `json:"price" binding:"required,min=0"`
+	Quantity   int          `json:"quantity" binding:"required,min=1"`
+	ProductURL *string      `json:"product_url,omitempty"`
 }
 
 // CustomerInfo matches the format received from Zain: first name, last name, email, phone.
@@ -33,11 +44,96 @@ type ShippingAddress struct {
 	Address    string `json:"address" binding:"required"`
 	PostalCode string `json:"postal_code"`
 	Country    string `json:"country"`
+
+	// Set by NormalizeShippingAddress for addresses found in the Jordan gazetteer
+	CityCode        string `json:"-"`
+	AreaCode        string `json:"-"`
+	GovernorateCode string `json:"-"`
 }
 
+// CartTotals are the cart's amounts in the partner's currency. Currency is optional; when sent it must be the
+// partner's.
 type CartTotals struct {
-	Subtotal float64 `json:"subtotal" binding:"required,min=0"`
-	Tax      float64 `json:"tax" binding:"min=0"`
-	Shipping float64 `json:"shipping" binding:"min=0"`
-	Total    float64 `json:"total" binding:"required,min=0"`
-}
\ No newline at end of file
+	Subtotal domain.Money `json:"subtotal" binding:"required,min=0"`
+	Tax      domain.Money `json:"tax" binding:"min=0"`
+	Shipping domain.Money `json:"shipping" binding:"min=0"`
+	Total    domain.Money `json:"total" binding:"required,min=0"`
+	Currency string       `json:"currency,omitempty"`
+}
+// OrderAmendRequest represents a PATCH /v1/orders/:id payload. Omitted sections are left unchanged;
+// items, when present, replace the order's full item list.
+type OrderAmendRequest struct {
+	Items    []CartItem       `json:"items,omitempty" binding:"omitempty,min=1,dive"`
+	Shipping *ShippingAddress `json:"shipping,omitempty"`
+	Totals   *CartTotals      `json:"totals,omitempty"`
+}
+
+// ReturnRequest represents a partner return request (POST /v1/orders/:id/returns).
+// Items defaults to everything shipped on the order.
+type ReturnRequest struct {
+	Reason string              `json:"reason" binding:"required"`
+	Note   string              `json:"note"`
+	Items  []ReturnItemRequest `json:"items,omitempty" binding:"omitempty,dive"`
+}
+
+type ReturnItemRequest struct {
+	SKU      string `json:"sku" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,min=1"`
+}
+
+// OrderNoteRequest represents a POST /v1/orders/:id/notes (or /v1/supplier/orders/:id/notes) payload.
+type OrderNoteRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+// CODRemittanceRequest represents a Wassel COD payout (POST /v1/supplier/cod/remittances).
+// RemittedAt (RFC 3339) defaults to now.
+type CODRemittanceRequest struct {
+	Reference  string       `json:"reference" binding:"required,max=100"`
+	Amount     domain.Money `json:"amount" binding:"min=0"`
+	RemittedAt string       `json:"remitted_at,omitempty"`
+	Waybills   []string     `json:"waybills" binding:"required,min=1,dive,required"`
+	Note       string       `json:"note,omitempty"`
+}
+
+// PartnerPriceRequest is a partner price list rule (POST /v1/supplier/price-lists). Exactly one of sku /
+// collection_handle and one of fixed_price / discount_percent is required; valid_from / valid_to are optional
+// YYYY-MM-DD dates (inclusive).
+type PartnerPriceRequest struct {
+	PartnerID        string        `json:"partner_id" binding:"required"`
+	SKU              string        `json:"sku,omitempty"`
+	CollectionHandle string        `json:"collection_handle,omitempty"`
+	FixedPrice       *domain.Money `json:"fixed_price,omitempty"`
+	DiscountPercent  *float64      `json:"discount_percent,omitempty"`
+	ValidFrom        string        `json:"valid_from,omitempty"`
+	ValidTo          string        `json:"valid_to,omitempty"`
+}
+
+// OrderRuleRequest is a partner auto-confirmation rule (POST /v1/supplier/order-rules, or inline in a dry run,
+// where partner_id is not needed). name and action (confirm or hold) are required; conditions left out always
+// hold. cities are gazetteer city names or codes.
+type OrderRuleRequest struct {
+	PartnerID      string        `json:"partner_id,omitempty"`
+	Name           string        `json:"name"`
+	Action         string        `json:"action"`
+	Priority       *int          `json:"priority,omitempty"`
+	MinCartTotal   *domain.Money `json:"min_cart_total,omitempty"`
+	MaxCartTotal   *domain.Money `json:"max_cart_total,omitempty"`
+	Cities         []string      `json:"cities,omitempty"`
+	MinItemCount   *int          `json:"min_item_count,omitempty"`
+	MaxItemCount   *int          `json:"max_item_count,omitempty"`
+	MinDelivered   *int          `json:"min_delivered,omitempty"`
+	MaxReturned    *int          `json:"max_returned,omitempty"`
+	PaymentMethods []string      `json:"payment_methods,omitempty"`
+}
+
+// OrderRuleDryRunRequest tests rules against the partner's past orders (POST /v1/supplier/order-rules/dry-run).
+// Without rules the partner's saved rules are used. from / to are YYYY-MM-DD dates (inclusive, warehouse time);
+// the last 30 days by default.
+type OrderRuleDryRunRequest struct {
+	PartnerID string             `json:"partner_id" binding:"required"`
+	Rules     []OrderRuleRequest `json:"rules,omitempty"`
+	From      string             `json:"from,omitempty"`
+	To        string             `json:"to,omitempty"`
+	Limit     int                `json:"limit,omitempty"`
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// duplicateCandidateLimit is how many of the customer's recent orders are compared with a new one.
+const duplicateCandidateLimit = 20
+
+// itemSet is an order's quantity per SKU.
+func itemSet(items []*domain.SupplierOrderItem) map[string]int {
+	set := make(map[string]int, len(items))
+	for _, item := range items {
+		set[item.SKU] += item.Quantity
+	}
+	return set
+}
+
+func sameItemSet(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for sku, quantity := range a {
+		if b[sku] != quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// FindPossibleDuplicate returns the most recent order the partner placed within window before order, for the same
+// customer phone and the same SKUs and quantities, or nil. Partners retrying a cart under a regenerated
+// partner_order_id are not caught by idempotency. Rejected and canceled orders are ignored, as is a zero window.
+func (s *orderService) FindPossibleDuplicate(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem, window time.Duration) (*domain.SupplierOrder, error) {
+	if window <= 0 || order.CustomerPhone == "" {
+		return nil, nil
+	}
+
+	statuses := make([]domain.OrderStatus, 0, len(domain.OrderStatuses))
+	for _, status := range domain.OrderStatuses {
+		if status != domain.OrderStatusRejected && status != domain.OrderStatusCanceled {
+			statuses = append(statuses, status)
+		}
+	}
+	from := order.CreatedAt.Add(-window)
+	page, err := s.repos.SupplierOrder.ListOrders(ctx, repository.OrderListFilter{
+		PartnerID:     &order.PartnerID,
+		Statuses:      statuses,
+		CustomerPhone: order.CustomerPhone,
+		CreatedFrom:   &from,
+		CreatedTo:     &order.CreatedAt,
+		Limit:         duplicateCandidateLimit,
+		SkipTotal:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]uuid.UUID, 0, len(page.Orders))
+	for _, candidate := range page.Orders {
+		if candidate.ID != order.ID {
+			candidates = append(candidates, candidate.ID)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	itemsByOrder, err := s.repos.SupplierOrderItem.GetByOrderIDs(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Listed newest first
+	want := itemSet(items)
+	for _, candidate := range page.Orders {
+		if candidate.ID != order.ID && sameItemSet(want, itemSet(itemsByOrder[candidate.ID])) {
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
+// RecordPossibleDuplicate holds the order as a possible duplicate of original (added to any hold reason it already
+// has) and logs a possible_duplicate event.
+func (s *orderService) RecordPossibleDuplicate(ctx context.Context, order, original *domain.SupplierOrder) error {
+	holdReason := fmt.Sprintf("Possible duplicate of order %s (same customer and items, placed %s)",
+		original.PartnerOrderID, original.CreatedAt.UTC().Format(time.RFC3339))
+	if order.HoldReason != nil && *order.HoldReason != "" {
+		holdReason = *order.HoldReason + "; " + holdReason
+	}
+	if err := s.repos.SupplierOrder.MarkPossibleDuplicate(ctx, order.ID, original.ID, holdReason); err != nil {
+		return err
+	}
+	order.HoldReason = &holdReason
+	order.PossibleDuplicateOf = &original.ID
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventPossibleDuplicate,
+		EventData: map[string]interface{}{
+			"duplicate_of":                  original.ID.String(),
+			"duplicate_of_partner_order_id": original.PartnerOrderID,
+			"customer_phone":                order.CustomerPhone,
+			"held":                          true,
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+	return nil
+}
+
+// HeldAsDuplicate reports whether the order is waiting for review as a possible duplicate. It is kept out of
+// Shopify until it is confirmed.
+func HeldAsDuplicate(order *domain.SupplierOrder) bool {
+	return order.PossibleDuplicateOf != nil && order.Status == domain.OrderStatusIncompleteCaution
+}
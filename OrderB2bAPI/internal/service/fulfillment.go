@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// ShopifyFulfillment is a Shopify fulfillment (fulfillments/create or fulfillments/update webhook) for one order.
+type ShopifyFulfillment struct {
+	ID              int64
+	TrackingCarrier *string
+	TrackingNumber  *string
+	TrackingURL     *string
+	LineItems       []ShopifyFulfillmentLine
+}
+
+// ShopifyFulfillmentLine is one fulfilled Shopify line item. VariantID is nil for custom items.
+type ShopifyFulfillmentLine struct {
+	VariantID *int64
+	Title     string
+	Quantity  int
+}
+
+// ApplyShopifyFulfillment records which order items a Shopify fulfillment shipped and moves the order to
+// PARTIALLY_FULFILLED or FULFILLED accordingly. Re-delivered fulfillments are recorded once.
+// Returns the order status after the update.
+func (s *orderService) ApplyShopifyFulfillment(ctx context.Context, orderID uuid.UUID, f ShopifyFulfillment) (domain.OrderStatus, error) {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+
+	// A fulfillment is allocated once; fulfillments/update and redeliveries only re-evaluate the status
+	existing, err := s.repos.SupplierOrderItem.ListFulfillmentsByOrderID(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	lines := f.LineItems
+	for _, e := range existing {
+		if e.ShopifyFulfillmentID == f.ID {
+			lines = nil
+			break
+		}
+	}
+
+	// Allocate each fulfilled line to matching items that still have unshipped quantity
+	var recorded []map[string]interface{}
+	for _, line := range lines {
+		remaining := line.Quantity
+		for _, item := range items {
+			if remaining <= 0 {
+				break
+			}
+			if !fulfillmentLineMatchesItem(line, item) {
+				continue
+			}
+			open := item.Quantity - item.FulfilledQuantity
+			if open <= 0 {
+				continue
+			}
+			qty := remaining
+			if qty > open {
+				qty = open
+			}
+			ok, err := s.repos.SupplierOrderItem.RecordFulfillment(ctx, &domain.OrderItemFulfillment{
+				SupplierOrderID:      orderID,
+				SupplierOrderItemID:  item.ID,
+				ShopifyFulfillmentID: f.ID,
+				Quantity:             qty,
+				TrackingCarrier:      f.TrackingCarrier,
+				TrackingNumber:       f.TrackingNumber,
+				TrackingURL:          f.TrackingURL,
+			})
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				// Recorded concurrently by another delivery of the same webhook
+				break
+			}
+			item.FulfilledQuantity += qty
+			remaining -= qty
+			recorded = append(recorded, map[string]interface{}{"sku": item.SKU, "quantity": qty})
+		}
+	}
+
+	if len(recorded) > 0 {
+		event := &domain.OrderEvent{
+			SupplierOrderID: orderID,
+			EventType:       domain.OrderEventItemsFulfilled,
+			EventData: map[string]interface{}{
+				"shopify_fulfillment_id": f.ID,
+				"items":                  recorded,
+			},
+		}
+		if f.TrackingNumber != nil {
+			event.EventData["tracking_number"] = *f.TrackingNumber
+		}
+		if f.TrackingCarrier != nil {
+			event.EventData["tracking_carrier"] = *f.TrackingCarrier
+		}
+		s.repos.OrderEvent.Create(ctx, event)
+	}
+
+	newStatus := fulfillmentStatusForItems(items)
+	if newStatus == "" || newStatus == order.Status || !awaitingFulfillment(order.Status) {
+		return order.Status, nil
+	}
+
+	if err := s.ChangeStatus(ctx, order, newStatus, StatusChange{Source: "shopify_fulfillment"}); err != nil {
+		return "", err
+	}
+
+	s.logger.Info("Order fulfillment status updated from Shopify",
+		zap.String("order_id", orderID.String()),
+		zap.String("status", string(newStatus)))
+	return newStatus, nil
+}
+
+// fulfillmentLineMatchesItem matches supplier items by variant and other items by their custom line item title.
+func fulfillmentLineMatchesItem(line ShopifyFulfillmentLine, item *domain.SupplierOrderItem) bool {
+	if item.IsSupplierItem && item.ShopifyVariantID != nil {
+		return line.VariantID != nil && *line.VariantID == *item.ShopifyVariantID
+	}
+	return line.VariantID == nil && (line.Title == customLineItemTitle(item) || line.Title == item.Title)
+}
+
+// fulfillmentStatusForItems returns FULFILLED when every item is fully shipped, PARTIALLY_FULFILLED when
+// some quantity is shipped, and "" when nothing is.
+func fulfillmentStatusForItems(items []*domain.SupplierOrderItem) domain.OrderStatus {
+	shipped, complete := false, true
+	for _, item := range items {
+		if item.FulfilledQuantity > 0 {
+			shipped = true
+		}
+		if item.FulfilledQuantity < item.Quantity {
+			complete = false
+		}
+	}
+	switch {
+	case !shipped:
+		return ""
+	case complete:
+		return domain.OrderStatusFulfilled
+	default:
+		return domain.OrderStatusPartiallyFulfilled
+	}
+}
+
+// awaitingFulfillment reports whether Shopify fulfillments may still move the order's status
+// (not yet fully shipped, and not rejected/canceled).
+func awaitingFulfillment(status domain.OrderStatus) bool {
+	switch status {
+	case domain.OrderStatusIncompleteCaution, domain.OrderStatusUnfulfilled, domain.OrderStatusPartiallyFulfilled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/pdf"
+)
+
+// Page geometry for invoices and statements (points)
+const (
+	docLeft   = 40.0
+	docRight  = pdf.PageWidth - 40
+	docTop    = 50.0
+	docBottom = pdf.PageHeight - 60
+	docText   = 9.0
+	docRow    = 16.0
+)
+
+// docColumn is a table column; Right columns are right-aligned at X+Width.
+type docColumn struct {
+	En, Ar string
+	X      float64
+	Width  float64
+	Right  bool
+}
+
+// documentLayout writes a document top to bottom, starting a new page (with the table header again) when the
+// current one is full. Labels are English, followed by Arabic when the configured font can draw it.
+type documentLayout struct {
+	cfg     config.InvoiceConfig
+	doc     *pdf.Document
+	pages   []*pdf.Page
+	page    *pdf.Page
+	y       float64
+	arabic  bool
+	columns []docColumn // current table, repeated on continuation pages
+}
+
+func newDocumentLayout(cfg config.InvoiceConfig, title string) (*documentLayout, error) {
+	doc := pdf.New()
+	doc.SetTitle(title)
+	if len(cfg.Font) > 0 {
+		if err := doc.SetFont(cfg.Font); err != nil {
+			return nil, fmt.Errorf("invoice font: %w", err)
+		}
+	}
+	l := &documentLayout{cfg: cfg, doc: doc, arabic: doc.HasUnicodeFont()}
+	l.newPage()
+	return l, nil
+}
+
+func (l *documentLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.pages = append(l.pages, l.page)
+	l.y = docTop
+}
+
+// label joins an English label with its Arabic translation when Arabic can be drawn.
+func (l *documentLayout) label(en, ar string) string {
+	if l.arabic && ar != "" {
+		return en + " / " + ar
+	}
+	return en
+}
+
+// fit shortens s with an ellipsis so it fits in width.
+func (l *documentLayout) fit(s string, width float64, bold bool) string {
+	if l.doc.TextWidth(s, docText, bold) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && l.doc.TextWidth(string(runes)+"…", docText, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "…"
+}
+
+// ensure starts a new page unless height more points fit on this one.
+func (l *documentLayout) ensure(height float64) {
+	if l.y+height <= docBottom {
+		return
+	}
+	l.newPage()
+	if l.columns != nil {
+		l.tableHeader(l.columns)
+	}
+}
+
+// heading writes a line of bold text, with the Arabic version right-aligned on the same line.
+func (l *documentLayout) heading(size float64, en, ar string) {
+	l.page.Text(docLeft, l.y+size, size, true, en)
+	if l.arabic && ar != "" {
+		l.page.TextRight(docRight, l.y+size, size, true, ar)
+	}
+	l.y += size + 6
+}
+
+// field writes "label: value" at x on the current line.
+func (l *documentLayout) field(x float64, en, ar, value string) {
+	text := l.label(en, ar) + ":"
+	l.page.Text(x, l.y, docText, true, text)
+	l.page.Text(x+l.doc.TextWidth(text, docText, true)+4, l.y, docText, false, value)
+}
+
+// tableHeader writes the shaded header row of a table and keeps it for continuation pages.
+func (l *documentLayout) tableHeader(columns []docColumn) {
+	l.columns = columns
+	height := docRow
+	if l.arabic {
+		height = 2 * docRow
+	}
+	l.page.FillRect(docLeft, l.y, docRight-docLeft, height, 0.92)
+	for _, col := range columns {
+		l.cell(col, l.y+11, true, col.En)
+		if l.arabic && col.Ar != "" {
+			l.cell(col, l.y+docRow+11, true, col.Ar)
+		}
+	}
+	l.y += height
+}
+
+// row writes one table row.
+func (l *documentLayout) row(values ...string) {
+	l.ensure(docRow)
+	for i, col := range l.columns {
+		if i < len(values) {
+			l.cell(col, l.y+11, false, l.fit(values[i], col.Width, false))
+		}
+	}
+	l.y += docRow
+	l.page.Line(docLeft, l.y, docRight, l.y, 0.3)
+}
+
+func (l *documentLayout) cell(col docColumn, y float64, bold bool, s string) {
+	if col.Right {
+		l.page.TextRight(col.X+col.Width, y, docText, bold, s)
+		return
+	}
+	l.page.Text(col.X, y, docText, bold, s)
+}
+
+// total writes a right-aligned "label  amount" line below a table.
+func (l *documentLayout) total(bold bool, en, ar, amount string) {
+	l.ensure(docRow)
+	label := l.label(en, ar)
+	l.page.TextRight(docRight-90, l.y+11, docText, bold, label)
+	l.page.TextRight(docRight, l.y+11, docText, bold, amount)
+	l.y += docRow
+}
+
+// sellerHeader writes the seller's name, address and tax number.
+func (l *documentLayout) sellerHeader() {
+	l.heading(16, l.cfg.SellerName, l.cfg.SellerNameAr)
+	if l.cfg.SellerAddress != "" {
+		l.page.Text(docLeft, l.y+docText, docText, false, l.cfg.SellerAddress)
+		l.y += docRow
+	}
+	if l.cfg.SellerTaxNumber != "" {
+		l.y += docText
+		l.field(docLeft, "Tax No.", "الرقم الضريبي", l.cfg.SellerTaxNumber)
+		l.y += docRow - docText
+	}
+	l.y += 8
+	l.page.Line(docLeft, l.y, docRight, l.y, 1)
+	l.y += 14
+}
+
+// finish numbers the pages and renders the document.
+func (l *documentLayout) finish(note string) ([]byte, error) {
+	for i, page := range l.pages {
+		if note != "" {
+			page.Text(docLeft, docBottom+30, 8, false, note)
+		}
+		page.TextRight(docRight, docBottom+30, 8, false, fmt.Sprintf("Page %d of %d", i+1, len(l.pages)))
+	}
+	return l.doc.Bytes()
+}
+
+func (l *documentLayout) money(amount domain.Money) string {
+	return amount.String()
+}
+
+func (l *documentLayout) date(t time.Time) string {
+	if l.cfg.Location != nil {
+		t = t.In(l.cfg.Location)
+	}
+	return t.Format("2006-01-02")
+}
+
+// invoiceAddress is the order's shipping address on one line (street, area, city).
+func invoiceAddress(order *domain.SupplierOrder) string {
+	var parts []string
+	for _, key := range []string{"street", "state", "city"} {
+		if v, ok := order.ShippingAddress[key].(string); ok && strings.TrimSpace(v) != "" {
+			parts = append(parts, strings.TrimSpace(v))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// unprintableArabicText returns the first name, address or item text on the invoice with Arabic letters that the
+// configured font cannot draw (any, without INVOICE_FONT_PATH), or "" when there is none.
+func unprintableArabicText(cfg config.InvoiceConfig, partner *domain.Partner, order *domain.SupplierOrder, lines []InvoiceLine) (string, error) {
+	doc := pdf.New()
+	if len(cfg.Font) > 0 {
+		if err := doc.SetFont(cfg.Font); err != nil {
+			return "", fmt.Errorf("invoice font: %w", err)
+		}
+	}
+	texts := []string{partner.Name, order.PartnerOrderID, order.CustomerName, order.CustomerPhone, invoiceAddress(order)}
+	if order.PaymentMethod != nil {
+		texts = append(texts, *order.PaymentMethod)
+	}
+	for _, line := range lines {
+		texts = append(texts, line.SKU, line.Title)
+	}
+	for _, text := range texts {
+		if strings.IndexFunc(text, isArabic) >= 0 && !doc.CanDraw(text) {
+			return text, nil
+		}
+	}
+	return "", nil
+}
+
+func isArabic(r rune) bool {
+	return unicode.Is(unicode.Arabic, r)
+}
+
+// renderInvoicePDF renders a tax invoice for the order's supplier items.
+func renderInvoicePDF(cfg config.InvoiceConfig, invoice *domain.Invoice, partner *domain.Partner, order *domain.SupplierOrder, lines []InvoiceLine) ([]byte, error) {
+	l, err := newDocumentLayout(cfg, "Invoice "+invoice.Number)
+	if err != nil {
+		return nil, err
+	}
+	l.sellerHeader()
+	l.heading(14, "TAX INVOICE", "فاتورة ضريبية")
+	l.y += 6
+
+	left := [][3]string{
+		{"Invoice No.", "رقم الفاتورة", invoice.Number},
+		{"Issue date", "تاريخ الإصدار", l.date(invoice.IssuedAt)},
+		{"Order", "الطلب", order.PartnerOrderID},
+	}
+	if order.ShopifyOrderID != nil && *order.ShopifyOrderID != "" {
+		left = append(left, [3]string{"Shop order", "رقم طلب المتجر", "#" + *order.ShopifyOrderID})
+	}
+	if order.PaymentMethod != nil && *order.PaymentMethod != "" {
+		left = append(left, [3]string{"Payment", "طريقة الدفع", *order.PaymentMethod})
+	}
+	right := [][3]string{
+		{"Bill to", "فاتورة إلى", partner.Name},
+		{"Customer", "العميل", order.CustomerName},
+		{"Phone", "الهاتف", order.CustomerPhone},
+		{"Address", "العنوان", invoiceAddress(order)},
+	}
+	middle := docLeft + (docRight-docLeft)/2
+	rows := len(left)
+	if len(right) > rows {
+		rows = len(right)
+	}
+	for i := 0; i < rows; i++ {
+		l.y += docText
+		if i < len(left) {
+			l.field(docLeft, left[i][0], left[i][1], left[i][2])
+		}
+		if i < len(right) {
+			l.field(middle, right[i][0], right[i][1], l.fit(right[i][2], docRight-middle-110, false))
+		}
+		l.y += docRow - docText
+	}
+	l.y += 12
+
+	l.tableHeader([]docColumn{
+		{En: "#", X: docLeft + 4, Width: 16},
+		{En: "SKU", Ar: "الرمز", X: docLeft + 24, Width: 90},
+		{En: "Description", Ar: "الوصف", X: docLeft + 120, Width: 195},
+		{En: "Qty", Ar: "الكمية", X: docLeft + 320, Width: 40, Right: true},
+		{En: "Unit price", Ar: "سعر الوحدة", X: docLeft + 365, Width: 70, Right: true},
+		{En: "Amount", Ar: "المبلغ", X: docLeft + 440, Width: docRight - docLeft - 444, Right: true},
+	})
+	for i, line := range lines {
+		l.row(fmt.Sprint(i+1), line.SKU, line.Title, fmt.Sprint(line.Quantity), l.money(line.UnitPrice), l.money(line.Amount))
+	}
+	l.columns = nil
+	l.y += 6
+
+	l.total(false, "Total excl. tax", "المجموع قبل الضريبة", l.money(invoice.Subtotal))
+	l.total(false, fmt.Sprintf("Sales tax %s%%", formatPercent(invoice.TaxRate)), "ضريبة المبيعات", l.money(invoice.Tax))
+	l.total(true, "Total "+invoice.Currency, "الإجمالي", l.money(invoice.Total))
+
+	return l.finish(l.label("Prices include sales tax.", "الأسعار شاملة ضريبة المبيعات"))
+}
+
+// renderStatementPDF renders a partner's monthly statement of account.
+func renderStatementPDF(cfg config.InvoiceConfig, statement *Statement) ([]byte, error) {
+	l, err := newDocumentLayout(cfg, "Statement "+statement.Period+" - "+statement.Partner.Name)
+	if err != nil {
+		return nil, err
+	}
+	l.sellerHeader()
+	l.heading(14, "STATEMENT OF ACCOUNT", "كشف حساب")
+	l.y += 6
+
+	l.y += docText
+	l.field(docLeft, "Partner", "الشريك", statement.Partner.Name)
+	l.y += docRow
+	l.field(docLeft, "Period", "الفترة", fmt.Sprintf("%s (%s to %s)", statement.Period, l.date(statement.From), l.date(statement.To.AddDate(0, 0, -1))))
+	l.y += docRow
+	l.field(docLeft, "Currency", "العملة", statement.Currency())
+	l.y += 12
+
+	l.tableHeader([]docColumn{
+		{En: "Date", Ar: "التاريخ", X: docLeft + 4, Width: 56},
+		{En: "Order", Ar: "الطلب", X: docLeft + 64, Width: 100},
+		{En: "Invoice", Ar: "الفاتورة", X: docLeft + 168, Width: 78},
+		{En: "Status", Ar: "الحالة", X: docLeft + 250, Width: 105},
+		{En: "Payment", Ar: "الدفع", X: docLeft + 360, Width: 60},
+		{En: "Amount", Ar: "المبلغ", X: docLeft + 425, Width: docRight - docLeft - 429, Right: true},
+	})
+	for _, line := range statement.Lines {
+		invoiceNumber := "-"
+		if line.Invoice != nil {
+			invoiceNumber = line.Invoice.Number
+		}
+		payment := "Unpaid"
+		if line.Paid() {
+			payment = "Paid"
+		}
+		amount := l.money(line.Amount)
+		if c := line.Amount.Currency(); c != "" && c != statement.Currency() {
+			amount = line.Amount.Display()
+		}
+		if line.Credited() {
+			amount = "(" + amount + ")"
+		}
+		l.row(l.date(line.Order.CreatedAt), line.Order.PartnerOrderID, invoiceNumber, string(line.Order.Status), payment, amount)
+	}
+	if len(statement.Lines) == 0 {
+		l.row("", "No orders this month")
+	}
+	l.columns = nil
+	l.y += 6
+
+	l.total(false, "Orders", "الطلبات", fmt.Sprint(len(statement.Lines)))
+	// One set of totals per currency; only named when there is more than one
+	totals := statement.Totals()
+	for _, t := range totals {
+		amount := l.money
+		if len(totals) > 1 {
+			amount = func(m domain.Money) string { return m.Display() }
+		}
+		l.total(false, "Total", "الإجمالي", amount(t.Total))
+		l.total(false, "Paid", "المدفوع", amount(t.Paid))
+		l.total(true, "Outstanding", "المستحق", amount(t.Outstanding()))
+	}
+
+	return l.finish(l.label("Amounts in parentheses (returned or refunded orders) are not included in the totals.",
+		"المبالغ بين قوسين غير مشمولة في المجموع"))
+}
+
+// formatPercent prints a rate without trailing zeros (16, 7.5).
+func formatPercent(rate float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", rate), "0"), ".")
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// statementPageSize is how many orders are loaded per round trip while building a statement.
+const statementPageSize = 500
+
+// CanInvoice reports whether an order in status can get an invoice: once confirmed, since its items can no
+// longer be amended, and unless it was rejected or canceled.
+func CanInvoice(status domain.OrderStatus) bool {
+	switch status {
+	case domain.OrderStatusIncompleteCaution, domain.OrderStatusRejected, domain.OrderStatusCanceled:
+		return false
+	}
+	return status.IsValid()
+}
+
+// InvoiceLine is one supplier item as invoiced. Prices include tax.
+type InvoiceLine struct {
+	SKU       string
+	Title     string
+	Quantity  int
+	UnitPrice domain.Money
+	Amount    domain.Money
+}
+
+// invoiceLines returns the order's supplier items as invoice lines and their total, in currency.
+func invoiceLines(items []*domain.SupplierOrderItem, currency string) ([]InvoiceLine, domain.Money) {
+	var lines []InvoiceLine
+	total := domain.NewMoney(0, currency)
+	for _, item := range items {
+		if !item.IsSupplierItem {
+			continue
+		}
+		price := item.Price.In(currency)
+		amount := price.Mul(item.Quantity)
+		lines = append(lines, InvoiceLine{
+			SKU:       item.SKU,
+			Title:     item.Title,
+			Quantity:  item.Quantity,
+			UnitPrice: price,
+			Amount:    amount,
+		})
+		total = total.Add(amount)
+	}
+	return lines, total
+}
+
+// invoiceCurrency is the currency the order is invoiced in: the order's, or the configured one for orders
+// without.
+func (s *invoiceService) invoiceCurrency(order *domain.SupplierOrder) string {
+	if order.Currency != "" {
+		return order.Currency
+	}
+	return s.cfg.Currency
+}
+
+type invoiceService struct {
+	cfg    config.InvoiceConfig
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewInvoiceService creates a new invoice and statement service
+func NewInvoiceService(cfg config.InvoiceConfig, repos *repository.Repositories, logger *zap.Logger) *invoiceService {
+	return &invoiceService{
+		cfg:    cfg,
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// GetOrIssue returns the order's invoice, issuing it on first request. Returns ErrConflict when the order cannot
+// be invoiced (not confirmed, rejected or canceled, without supplier items, or with Arabic text the invoice
+// font cannot print).
+func (s *invoiceService) GetOrIssue(ctx context.Context, partner *domain.Partner, order *domain.SupplierOrder) (*domain.Invoice, error) {
+	invoice, err := s.repos.Invoice.GetByOrderID(ctx, order.ID)
+	if err == nil {
+		return invoice, nil
+	}
+	if _, ok := err.(*errors.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	if !CanInvoice(order.Status) {
+		return nil, &errors.ErrConflict{Message: fmt.Sprintf("order cannot be invoiced in status %s", order.Status)}
+	}
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	currency := s.invoiceCurrency(order)
+	lines, total := invoiceLines(items, currency)
+	if len(lines) == 0 {
+		return nil, &errors.ErrConflict{Message: "order has no items to invoice"}
+	}
+
+	// Invoices never change, so one that would print Arabic names as "?" (no font configured) is not issued
+	text, err := unprintableArabicText(s.cfg, partner, order, lines)
+	if err != nil {
+		return nil, err
+	}
+	if text != "" {
+		s.logger.Warn("Invoice not issued: text cannot be printed, set INVOICE_FONT_PATH to a font with Arabic glyphs",
+			zap.String("order_id", order.ID.String()),
+			zap.String("text", text))
+		return nil, &errors.ErrConflict{Message: fmt.Sprintf("order cannot be invoiced: %q cannot be printed with the invoice font", text)}
+	}
+
+	tax := total.Percent(100 * s.cfg.TaxRate / (100 + s.cfg.TaxRate))
+	invoice = &domain.Invoice{
+		SupplierOrderID: order.ID,
+		PartnerID:       order.PartnerID,
+		Currency:        currency,
+		Subtotal:        total.Sub(tax),
+		TaxRate:         s.cfg.TaxRate,
+		Tax:             tax,
+		Total:           total,
+	}
+	err = s.repos.Invoice.Issue(ctx, invoice, func(invoice *domain.Invoice) error {
+		invoice.Number = fmt.Sprintf("%s%06d", s.cfg.NumberPrefix, invoice.Sequence)
+		pdf, err := renderInvoicePDF(s.cfg, invoice, partner, order, lines)
+		if err != nil {
+			return err
+		}
+		invoice.PDF = pdf
+		return nil
+	})
+	if err != nil {
+		// Issued meanwhile by a concurrent request
+		if _, ok := err.(*errors.ErrConflict); ok {
+			return s.repos.Invoice.GetByOrderID(ctx, order.ID)
+		}
+		return nil, err
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventInvoiceIssued,
+		EventData: map[string]interface{}{
+			"invoice_number": invoice.Number,
+			"total":          invoice.Total,
+			"currency":       invoice.Currency,
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	s.logger.Info("Invoice issued",
+		zap.String("order_id", order.ID.String()),
+		zap.String("invoice_number", invoice.Number),
+		zap.Stringer("total", invoice.Total),
+	)
+	return invoice, nil
+}
+
+// StatementLine is one order on a partner statement. Amount is the invoice total, or the total of the order's
+// supplier items while it has no invoice.
+type StatementLine struct {
+	Order   *domain.SupplierOrder
+	Invoice *domain.Invoice // nil until issued
+	Amount  domain.Money
+}
+
+// Paid reports whether the order's payment has been collected or recorded.
+func (l *StatementLine) Paid() bool {
+	return l.Order.PaymentStatus == domain.PaymentStatusPaid
+}
+
+// Credited reports whether the order was returned or refunded, so nothing is owed for it.
+func (l *StatementLine) Credited() bool {
+	return l.Order.Status == domain.OrderStatusReturned || l.Order.Status == domain.OrderStatusRefunded
+}
+
+// Statement is a partner's statement of account for one month: every order placed in the month except rejected
+// and canceled ones, oldest first.
+type Statement struct {
+	Partner *domain.Partner
+	Period  string // YYYY-MM
+	From    time.Time
+	To      time.Time // exclusive
+	Lines   []StatementLine
+}
+
+// Currency is the partner's currency, which the statement amounts are in unless an order was placed in another
+// one (e.g. before the partner's currency changed).
+func (s *Statement) Currency() string {
+	if s.Partner.Currency != "" {
+		return s.Partner.Currency
+	}
+	return domain.DefaultCurrency
+}
+
+// StatementTotals are a statement's totals in one currency.
+type StatementTotals struct {
+	Currency string
+	Total    domain.Money // the orders, less returned or refunded ones
+	Paid     domain.Money // the part of Total already paid
+}
+
+// Outstanding is the part of Total still to be paid.
+func (t *StatementTotals) Outstanding() domain.Money {
+	return t.Total.Sub(t.Paid)
+}
+
+// Totals adds up the lines per currency, since amounts in different currencies cannot be added: the partner's
+// currency first (also when there are no orders), then any other currency orders were placed in, by code.
+func (s *Statement) Totals() []*StatementTotals {
+	totals := []*StatementTotals{{
+		Currency: s.Currency(),
+		Total:    domain.NewMoney(0, s.Currency()),
+		Paid:     domain.NewMoney(0, s.Currency()),
+	}}
+	byCurrency := map[string]*StatementTotals{s.Currency(): totals[0]}
+	for _, line := range s.Lines {
+		if line.Credited() {
+			continue
+		}
+		currency := line.Amount.Currency()
+		if currency == "" {
+			currency = s.Currency()
+		}
+		t, ok := byCurrency[currency]
+		if !ok {
+			t = &StatementTotals{Currency: currency, Total: domain.NewMoney(0, currency), Paid: domain.NewMoney(0, currency)}
+			byCurrency[currency] = t
+			totals = append(totals, t)
+		}
+		t.Total = t.Total.Add(line.Amount)
+		if line.Paid() {
+			t.Paid = t.Paid.Add(line.Amount)
+		}
+	}
+	sort.Slice(totals[1:], func(i, j int) bool { return totals[1+i].Currency < totals[1+j].Currency })
+	return totals
+}
+
+// GetStatement builds the partner's statement for orders created in [from, to).
+func (s *invoiceService) GetStatement(ctx context.Context, partner *domain.Partner, from, to time.Time) (*Statement, error) {
+	statuses := make([]domain.OrderStatus, 0, len(domain.OrderStatuses))
+	for _, status := range domain.OrderStatuses {
+		if status != domain.OrderStatusRejected && status != domain.OrderStatusCanceled {
+			statuses = append(statuses, status)
+		}
+	}
+	filter := repository.OrderListFilter{
+		PartnerID:   &partner.ID,
+		Statuses:    statuses,
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Limit:       statementPageSize,
+		SkipTotal:   true,
+	}
+
+	statement := &Statement{
+		Partner: partner,
+		Period:  from.Format("2006-01"),
+		From:    from,
+		To:      to,
+	}
+	for {
+		page, err := s.repos.SupplierOrder.ListOrders(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		orderIDs := make([]uuid.UUID, len(page.Orders))
+		for i, order := range page.Orders {
+			orderIDs[i] = order.ID
+		}
+		itemsByOrder, err := s.repos.SupplierOrderItem.GetByOrderIDs(ctx, orderIDs)
+		if err != nil {
+			return nil, err
+		}
+		invoices, err := s.repos.Invoice.ListByOrderIDs(ctx, orderIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, order := range page.Orders {
+			line := StatementLine{Order: order, Invoice: invoices[order.ID]}
+			if line.Invoice != nil {
+				line.Amount = line.Invoice.Total.In(line.Invoice.Currency)
+			} else {
+				_, line.Amount = invoiceLines(itemsByOrder[order.ID], s.invoiceCurrency(order))
+			}
+			statement.Lines = append(statement.Lines, line)
+		}
+
+		if page.Next == nil {
+			break
+		}
+		filter.After = page.Next
+	}
+
+	// Listed newest first; statements read oldest first
+	for i, j := 0, len(statement.Lines)-1; i < j; i, j = i+1, j-1 {
+		statement.Lines[i], statement.Lines[j] = statement.Lines[j], statement.Lines[i]
+	}
+	return statement, nil
+}
+
+// RenderStatement renders the statement as a PDF.
+func (s *invoiceService) RenderStatement(statement *Statement) ([]byte, error) {
+	return renderStatementPDF(s.cfg, statement)
+}
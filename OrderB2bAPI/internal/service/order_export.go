@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// exportPageSize is how many orders are loaded (with their items) per round trip while exporting.
+const exportPageSize = 500
+
+// OrderExportColumns is the header of an order export; ExportOrders emits rows in this column order.
+var OrderExportColumns = []string{
+	"order_id",
+	"partner_order_id",
+	"shopify_order_id",
+	"status",
+	"created_at",
+	"customer_name",
+	"customer_phone",
+	"city",
+	"area",
+	"address",
+	"payment_method",
+	"payment_status",
+	"cart_total",
+	"sku",
+	"title",
+	"price",
+	"quantity",
+	"fulfilled_quantity",
+	"tracking_carrier",
+	"tracking_number",
+	"tracking_url",
+	"last_delivery_status",
+	"last_delivery_status_label",
+	"last_delivery_at",
+}
+
+// ExportOrders calls writeRow for every order matching filter, newest first: one row per item, or a single row
+// with empty item columns for an order without items. Orders are loaded a page at a time so memory stays flat;
+// flush (optional) is called after each page. filter.After and filter.Limit are ignored.
+func (s *orderService) ExportOrders(ctx context.Context, filter repository.OrderListFilter, writeRow func([]interface{}) error, flush func() error) error {
+	filter.After = nil
+	filter.Limit = exportPageSize
+	filter.SkipTotal = true
+
+	for {
+		page, err := s.repos.SupplierOrder.ListOrders(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		orderIDs := make([]uuid.UUID, len(page.Orders))
+		for i, order := range page.Orders {
+			orderIDs[i] = order.ID
+		}
+		itemsByOrder, err := s.repos.SupplierOrderItem.GetByOrderIDs(ctx, orderIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, order := range page.Orders {
+			items := itemsByOrder[order.ID]
+			if len(items) == 0 {
+				if err := writeRow(orderExportRow(order, nil)); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, item := range items {
+				if err := writeRow(orderExportRow(order, item)); err != nil {
+					return err
+				}
+			}
+		}
+		if flush != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		if page.Next == nil {
+			return nil
+		}
+		filter.After = page.Next
+	}
+}
+
+func orderExportRow(order *domain.SupplierOrder, item *domain.SupplierOrderItem) []interface{} {
+	addressField := func(key string) string {
+		if v, ok := order.ShippingAddress[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+	var lastDeliveryAt interface{}
+	if order.LastDeliveryAt != nil {
+		lastDeliveryAt = order.LastDeliveryAt.Format(time.RFC3339)
+	}
+
+	row := []interface{}{
+		order.ID.String(),
+		order.PartnerOrderID,
+		order.ShopifyOrderID,
+		string(order.Status),
+		order.CreatedAt.Format(time.RFC3339),
+		order.CustomerName,
+		order.CustomerPhone,
+		addressField("city"),
+		addressField("state"),
+		addressField("street"),
+		order.PaymentMethod,
+		order.PaymentStatus,
+		order.CartTotal,
+	}
+	if item != nil {
+		row = append(row, item.SKU, item.Title, item.Price, item.Quantity, item.FulfilledQuantity)
+	} else {
+		row = append(row, nil, nil, nil, nil, nil)
+	}
+	return append(row,
+		order.TrackingCarrier,
+		order.TrackingNumber,
+		order.TrackingURL,
+		order.LastDeliveryStatus,
+		order.LastDeliveryStatusLabel,
+		lastDeliveryAt,
+	)
+}
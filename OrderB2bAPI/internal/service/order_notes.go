@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// shopifyNoteMaxLen caps the note written to Shopify; the oldest messages are dropped first.
+const shopifyNoteMaxLen = 5000
+
+// AddNote stores a note on the order and records a note_added event. The body is trimmed and must not be empty.
+func (s *orderService) AddNote(ctx context.Context, order *domain.SupplierOrder, note *domain.OrderNote) error {
+	note.Body = strings.TrimSpace(note.Body)
+	if note.Body == "" {
+		return &errors.ErrValidation{Message: "note body is required"}
+	}
+	note.SupplierOrderID = order.ID
+
+	if err := s.repos.OrderNote.Create(ctx, note); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"note_id":     note.ID.String(),
+		"author_type": note.AuthorType,
+	}
+	if note.StaffID != nil {
+		data["staff_id"] = note.StaffID.String()
+	}
+	s.repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventNoteAdded,
+		EventData:       data,
+	})
+	return nil
+}
+
+// SyncOrderNotes writes the whole note thread into the Shopify order note, or the draft order note when the draft
+// was never completed. The note keeps the partner order ID on its first line, as set at draft creation.
+// Returns what was updated in Shopify ("order_note_updated", "draft_note_updated" or "none").
+func (s *shopifyService) SyncOrderNotes(ctx context.Context, order *domain.SupplierOrder, notes []*domain.OrderNote) (string, error) {
+	note := buildShopifyOrderNote(order.PartnerOrderID, notes)
+
+	if order.ShopifyOrderID != nil && *order.ShopifyOrderID != "" {
+		orderGID, err := s.GetOrderGIDByName(ctx, *order.ShopifyOrderID)
+		if err != nil {
+			return "order_note_updated", fmt.Errorf("resolve order by name: %w", err)
+		}
+		return "order_note_updated", s.executeMutation(shopify.OrderUpdateMutation, "orderUpdate", map[string]interface{}{
+			"input": map[string]interface{}{
+				"id":   orderGID,
+				"note": note,
+			},
+		})
+	}
+	if order.ShopifyDraftOrderID != nil {
+		return "draft_note_updated", s.executeMutation(shopify.DraftOrderUpdateMutation, "draftOrderUpdate", map[string]interface{}{
+			"id":    fmt.Sprintf("gid://shopify/DraftOrder/%d", *order.ShopifyDraftOrderID),
+			"input": map[string]interface{}{"note": note},
+		})
+	}
+	return "none", nil
+}
+
+// buildShopifyOrderNote renders the partner order ID followed by one line per note, oldest first.
+func buildShopifyOrderNote(partnerOrderID string, notes []*domain.OrderNote) string {
+	lines := make([]string, len(notes))
+	for i, n := range notes {
+		author := "Partner"
+		if n.AuthorType == domain.NoteAuthorStaff {
+			author = "Supplier"
+		}
+		lines[i] = fmt.Sprintf("[%s %s] %s", n.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"), author, n.Body)
+	}
+
+	header := partnerOrderID + "\n\nNotes:\n"
+	for len(lines) > 1 && len(header)+len(strings.Join(lines, "\n")) > shopifyNoteMaxLen {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return partnerOrderID
+	}
+	return header + strings.Join(lines, "\n")
+}
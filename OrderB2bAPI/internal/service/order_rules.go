@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+	"github.com/jafarshop/b2bapi/pkg/gazetteer"
+)
+
+// defaultOrderRulePriority is the priority of a rule created without one.
+const defaultOrderRulePriority = 100
+
+// OrderFacts are what auto-confirmation rules look at in an order.
+type OrderFacts struct {
+	CartTotal     domain.Money `json:"cart_total"`
+	CityCode      string       `json:"city_code,omitempty"`
+	ItemCount     int          `json:"item_count"` // total quantity
+	PaymentMethod string       `json:"payment_method,omitempty"`
+	Delivered     int          `json:"delivered"` // customer's earlier delivered orders
+	Returned      int          `json:"returned"`  // customer's earlier orders returned to shipper (RTO)
+}
+
+// MatchOrderRule returns the first rule (rules are in priority order) whose conditions all hold for facts, or nil.
+func MatchOrderRule(rules []*domain.PartnerOrderRule, facts *OrderFacts) *domain.PartnerOrderRule {
+	for _, rule := range rules {
+		if orderRuleMatches(rule, facts) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func orderRuleMatches(rule *domain.PartnerOrderRule, facts *OrderFacts) bool {
+	switch {
+	case rule.MinCartTotal != nil && facts.CartTotal.Cmp(*rule.MinCartTotal) < 0,
+		rule.MaxCartTotal != nil && facts.CartTotal.Cmp(*rule.MaxCartTotal) > 0,
+		rule.MinItemCount != nil && facts.ItemCount < *rule.MinItemCount,
+		rule.MaxItemCount != nil && facts.ItemCount > *rule.MaxItemCount,
+		rule.MinDelivered != nil && facts.Delivered < *rule.MinDelivered,
+		rule.MaxReturned != nil && facts.Returned > *rule.MaxReturned:
+		return false
+	}
+	if len(rule.CityCodes) > 0 && !containsFold(rule.CityCodes, facts.CityCode) {
+		return false
+	}
+	if len(rule.PaymentMethods) > 0 && !containsFold(rule.PaymentMethods, facts.PaymentMethod) {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether value (non-empty) is one of values, ignoring case.
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseOrderRule validates an auto-confirmation rule for partnerID. Cities are resolved to gazetteer city codes.
+// Errors are *errors.ErrValidation keyed by field (prefix is put before each field name, e.g. "rules[0].").
+func ParseOrderRule(req *OrderRuleRequest, partnerID uuid.UUID, prefix string) (*domain.PartnerOrderRule, error) {
+	fields := make(map[string]string)
+	rule := &domain.PartnerOrderRule{
+		PartnerID:    partnerID,
+		Name:         strings.TrimSpace(req.Name),
+		Action:       domain.OrderRuleAction(strings.ToLower(strings.TrimSpace(req.Action))),
+		Priority:     defaultOrderRulePriority,
+		MinItemCount: req.MinItemCount,
+		MaxItemCount: req.MaxItemCount,
+		MinDelivered: req.MinDelivered,
+		MaxReturned:  req.MaxReturned,
+	}
+
+	if rule.Name == "" {
+		fields[prefix+"name"] = "is required"
+	} else if len([]rune(rule.Name)) > 255 {
+		fields[prefix+"name"] = "must be at most 255 characters"
+	}
+	if !rule.Action.IsValid() {
+		fields[prefix+"action"] = "must be confirm or hold"
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+
+	for field, amount := range map[string]*domain.Money{"min_cart_total": req.MinCartTotal, "max_cart_total": req.MaxCartTotal} {
+		if amount != nil && amount.IsNegative() {
+			fields[prefix+field] = "must not be negative"
+		}
+	}
+	if req.MinCartTotal != nil {
+		minTotal := req.MinCartTotal.Round()
+		rule.MinCartTotal = &minTotal
+	}
+	if req.MaxCartTotal != nil {
+		maxTotal := req.MaxCartTotal.Round()
+		rule.MaxCartTotal = &maxTotal
+	}
+	if rule.MinCartTotal != nil && rule.MaxCartTotal != nil && rule.MaxCartTotal.Cmp(*rule.MinCartTotal) < 0 {
+		fields[prefix+"max_cart_total"] = "must not be less than min_cart_total"
+	}
+
+	for field, n := range map[string]*int{
+		"min_item_count": req.MinItemCount,
+		"max_item_count": req.MaxItemCount,
+		"min_delivered":  req.MinDelivered,
+		"max_returned":   req.MaxReturned,
+	} {
+		if n != nil && *n < 0 {
+			fields[prefix+field] = "must not be negative"
+		}
+	}
+	if req.MinItemCount != nil && req.MaxItemCount != nil && *req.MaxItemCount < *req.MinItemCount {
+		fields[prefix+"max_item_count"] = "must not be less than min_item_count"
+	}
+
+	for i, name := range req.Cities {
+		city := gazetteer.CityByCode(strings.ToLower(strings.TrimSpace(name)))
+		if city == nil {
+			city, _, _ = gazetteer.FindCity(name)
+		}
+		if city == nil {
+			fields[fmt.Sprintf("%scities[%d]", prefix, i)] = "must be a city in Jordan"
+			continue
+		}
+		if !containsFold(rule.CityCodes, city.Code) {
+			rule.CityCodes = append(rule.CityCodes, city.Code)
+		}
+	}
+	for i, method := range req.PaymentMethods {
+		method = strings.TrimSpace(method)
+		if method == "" {
+			fields[fmt.Sprintf("%spayment_methods[%d]", prefix, i)] = "must not be empty"
+			continue
+		}
+		rule.PaymentMethods = append(rule.PaymentMethods, method)
+	}
+
+	if len(fields) > 0 {
+		return nil, &errors.ErrValidation{Message: "invalid order rule", Fields: fields}
+	}
+	return rule, nil
+}
+
+// CollectOrderFacts collects what rules look at in order. The customer history counts orders placed before it.
+func (s *orderService) CollectOrderFacts(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) (*OrderFacts, error) {
+	facts := &OrderFacts{CartTotal: order.CartTotal}
+	if code, ok := order.ShippingAddress["city_code"].(string); ok {
+		facts.CityCode = code
+	}
+	for _, item := range items {
+		facts.ItemCount += item.Quantity
+	}
+	if order.PaymentMethod != nil {
+		facts.PaymentMethod = strings.TrimSpace(*order.PaymentMethod)
+	}
+	if order.CustomerPhone != "" {
+		history, err := s.repos.SupplierOrder.CustomerHistory(ctx, order.CustomerPhone, order.CreatedAt, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		facts.Delivered = history.Delivered
+		facts.Returned = history.Returned
+	}
+	return facts, nil
+}
+
+// ApplyOrderRules checks a new order against the partner's auto-confirmation rules. The first matching rule
+// confirms the order (UNFULFILLED) or holds it with a hold reason naming the rule, and a rule_matched event records
+// the rule and the facts it saw. Orders that are no longer INCOMPLETE_CAUTION or are already held (stock, credit,
+// possible duplicate) are left for staff. Returns the rule that fired, or nil.
+func (s *orderService) ApplyOrderRules(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) (*domain.PartnerOrderRule, error) {
+	if order.Status != domain.OrderStatusIncompleteCaution || order.HoldReason != nil {
+		return nil, nil
+	}
+	rules, err := s.repos.PartnerOrderRule.ListByPartnerID(ctx, order.PartnerID)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	facts, err := s.CollectOrderFacts(ctx, order, items)
+	if err != nil {
+		return nil, err
+	}
+	rule := MatchOrderRule(rules, facts)
+	if rule == nil {
+		return nil, nil
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventRuleMatched,
+		EventData: map[string]interface{}{
+			"rule_id":        rule.ID.String(),
+			"rule_name":      rule.Name,
+			"action":         string(rule.Action),
+			"cart_total":     facts.CartTotal.String(),
+			"city_code":      facts.CityCode,
+			"item_count":     facts.ItemCount,
+			"payment_method": facts.PaymentMethod,
+			"delivered":      facts.Delivered,
+			"returned":       facts.Returned,
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	switch rule.Action {
+	case domain.OrderRuleActionConfirm:
+		err = s.ChangeStatus(ctx, order, domain.OrderStatusUnfulfilled, StatusChange{
+			Source: "order_rule",
+			Data:   map[string]interface{}{"rule_id": rule.ID.String(), "rule_name": rule.Name},
+		})
+	case domain.OrderRuleActionHold:
+		holdReason := fmt.Sprintf("Held by rule %q", rule.Name)
+		if err = s.repos.SupplierOrder.UpdateHoldReason(ctx, order.ID, &holdReason); err == nil {
+			order.HoldReason = &holdReason
+		}
+	}
+	return rule, err
+}
+
+// OrderRuleDryRunResult is one past order checked in a dry run.
+type OrderRuleDryRunResult struct {
+	SupplierOrderID string                 `json:"supplier_order_id"`
+	PartnerOrderID  string                 `json:"partner_order_id"`
+	CreatedAt       string                 `json:"created_at"`
+	Status          domain.OrderStatus     `json:"status"`
+	Facts           *OrderFacts            `json:"facts"`
+	RuleID          *uuid.UUID             `json:"rule_id,omitempty"`
+	RuleName        string                 `json:"rule_name,omitempty"`
+	Action          domain.OrderRuleAction `json:"action,omitempty"` // empty when no rule matched (left for staff)
+}
+
+// OrderRuleDryRun is the outcome of checking rules against a partner's past orders; nothing is changed.
+type OrderRuleDryRun struct {
+	Orders    []OrderRuleDryRunResult `json:"orders"`
+	Checked   int                     `json:"checked"`
+	Confirmed int                     `json:"confirmed"`
+	Held      int                     `json:"held"`
+	Unmatched int                     `json:"unmatched"`
+	// Truncated is set when more orders were placed in the period than were checked
+	Truncated bool `json:"truncated"`
+	// Matches counts the orders each rule fired for, by rule ID (or name for unsaved rules)
+	Matches map[string]int `json:"matches"`
+}
+
+// DryRunOrderRules checks rules against the partner's orders created in [from, to), newest first, up to limit
+// orders. Customer history is counted as of each order's creation, by the outcome of the earlier orders today.
+func (s *orderService) DryRunOrderRules(ctx context.Context, partnerID uuid.UUID, rules []*domain.PartnerOrderRule, from, to time.Time, limit int) (*OrderRuleDryRun, error) {
+	page, err := s.repos.SupplierOrder.ListOrders(ctx, repository.OrderListFilter{
+		PartnerID:   &partnerID,
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Limit:       limit,
+		SkipTotal:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	orderIDs := make([]uuid.UUID, len(page.Orders))
+	for i, order := range page.Orders {
+		orderIDs[i] = order.ID
+	}
+	itemsByOrder, err := s.repos.SupplierOrderItem.GetByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OrderRuleDryRun{
+		Orders:    make([]OrderRuleDryRunResult, 0, len(page.Orders)),
+		Truncated: page.Next != nil,
+		Matches:   make(map[string]int),
+	}
+	for _, order := range page.Orders {
+		facts, err := s.CollectOrderFacts(ctx, order, itemsByOrder[order.ID])
+		if err != nil {
+			return nil, err
+		}
+		entry := OrderRuleDryRunResult{
+			SupplierOrderID: order.ID.String(),
+			PartnerOrderID:  order.PartnerOrderID,
+			CreatedAt:       order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Status:          order.Status,
+			Facts:           facts,
+		}
+		result.Checked++
+		rule := MatchOrderRule(rules, facts)
+		switch {
+		case rule == nil:
+			result.Unmatched++
+		case rule.Action == domain.OrderRuleActionConfirm:
+			result.Confirmed++
+		default:
+			result.Held++
+		}
+		if rule != nil {
+			entry.RuleName = rule.Name
+			entry.Action = rule.Action
+			key := rule.Name
+			if rule.ID != uuid.Nil {
+				entry.RuleID = &rule.ID
+				key = rule.ID.String()
+			}
+			result.Matches[key]++
+		}
+		result.Orders = append(result.Orders, entry)
+	}
+	return result, nil
+}
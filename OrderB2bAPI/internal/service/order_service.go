@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/google/uuid"
@@ -27,12 +29,20 @@ func NewOrderService(repos *repository.Repositories, logger *zap.Logger) *orderS
 
 // CreateOrderFromCart creates a supplier order from a cart submission.
 // supplierItems must be partner-scoped (from partner_sku_mappings) so only this partner's SKUs are accepted.
+// deliveryWindow is req.DeliveryWindow already checked by ParseDeliveryWindow (nil when none was requested).
+// priceList is the partner's price list on the order date (nil when it has none). Amounts are in the partner's
+// currency (see CheckCartCurrency).
+// When the partner has a credit limit the order total is checked against it as the order is created: over the limit,
+// the reject policy returns *errors.ErrCreditLimitExceeded and nothing is created, the hold policy returns the
+// order with a CreditCheck to pass to RecordCreditCheck.
 func (s *orderService) CreateOrderFromCart(
 	ctx context.Context,
-	partnerID uuid.UUID,
+	partner *domain.Partner,
 	req CartSubmitRequest,
 	supplierItems map[string]*domain.PartnerSKUMapping,
-) (*domain.SupplierOrder, error) {
+	priceList *PriceList,
+	deliveryWindow *domain.DeliveryWindow,
+) (*domain.SupplierOrder, *CreditCheck, error) {
 	// Build customer name from Zain format: first_name + last_name
 	customerName := strings.TrimSpace(req.Customer.FirstName + " " + req.Customer.LastName)
 	if customerName == "" {
@@ -41,83 +51,165 @@ func (s *orderService) CreateOrderFromCart(
 
 	// Create order
 	order := &domain.SupplierOrder{
-		PartnerID:      partnerID,
+		PartnerID:      partner.ID,
 		PartnerOrderID: req.PartnerOrderID,
 		Status:         domain.OrderStatusIncompleteCaution,
 		CustomerName:   customerName,
 		CustomerPhone:  req.Customer.Phone,
-		CartTotal:      req.Totals.Total,
-		PaymentStatus:  "Payment pending",
+		CartTotal:      req.Totals.Total.In(partner.Currency),
+		Currency:       partner.Currency,
+		PaymentStatus:  domain.PaymentStatusPending,
 		PaymentMethod:  req.PaymentMethod,
+		DeliveryWindow: deliveryWindow,
 	}
 
-	// Map Zain shipping fields to internal map (street = address, area = state, country default Jordan)
-	country := req.Shipping.Country
+	order.ShippingAddress = BuildShippingAddress(req.Shipping, req.Customer.Email)
+
+	// Create order in database (with the credit check, under a lock on the partner, when it has a limit)
+	s.logger.Info("Creating supplier order in database", zap.String("partner_order_id", req.PartnerOrderID))
+	var creditCheck *CreditCheck
+	var err error
+	if partner.CreditLimit != nil {
+		err = s.repos.SupplierOrder.CreateWithinCredit(ctx, order, creditExposureStatuses, func(exposure *repository.CreditExposure) error {
+			var checkErr error
+			creditCheck, checkErr = checkCartCredit(partner, exposure, order.CartTotal)
+			return checkErr
+		})
+	} else {
+		err = s.repos.SupplierOrder.Create(ctx, order)
+	}
+	if err != nil {
+		if _, ok := err.(*errors.ErrCreditLimitExceeded); !ok {
+			s.logger.Error("Failed to create supplier order in database", zap.Error(err))
+		}
+		return nil, nil, err
+	}
+
+	// Create order items
+	s.logger.Info("Creating order items", zap.Int("item_count", len(req.Items)))
+	items := BuildOrderItems(order.ID, req.Items, supplierItems, priceList)
+
+	// Create items in batch
+	s.logger.Info("Inserting order items into database", zap.Int("item_count", len(items)))
+	if err := s.repos.SupplierOrderItem.CreateBatch(ctx, items); err != nil {
+		s.logger.Error("Failed to create order items in database", zap.Error(err))
+		return nil, nil, err
+	}
+	s.logger.Info("Order items created successfully")
+
+	// Log order creation event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventCreated,
+		EventData: map[string]interface{}{
+			"partner_order_id": req.PartnerOrderID,
+			"status":           order.Status,
+		},
+	}
+	if deliveryWindow != nil {
+		event.EventData["delivery_date"] = deliveryWindow.DateString()
+		if r := deliveryWindow.TimeRange(); r != "" {
+			event.EventData["delivery_window"] = r
+		}
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	return order, creditCheck, nil
+}
+
+// BuildShippingAddress maps Zain shipping fields to the internal shipping_address map
+// (street = address, area = state, country default Jordan). email is stored alongside when set, as are the
+// gazetteer codes set by NormalizeShippingAddress (city_code, area_code, governorate_code).
+func BuildShippingAddress(shipping ShippingAddress, email string) map[string]interface{} {
+	country := shipping.Country
 	if country == "" {
 		country = "Jordan"
 	}
-	order.ShippingAddress = map[string]interface{}{
-		"street":      req.Shipping.Address,
-		"city":        req.Shipping.City,
-		"postal_code": req.Shipping.PostalCode,
+	address := map[string]interface{}{
+		"street":      shipping.Address,
+		"city":        shipping.City,
+		"postal_code": shipping.PostalCode,
 		"country":     country,
 	}
-	if req.Shipping.Area != "" {
-		order.ShippingAddress["state"] = req.Shipping.Area
+	if shipping.Area != "" {
+		address["state"] = shipping.Area
 	}
-	if req.Customer.Email != "" {
-		order.ShippingAddress["email"] = req.Customer.Email
+	if shipping.CityCode != "" {
+		address["city_code"] = shipping.CityCode
+		address["governorate_code"] = shipping.GovernorateCode
 	}
-
-	// Create order in database
-	s.logger.Info("Creating supplier order in database", zap.String("partner_order_id", req.PartnerOrderID))
-	if err := s.repos.SupplierOrder.Create(ctx, order); err != nil {
-		s.logger.Error("Failed to create supplier order in database", zap.Error(err))
-		return nil, err
+	if shipping.AreaCode != "" {
+		address["area_code"] = shipping.AreaCode
+	}
+	if email != "" {
+		address["email"] = email
 	}
+	return address
+}
 
-	// Create order items
-	s.logger.Info("Creating order items", zap.Int("item_count", len(req.Items)))
-	items := make([]*domain.SupplierOrderItem, 0, len(req.Items))
-	for _, cartItem := range req.Items {
+// BuildOrderItems converts cart items to order items, flagging those found in supplierItems (partner-scoped mappings).
+// Supplier items priced by priceList (may be nil) keep their Shopify and agreed prices for the Shopify order.
+func BuildOrderItems(
+	orderID uuid.UUID,
+	cartItems []CartItem,
+	supplierItems map[string]*domain.PartnerSKUMapping,
+	priceList *PriceList,
+) []*domain.SupplierOrderItem {
+	items := make([]*domain.SupplierOrderItem, 0, len(cartItems))
+	for _, cartItem := range cartItems {
 		item := &domain.SupplierOrderItem{
-			SupplierOrderID: order.ID,
+			SupplierOrderID: orderID,
 			SKU:             cartItem.SKU,
 			Title:           cartItem.Title,
 			Price:           cartItem.Price,
 			Quantity:        cartItem.Quantity,
 			ProductURL:      cartItem.ProductURL,
 		}
-
 		if mapping, ok := supplierItems[cartItem.SKU]; ok {
 			item.IsSupplierItem = true
 			item.ShopifyVariantID = &mapping.ShopifyVariantID
-			s.logger.Debug("Item is supplier item", zap.String("sku", cartItem.SKU))
+			if wholesalePrice, ok := priceList.AgreedPrice(mapping); ok {
+				item.WholesalePrice = &wholesalePrice
+				if listPrice, ok := syncedPrice(mapping); ok {
+					item.ListPrice = &listPrice
+				}
+			}
 		}
-
 		items = append(items, item)
 	}
+	return items
+}
 
-	// Create items in batch
-	s.logger.Info("Inserting order items into database", zap.Int("item_count", len(items)))
-	if err := s.repos.SupplierOrderItem.CreateBatch(ctx, items); err != nil {
-		s.logger.Error("Failed to create order items in database", zap.Error(err))
-		return nil, err
+// CartItemsFromOrderItems returns an order's stored items as cart items, e.g. to check new totals against them.
+func CartItemsFromOrderItems(items []*domain.SupplierOrderItem) []CartItem {
+	cartItems := make([]CartItem, len(items))
+	for i, item := range items {
+		cartItems[i] = CartItem{
+			SKU:        item.SKU,
+			Title:      item.Title,
+			Price:      item.Price,
+			Quantity:   item.Quantity,
+			ProductURL: item.ProductURL,
+		}
 	}
-	s.logger.Info("Order items created successfully")
+	return cartItems
+}
 
-	// Log order creation event
-	event := &domain.OrderEvent{
-		SupplierOrderID: order.ID,
-		EventType:       "order_created",
-		EventData: map[string]interface{}{
-			"partner_order_id": req.PartnerOrderID,
-			"status":           order.Status,
-		},
+// AddedCartItems returns cartItems with the quantity already on the order (per SKU) taken off, so each line has
+// only what an amendment adds (0 when nothing).
+func AddedCartItems(cartItems []CartItem, current []*domain.SupplierOrderItem) []CartItem {
+	onOrder := make(map[string]int)
+	for _, item := range current {
+		onOrder[item.SKU] += item.Quantity
 	}
-	s.repos.OrderEvent.Create(ctx, event)
-
-	return order, nil
+	added := make([]CartItem, len(cartItems))
+	for i, item := range cartItems {
+		taken := min(item.Quantity, onOrder[item.SKU])
+		onOrder[item.SKU] -= taken
+		item.Quantity -= taken
+		added[i] = item
+	}
+	return added
 }
 
 // ConfirmOrder confirms an order (idempotent: already confirmed returns success)
@@ -128,34 +220,17 @@ func (s *orderService) ConfirmOrder(ctx context.Context, orderID uuid.UUID) erro
 	}
 
 	// Already confirmed/unfulfilled - idempotent success
-	if order.Status == domain.OrderStatusUnfulfilled || order.Status == domain.OrderStatusConfirmed {
+	if order.Status == domain.OrderStatusUnfulfilled {
 		return nil
 	}
 
-	// Validate state transition
-	if !order.Status.CanTransitionTo(domain.OrderStatusUnfulfilled) {
-		return &errors.ErrInvalidStateTransition{
-			From: order.Status,
-			To:   domain.OrderStatusUnfulfilled,
-		}
-	}
-
-	// Update status
-	if err := s.repos.SupplierOrder.UpdateStatus(ctx, orderID, domain.OrderStatusUnfulfilled, nil); err != nil {
+	if err := s.ChangeStatus(ctx, order, domain.OrderStatusUnfulfilled, StatusChange{}); err != nil {
 		return err
 	}
-
-	// Log event
-	event := &domain.OrderEvent{
-		SupplierOrderID: orderID,
-		EventType:       "status_change",
-		EventData: map[string]interface{}{
-			"from": order.Status,
-			"to":   domain.OrderStatusUnfulfilled,
-		},
+	// Confirming resolves a hold (the stock_shortage event keeps the reason)
+	if order.HoldReason != nil {
+		return s.repos.SupplierOrder.UpdateHoldReason(ctx, order.ID, nil)
 	}
-	s.repos.OrderEvent.Create(ctx, event)
-
 	return nil
 }
 
@@ -171,32 +246,33 @@ func (s *orderService) RejectOrder(ctx context.Context, orderID uuid.UUID, reaso
 		return nil
 	}
 
-	// Validate state transition
-	if !order.Status.CanTransitionTo(domain.OrderStatusRejected) {
-		return &errors.ErrInvalidStateTransition{
-			From: order.Status,
-			To:   domain.OrderStatusRejected,
-		}
-	}
+	return s.ChangeStatus(ctx, order, domain.OrderStatusRejected, StatusChange{
+		RejectionReason: &reason,
+		Data:            map[string]interface{}{"reason": reason},
+	})
+}
 
-	// Update status
-	if err := s.repos.SupplierOrder.UpdateStatus(ctx, orderID, domain.OrderStatusRejected, &reason); err != nil {
+// CancelOrder cancels an order on the partner's request (idempotent: already canceled returns success).
+// Shopify must already have been updated by the caller; shopifyAction records what was done there.
+func (s *orderService) CancelOrder(ctx context.Context, orderID uuid.UUID, reason, shopifyAction string) error {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
 		return err
 	}
 
-	// Log event
-	event := &domain.OrderEvent{
-		SupplierOrderID: orderID,
-		EventType:       "status_change",
-		EventData: map[string]interface{}{
-			"from":   order.Status,
-			"to":     domain.OrderStatusRejected,
-			"reason": reason,
-		},
+	// Already canceled - idempotent success
+	if order.Status == domain.OrderStatusCanceled {
+		return nil
 	}
-	s.repos.OrderEvent.Create(ctx, event)
 
-	return nil
+	change := StatusChange{
+		Source: "partner",
+		Data:   map[string]interface{}{"shopify_action": shopifyAction},
+	}
+	if reason != "" {
+		change.Data["reason"] = reason
+	}
+	return s.ChangeStatus(ctx, order, domain.OrderStatusCanceled, change)
 }
 
 // ShipOrder marks an order as shipped with tracking information (idempotent: already shipped returns success)
@@ -207,38 +283,248 @@ func (s *orderService) ShipOrder(ctx context.Context, orderID uuid.UUID, carrier
 	}
 
 	// Already shipped/fulfilled - idempotent success
-	if order.Status == domain.OrderStatusFulfilled || order.Status == domain.OrderStatusShipped {
+	if order.Status == domain.OrderStatusFulfilled {
 		return nil
 	}
+	// Staff confirm before shipping; only Shopify fulfillments may move an unconfirmed order
+	if order.Status == domain.OrderStatusIncompleteCaution {
+		return &errors.ErrInvalidStateTransition{From: order.Status, To: domain.OrderStatusFulfilled}
+	}
+
+	// Update status, then tracking
+	change := StatusChange{
+		Data: map[string]interface{}{
+			"carrier":         carrier,
+			"tracking_number": trackingNumber,
+		},
+	}
+	if trackingURL != nil {
+		change.Data["tracking_url"] = *trackingURL
+	}
+	if err := s.ChangeStatus(ctx, order, domain.OrderStatusFulfilled, change); err != nil {
+		return err
+	}
+	return s.repos.SupplierOrder.UpdateTracking(ctx, orderID, &carrier, &trackingNumber, trackingURL)
+}
+
+// OrderAmendment is the resolved form of an OrderAmendRequest. Nil fields are left unchanged.
+type OrderAmendment struct {
+	Items           []*domain.SupplierOrderItem
+	ShippingAddress map[string]interface{}
+	CartTotal       *domain.Money
+}
+
+// DiffOrderAmendment describes what amend changes on order (items aggregated by SKU).
+// An empty map means the amendment is a no-op.
+func DiffOrderAmendment(order *domain.SupplierOrder, currentItems []*domain.SupplierOrderItem, amend OrderAmendment) map[string]interface{} {
+	changes := make(map[string]interface{})
+
+	if amend.Items != nil {
+		type skuLine struct {
+			title    string
+			price    domain.Money
+			quantity int
+		}
+		aggregate := func(items []*domain.SupplierOrderItem) (map[string]*skuLine, []string) {
+			lines := make(map[string]*skuLine)
+			var skus []string
+			for _, item := range items {
+				if line, ok := lines[item.SKU]; ok {
+					line.quantity += item.Quantity
+					continue
+				}
+				lines[item.SKU] = &skuLine{title: item.Title, price: item.Price, quantity: item.Quantity}
+				skus = append(skus, item.SKU)
+			}
+			return lines, skus
+		}
+		before, beforeSKUs := aggregate(currentItems)
+		after, afterSKUs := aggregate(amend.Items)
+
+		var added, removed, changed []map[string]interface{}
+		for _, sku := range beforeSKUs {
+			old := before[sku]
+			updated, ok := after[sku]
+			if !ok {
+				removed = append(removed, map[string]interface{}{"sku": sku, "title": old.title, "quantity": old.quantity})
+				continue
+			}
+			if old.quantity == updated.quantity && old.price.Equal(updated.price) {
+				continue
+			}
+			change := map[string]interface{}{"sku": sku}
+			if old.quantity != updated.quantity {
+				change["quantity"] = map[string]interface{}{"from": old.quantity, "to": updated.quantity}
+			}
+			if !old.price.Equal(updated.price) {
+				change["price"] = map[string]interface{}{"from": old.price, "to": updated.price}
+			}
+			changed = append(changed, change)
+		}
+		for _, sku := range afterSKUs {
+			if _, ok := before[sku]; ok {
+				continue
+			}
+			line := after[sku]
+			added = append(added, map[string]interface{}{"sku": sku, "title": line.title, "price": line.price, "quantity": line.quantity})
+		}
+		if len(added) > 0 {
+			changes["items_added"] = added
+		}
+		if len(removed) > 0 {
+			changes["items_removed"] = removed
+		}
+		if len(changed) > 0 {
+			changes["items_changed"] = changed
+		}
+	}
 
-	// Validate state transition
-	if !order.Status.CanTransitionTo(domain.OrderStatusFulfilled) {
-		return &errors.ErrInvalidStateTransition{
-			From: order.Status,
-			To:   domain.OrderStatusFulfilled,
+	if amend.ShippingAddress != nil && !reflect.DeepEqual(order.ShippingAddress, amend.ShippingAddress) {
+		changes["shipping_address"] = map[string]interface{}{
+			"from": order.ShippingAddress,
+			"to":   amend.ShippingAddress,
 		}
 	}
 
-	// Update tracking
-	if err := s.repos.SupplierOrder.UpdateTracking(ctx, orderID, &carrier, &trackingNumber, trackingURL); err != nil {
-		return err
+	if amend.CartTotal != nil && !amend.CartTotal.Equal(order.CartTotal) {
+		changes["cart_total"] = map[string]interface{}{
+			"from": order.CartTotal,
+			"to":   *amend.CartTotal,
+		}
+	}
+
+	return changes
+}
+
+// AmendOrder applies an amendment to an INCOMPLETE_CAUTION order and records changes (from DiffOrderAmendment)
+// as an order_amended event. Shopify must already have been updated by the caller; shopifyAction records what was done there.
+// When the amendment raises the total of a partner with a credit limit it is checked as on cart submit: over the
+// limit, the reject policy returns *errors.ErrCreditLimitExceeded and nothing is stored, the hold policy returns a
+// CreditCheck to pass to RecordCreditCheck.
+func (s *orderService) AmendOrder(ctx context.Context, partner *domain.Partner, orderID uuid.UUID, amend OrderAmendment, changes map[string]interface{}, shopifyAction string) (*CreditCheck, error) {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only orders still awaiting confirmation can be amended
+	if order.Status != domain.OrderStatusIncompleteCaution {
+		return nil, &errors.ErrConflict{Message: fmt.Sprintf("order cannot be amended in status %s", order.Status)}
+	}
+
+	// A higher total is checked against the partner's credit limit as it is stored, as on cart submit
+	raisesTotal := amend.CartTotal != nil && amend.CartTotal.Cmp(order.CartTotal) > 0
+
+	// Items, address and total are stored together so a failure can't leave new items with the old total
+	if amend.ShippingAddress != nil {
+		order.ShippingAddress = amend.ShippingAddress
+	}
+	if amend.CartTotal != nil {
+		order.CartTotal = *amend.CartTotal
+	}
+	var creditCheck *CreditCheck
+	if partner.CreditLimit != nil && raisesTotal {
+		err = s.repos.SupplierOrder.AmendWithinCredit(ctx, order, amend.Items, creditExposureStatuses, func(exposure *repository.CreditExposure) error {
+			var checkErr error
+			creditCheck, checkErr = checkCartCredit(partner, exposure, order.CartTotal)
+			return checkErr
+		})
+	} else {
+		err = s.repos.SupplierOrder.Amend(ctx, order, amend.Items)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	// Log event
 	event := &domain.OrderEvent{
 		SupplierOrderID: orderID,
-		EventType:       "status_change",
+		EventType:       domain.OrderEventAmended,
 		EventData: map[string]interface{}{
-			"from":            order.Status,
-			"to":              domain.OrderStatusFulfilled,
-			"carrier":         carrier,
-			"tracking_number": trackingNumber,
+			"changes":        changes,
+			"source":         "partner",
+			"shopify_action": shopifyAction,
 		},
 	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	return creditCheck, nil
+}
+
+// SyncStatusFromShopify applies a status read from Shopify (or a Shopify webhook) through ChangeStatus.
+// A status that would move the order backwards (e.g. COMPLETE -> UNFULFILLED) is not applied: it is kept as a
+// status_change_rejected event and ErrInvalidStateTransition is returned.
+func (s *orderService) SyncStatusFromShopify(ctx context.Context, order *domain.SupplierOrder, status domain.OrderStatus, source string) error {
+	return s.ChangeStatus(ctx, order, status, StatusChange{Source: source})
+}
+
+// RecordTracking stores tracking info and logs a tracking_updated event when carrier, number or URL changed.
+func (s *orderService) RecordTracking(ctx context.Context, order *domain.SupplierOrder, carrier, trackingNumber, trackingURL *string, source string) error {
+	if stringPtrEqual(order.TrackingCarrier, carrier) && stringPtrEqual(order.TrackingNumber, trackingNumber) && stringPtrEqual(order.TrackingURL, trackingURL) {
+		return nil
+	}
+	if err := s.repos.SupplierOrder.UpdateTracking(ctx, order.ID, carrier, trackingNumber, trackingURL); err != nil {
+		return err
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventTrackingUpdated,
+		EventData: map[string]interface{}{
+			"source": source,
+		},
+	}
+	if carrier != nil {
+		event.EventData["tracking_carrier"] = *carrier
+	}
+	if trackingNumber != nil {
+		event.EventData["tracking_number"] = *trackingNumber
+	}
 	if trackingURL != nil {
 		event.EventData["tracking_url"] = *trackingURL
 	}
 	s.repos.OrderEvent.Create(ctx, event)
 
+	order.TrackingCarrier = carrier
+	order.TrackingNumber = trackingNumber
+	order.TrackingURL = trackingURL
 	return nil
 }
+
+// RecordDeliveryStatus stores the last Wassel delivery status and logs a delivery_status event when the status
+// code or waybill changed (Wassel may resend the same status).
+func (s *orderService) RecordDeliveryStatus(ctx context.Context, order *domain.SupplierOrder, status int, statusLabel, waybill, imageURL string) error {
+	if err := s.repos.SupplierOrder.UpdateLastDeliveryStatus(ctx, order.ID, status, statusLabel, waybill, imageURL); err != nil {
+		return err
+	}
+	if order.LastDeliveryStatus != nil && *order.LastDeliveryStatus == status && stringPtrEqual(order.LastDeliveryWaybill, &waybill) {
+		return nil
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventDeliveryStatus,
+		EventData: map[string]interface{}{
+			"status":       status,
+			"status_label": statusLabel,
+			"waybill":      waybill,
+		},
+	}
+	if imageURL != "" {
+		event.EventData["delivery_image_url"] = imageURL
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	order.LastDeliveryStatus = &status
+	order.LastDeliveryWaybill = &waybill
+	return nil
+}
+
+func stringPtrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
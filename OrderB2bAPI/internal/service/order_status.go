@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// StatusChange describes why an order status changes; it ends up in the status_change event.
+type StatusChange struct {
+	Source          string                 // who asked: supplier_staff, partner, shopify_sync, shopify_fulfillment, return, ...
+	RejectionReason *string                // stored on the order (REJECTED only)
+	Data            map[string]interface{} // extra event fields (reason, tracking, return_id, ...)
+}
+
+// ChangeStatus is the single write path for order status. It checks the transition against the state machine,
+// then updates the row only if the status is still order.Status (compare-and-set).
+//
+// Returns ErrInvalidStateTransition for an illegal transition (the attempt is kept as a status_change_rejected
+// event and the order is left unchanged) and ErrConflict when another writer changed the status first.
+// On success order.Status is updated. Changing to the current status is a no-op.
+func (s *orderService) ChangeStatus(ctx context.Context, order *domain.SupplierOrder, to domain.OrderStatus, change StatusChange) error {
+	from := order.Status
+	if from == to {
+		return nil
+	}
+
+	if !from.CanTransitionTo(to) {
+		s.logger.Warn("Rejected illegal order status change",
+			zap.String("order_id", order.ID.String()),
+			zap.String("from", string(from)),
+			zap.String("to", string(to)),
+			zap.String("source", change.Source))
+
+		// Shopify sync repeats the same stale status on every read; keep one event per distinct attempt
+		if !s.lastRejectedChangeIs(ctx, order.ID, from, to, change.Source) {
+			event := &domain.OrderEvent{
+				SupplierOrderID: order.ID,
+				EventType:       domain.OrderEventStatusChangeRejected,
+				EventData:       statusEventData(from, to, change),
+			}
+			s.repos.OrderEvent.Create(ctx, event)
+		}
+
+		return &errors.ErrInvalidStateTransition{From: from, To: to}
+	}
+
+	updated, err := s.repos.SupplierOrder.TransitionStatus(ctx, order.ID, from, to, change.RejectionReason)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		current, getErr := s.repos.SupplierOrder.GetByID(ctx, order.ID)
+		if getErr != nil {
+			return getErr
+		}
+		order.Status = current.Status
+		if current.Status == to {
+			// Someone else made the same change
+			return nil
+		}
+		return &errors.ErrConflict{Message: fmt.Sprintf("order status changed concurrently (now %s)", current.Status)}
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventStatusChange,
+		EventData:       statusEventData(from, to, change),
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	order.Status = to
+	return nil
+}
+
+func (s *orderService) lastRejectedChangeIs(ctx context.Context, orderID uuid.UUID, from, to domain.OrderStatus, source string) bool {
+	last, err := s.repos.OrderEvent.GetLatestByOrderID(ctx, orderID, domain.OrderEventStatusChangeRejected)
+	if err != nil {
+		return false
+	}
+	str := func(key string) string {
+		v, _ := last.EventData[key].(string)
+		return v
+	}
+	return str("from") == string(from) && str("to") == string(to) && str("source") == source
+}
+
+func statusEventData(from, to domain.OrderStatus, change StatusChange) map[string]interface{} {
+	data := map[string]interface{}{
+		"from": from,
+		"to":   to,
+	}
+	if change.Source != "" {
+		data["source"] = change.Source
+	}
+	for k, v := range change.Data {
+		data[k] = v
+	}
+	return data
+}
+
+// VerifyOrderStatuses fails if any order holds a status outside domain.OrderStatuses (e.g. a legacy name on a
+// database that has not run migration 000014). Call at startup: the state machine would refuse to move such orders.
+func VerifyOrderStatuses(ctx context.Context, repos *repository.Repositories) error {
+	counts, err := repos.SupplierOrder.CountUnknownStatuses(ctx, domain.OrderStatuses)
+	if err != nil {
+		return fmt.Errorf("checking order statuses: %w", err)
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	unknown := make([]string, 0, len(counts))
+	for status, n := range counts {
+		unknown = append(unknown, fmt.Sprintf("%s (%d orders)", status, n))
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("orders with unknown status: %s; run migration 000014_order_status_constraint", strings.Join(unknown, ", "))
+}
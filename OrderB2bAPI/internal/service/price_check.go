@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// PriceDiscrepancy is one submitted amount that disagrees with ours.
+type PriceDiscrepancy struct {
+	Field     string       `json:"field"` // e.g. "items[0].price", "totals.subtotal", "totals.total"
+	SKU       string       `json:"sku,omitempty"`
+	Submitted domain.Money `json:"submitted"`
+	Expected  domain.Money `json:"expected"`
+}
+
+// PriceCheck is the result of CheckCartPrices.
+type PriceCheck struct {
+	Policy        domain.PricePolicy
+	Discrepancies []PriceDiscrepancy
+	Overridden    bool // req prices and totals were replaced with ours
+}
+
+// CheckCartPrices compares the price of each of our items in req with the partner's price for it (the price list's
+// agreed price, else the synced Shopify price in supplierItems), and the submitted subtotal/total with the ones
+// recomputed from the item prices (total = subtotal + tax + shipping). Items without a known price, and other
+// stores' items, are taken at the submitted price. priceList may be nil.
+//
+// What happens on a discrepancy depends on policy: reject returns *errors.ErrValidation keyed by field, flag
+// leaves req unchanged, override rewrites req's item prices and totals with ours.
+func CheckCartPrices(
+	req *CartSubmitRequest,
+	supplierItems map[string]*domain.PartnerSKUMapping,
+	priceList *PriceList,
+	policy domain.PricePolicy,
+) (*PriceCheck, error) {
+	if !policy.IsValid() {
+		policy = domain.PricePolicyFlag
+	}
+	check := &PriceCheck{Policy: policy}
+
+	prices := make([]domain.Money, len(req.Items))
+	var subtotal domain.Money
+	for i, item := range req.Items {
+		prices[i] = item.Price
+		if expected, ok := priceList.UnitPrice(supplierItems[item.SKU]); ok && !amountsEqual(item.Price, expected) {
+			check.Discrepancies = append(check.Discrepancies, PriceDiscrepancy{
+				Field:     fmt.Sprintf("items[%d].price", i),
+				SKU:       item.SKU,
+				Submitted: item.Price,
+				Expected:  expected,
+			})
+			prices[i] = expected
+		}
+	}
+
+	// Totals are recomputed from the submitted prices under reject/flag so only real arithmetic errors are
+	// reported for them; under override they follow our prices.
+	for i, item := range req.Items {
+		price := item.Price
+		if policy == domain.PricePolicyOverride {
+			price = prices[i]
+		}
+		subtotal = subtotal.Add(price.Mul(item.Quantity))
+	}
+	total := subtotal.Add(req.Totals.Tax).Add(req.Totals.Shipping)
+	if !amountsEqual(req.Totals.Subtotal, subtotal) {
+		check.Discrepancies = append(check.Discrepancies, PriceDiscrepancy{
+			Field:     "totals.subtotal",
+			Submitted: req.Totals.Subtotal,
+			Expected:  subtotal,
+		})
+	}
+	if !amountsEqual(req.Totals.Total, total) {
+		check.Discrepancies = append(check.Discrepancies, PriceDiscrepancy{
+			Field:     "totals.total",
+			Submitted: req.Totals.Total,
+			Expected:  total,
+		})
+	}
+
+	if len(check.Discrepancies) == 0 {
+		return check, nil
+	}
+	switch policy {
+	case domain.PricePolicyReject:
+		fields := make(map[string]string, len(check.Discrepancies))
+		for _, d := range check.Discrepancies {
+			fields[d.Field] = fmt.Sprintf("expected %s, got %s", d.Expected, d.Submitted)
+		}
+		return check, &errors.ErrValidation{Message: "prices do not match", Fields: fields}
+	case domain.PricePolicyOverride:
+		for i := range req.Items {
+			req.Items[i].Price = prices[i]
+		}
+		req.Totals.Subtotal = subtotal
+		req.Totals.Total = total
+		check.Overridden = true
+	}
+	return check, nil
+}
+
+// RecordPriceCheck logs a price_discrepancy event on the order when the check found any.
+func (s *orderService) RecordPriceCheck(ctx context.Context, order *domain.SupplierOrder, check *PriceCheck) {
+	if check == nil || len(check.Discrepancies) == 0 {
+		return
+	}
+	discrepancies := make([]map[string]interface{}, len(check.Discrepancies))
+	for i, d := range check.Discrepancies {
+		discrepancies[i] = map[string]interface{}{
+			"field":     d.Field,
+			"submitted": d.Submitted,
+			"expected":  d.Expected,
+		}
+		if d.SKU != "" {
+			discrepancies[i]["sku"] = d.SKU
+		}
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventPriceDiscrepancy,
+		EventData: map[string]interface{}{
+			"policy":        check.Policy,
+			"overridden":    check.Overridden,
+			"discrepancies": discrepancies,
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+}
+
+// CheckCartCurrency checks that the cart is in the partner's currency, the only one its prices and orders use.
+// A cart that names no currency is taken to be in it.
+func CheckCartCurrency(req *CartSubmitRequest, partner *domain.Partner) error {
+	currency := strings.ToUpper(strings.TrimSpace(req.Totals.Currency))
+	if currency != "" && currency != partner.Currency {
+		return &errors.ErrValidation{
+			Message: "unsupported currency",
+			Fields:  map[string]string{"totals.currency": fmt.Sprintf("must be %s", partner.Currency)},
+		}
+	}
+	req.Totals.Currency = partner.Currency
+	return nil
+}
+
+// syncedPrice returns the Shopify price stored on a partner SKU mapping.
+func syncedPrice(mapping *domain.PartnerSKUMapping) (domain.Money, bool) {
+	if mapping == nil || mapping.Price == nil || mapping.Price.IsNegative() {
+		return domain.Money{}, false
+	}
+	return *mapping.Price, true
+}
+
+// amountsEqual compares amounts at the currency's precision, so 12.5 and 12.500 JOD match.
+func amountsEqual(a, b domain.Money) bool {
+	return a.Round().Equal(b.Round())
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// PriceList is a partner's price list as of one day: the rules in effect, by SKU and for the partner's catalog
+// collection. A SKU rule beats the collection rule; among rules for the same target the one starting latest wins.
+// A nil *PriceList has no rules, so every SKU keeps its Shopify price.
+type PriceList struct {
+	bySKU      map[string]*domain.PartnerPrice
+	collection *domain.PartnerPrice
+}
+
+// NewPriceList picks the rules in effect on day. rules must be ordered newest first within a target (as
+// PartnerPriceRepository.ListByPartnerID returns them). Collection rules only apply to the partner's catalog
+// collection (collectionHandle), which is the collection its SKUs are synced from.
+func NewPriceList(rules []*domain.PartnerPrice, collectionHandle string, day time.Time) *PriceList {
+	list := &PriceList{bySKU: make(map[string]*domain.PartnerPrice)}
+	for _, rule := range rules {
+		if !rule.ActiveOn(day) {
+			continue
+		}
+		switch {
+		case rule.SKU != nil:
+			if _, ok := list.bySKU[*rule.SKU]; !ok {
+				list.bySKU[*rule.SKU] = rule
+			}
+		case rule.CollectionHandle != nil && *rule.CollectionHandle == collectionHandle && collectionHandle != "":
+			if list.collection == nil {
+				list.collection = rule
+			}
+		}
+	}
+	return list
+}
+
+// IsEmpty reports whether no rule is in effect.
+func (l *PriceList) IsEmpty() bool {
+	return l == nil || (len(l.bySKU) == 0 && l.collection == nil)
+}
+
+// Rule returns the rule that prices sku, or nil.
+func (l *PriceList) Rule(sku string) *domain.PartnerPrice {
+	if l == nil {
+		return nil
+	}
+	if rule, ok := l.bySKU[sku]; ok {
+		return rule
+	}
+	return l.collection
+}
+
+// Apply returns the agreed price for sku given its Shopify price (hasListPrice false when unknown; a percentage
+// rule then cannot be applied). ok is false when no rule prices the SKU.
+func (l *PriceList) Apply(sku string, listPrice domain.Money, hasListPrice bool) (domain.Money, bool) {
+	rule := l.Rule(sku)
+	switch {
+	case rule == nil:
+		return domain.Money{}, false
+	case rule.FixedPrice != nil:
+		return *rule.FixedPrice, true
+	case rule.DiscountPercent != nil && hasListPrice:
+		return listPrice.Percent(100 - *rule.DiscountPercent), true
+	}
+	return domain.Money{}, false
+}
+
+// AgreedPrice is the price list's price for a mapped SKU, off its synced Shopify price. ok is false when no rule
+// prices the SKU.
+func (l *PriceList) AgreedPrice(mapping *domain.PartnerSKUMapping) (domain.Money, bool) {
+	if mapping == nil {
+		return domain.Money{}, false
+	}
+	listPrice, hasListPrice := syncedPrice(mapping)
+	return l.Apply(mapping.SKU, listPrice, hasListPrice)
+}
+
+// UnitPrice is the price the partner should pay for a mapped SKU: the agreed price when a rule applies, otherwise
+// the synced Shopify price. ok is false when neither is known.
+func (l *PriceList) UnitPrice(mapping *domain.PartnerSKUMapping) (domain.Money, bool) {
+	if price, ok := l.AgreedPrice(mapping); ok {
+		return price, true
+	}
+	return syncedPrice(mapping)
+}
+
+// ApplyToCatalogProduct rewrites the variant prices of a ProductB2B product node with the agreed prices, keeping
+// the Shopify price in listPrice. Variants without a rule are left as they are.
+func (l *PriceList) ApplyToCatalogProduct(product map[string]interface{}) {
+	if l.IsEmpty() {
+		return
+	}
+	// ProductB2B can return variants as [] or { nodes: [] }
+	variants, _ := product["variants"].([]interface{})
+	if vmap, ok := product["variants"].(map[string]interface{}); ok {
+		variants, _ = vmap["nodes"].([]interface{})
+	}
+	for _, v := range variants {
+		variant, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sku, _ := variant["sku"].(string)
+		if sku == "" {
+			continue
+		}
+		priceStr, _ := variant["price"].(string)
+		listPrice, err := domain.ParseMoney(priceStr, "")
+		if price, ok := l.Apply(sku, listPrice, err == nil); ok {
+			variant["listPrice"] = priceStr
+			variant["price"] = price.String()
+		}
+	}
+}
+
+// ParsePartnerPrice validates a price list rule for partnerID. Errors are *errors.ErrValidation keyed by field.
+func ParsePartnerPrice(req *PartnerPriceRequest, partnerID uuid.UUID) (*domain.PartnerPrice, error) {
+	fields := make(map[string]string)
+	price := &domain.PartnerPrice{PartnerID: partnerID}
+
+	sku := strings.TrimSpace(req.SKU)
+	collectionHandle := strings.TrimSpace(req.CollectionHandle)
+	switch {
+	case sku != "" && collectionHandle != "":
+		fields["sku"] = "set either sku or collection_handle, not both"
+	case sku != "":
+		price.SKU = &sku
+	case collectionHandle != "":
+		price.CollectionHandle = &collectionHandle
+	default:
+		fields["sku"] = "sku or collection_handle is required"
+	}
+
+	switch {
+	case req.FixedPrice != nil && req.DiscountPercent != nil:
+		fields["fixed_price"] = "set either fixed_price or discount_percent, not both"
+	case req.FixedPrice != nil:
+		if req.FixedPrice.IsNegative() {
+			fields["fixed_price"] = "must not be negative"
+		}
+		fixedPrice := req.FixedPrice.Round()
+		price.FixedPrice = &fixedPrice
+	case req.DiscountPercent != nil:
+		if *req.DiscountPercent <= 0 || *req.DiscountPercent > 100 {
+			fields["discount_percent"] = "must be greater than 0 and at most 100"
+		}
+		price.DiscountPercent = req.DiscountPercent
+	default:
+		fields["fixed_price"] = "fixed_price or discount_percent is required"
+	}
+
+	parseDate := func(field, value string) *time.Time {
+		if value == "" {
+			return nil
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			fields[field] = "must be a date (YYYY-MM-DD)"
+			return nil
+		}
+		return &date
+	}
+	price.ValidFrom = parseDate("valid_from", strings.TrimSpace(req.ValidFrom))
+	price.ValidTo = parseDate("valid_to", strings.TrimSpace(req.ValidTo))
+	if price.ValidFrom != nil && price.ValidTo != nil && price.ValidTo.Before(*price.ValidFrom) {
+		fields["valid_to"] = "must not be before valid_from"
+	}
+
+	if len(fields) > 0 {
+		return nil, &errors.ErrValidation{Message: "invalid price list rule", Fields: fields}
+	}
+	return price, nil
+}
+
+type priceListService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewPriceListService creates a new partner price list service
+func NewPriceListService(repos *repository.Repositories, logger *zap.Logger) *priceListService {
+	return &priceListService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// Load returns the partner's price list in effect on day (warehouse local date).
+func (s *priceListService) Load(ctx context.Context, partner *domain.Partner, day time.Time) (*PriceList, error) {
+	rules, err := s.repos.PartnerPrice.ListByPartnerID(ctx, partner.ID)
+	if err != nil {
+		return nil, err
+	}
+	collectionHandle := ""
+	if partner.CollectionHandle != nil {
+		collectionHandle = *partner.CollectionHandle
+	}
+	return NewPriceList(rules, collectionHandle, day), nil
+}
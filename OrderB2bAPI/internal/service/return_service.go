@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Wassel delivery statuses that mean the shipment is coming back
+const (
+	WasselStatusReturnedFromCustomer = 180
+	WasselStatusReturnedShelf        = 190
+	WasselStatusReturnedToShipper    = 210
+)
+
+// IsWasselReturnStatus reports whether a Wassel status code opens a return.
+func IsWasselReturnStatus(status int) bool {
+	return status == WasselStatusReturnedFromCustomer ||
+		status == WasselStatusReturnedShelf ||
+		status == WasselStatusReturnedToShipper
+}
+
+type returnService struct {
+	repos  *repository.Repositories
+	orders *orderService
+	cod    *codService
+	logger *zap.Logger
+}
+
+// NewReturnService creates a new return (RMA) service
+func NewReturnService(repos *repository.Repositories, logger *zap.Logger) *returnService {
+	return &returnService{
+		repos:  repos,
+		orders: NewOrderService(repos, logger),
+		cod:    NewCODService(repos, logger),
+		logger: logger,
+	}
+}
+
+// returnableQuantity is how much of an item can be returned: what shipped, or the full quantity when the
+// order was fulfilled as a whole (no per-item fulfillment recorded).
+func returnableQuantity(item *domain.SupplierOrderItem) int {
+	if item.FulfilledQuantity > 0 {
+		return item.FulfilledQuantity
+	}
+	return item.Quantity
+}
+
+// BuildReturnItems resolves requested SKUs to order items. An empty request returns everything returnable.
+func BuildReturnItems(orderItems []*domain.SupplierOrderItem, requested []ReturnItemRequest) ([]*domain.OrderReturnItem, error) {
+	if len(requested) == 0 {
+		items := make([]*domain.OrderReturnItem, 0, len(orderItems))
+		for _, item := range orderItems {
+			if qty := returnableQuantity(item); qty > 0 {
+				items = append(items, &domain.OrderReturnItem{SupplierOrderItemID: item.ID, Quantity: qty})
+			}
+		}
+		return items, nil
+	}
+
+	fields := make(map[string]string)
+	var items []*domain.OrderReturnItem
+	for _, req := range requested {
+		remaining := req.Quantity
+		found := false
+		for _, item := range orderItems {
+			if item.SKU != req.SKU {
+				continue
+			}
+			found = true
+			qty := returnableQuantity(item)
+			if qty > remaining {
+				qty = remaining
+			}
+			if qty > 0 {
+				items = append(items, &domain.OrderReturnItem{SupplierOrderItemID: item.ID, Quantity: qty})
+				remaining -= qty
+			}
+		}
+		if !found {
+			fields[req.SKU] = "not in this order"
+		} else if remaining > 0 {
+			fields[req.SKU] = fmt.Sprintf("quantity exceeds shipped quantity by %d", remaining)
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &errors.ErrValidation{Message: "invalid return items", Fields: fields}
+	}
+	return items, nil
+}
+
+// OpenReturn opens a return for a shipped order (PARTIALLY_FULFILLED, FULFILLED or COMPLETE) and moves it to
+// RETURN_IN_PROGRESS. ret needs Source, Reason and Items; returns ErrInvalidStateTransition for orders that were
+// not shipped and ErrConflict when the order already has an open return.
+func (s *returnService) OpenReturn(ctx context.Context, order *domain.SupplierOrder, ret *domain.OrderReturn) error {
+	// Only shipped orders can move to RETURN_IN_PROGRESS
+	if !order.Status.CanTransitionTo(domain.OrderStatusReturnInProgress) {
+		return &errors.ErrInvalidStateTransition{From: order.Status, To: domain.OrderStatusReturnInProgress}
+	}
+
+	ret.SupplierOrderID = order.ID
+	ret.Status = domain.ReturnStatusRequested
+	ret.PreviousOrderStatus = order.Status
+	if err := s.repos.OrderReturn.Create(ctx, ret); err != nil {
+		return err
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventReturnOpened,
+		EventData: map[string]interface{}{
+			"return_id": ret.ID.String(),
+			"source":    ret.Source,
+			"reason":    ret.Reason,
+		},
+	}
+	if ret.WasselStatus != nil {
+		event.EventData["wassel_status"] = *ret.WasselStatus
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	return s.setOrderStatus(ctx, order, domain.OrderStatusReturnInProgress, ret)
+}
+
+// OpenWasselReturn opens a return for a Wassel return status (180/190/210) covering everything shipped.
+// A shipment comes back once, so when the order already has a Wassel return (open or decided) or an open return,
+// that return is returned with opened=false; a RETURNED order gets none (ret nil). Later and repeated updates
+// therefore never open a second return, whose approval would restock the same goods again.
+func (s *returnService) OpenWasselReturn(ctx context.Context, order *domain.SupplierOrder, wasselStatus int) (ret *domain.OrderReturn, opened bool, err error) {
+	if order.Status == domain.OrderStatusReturned {
+		return nil, false, nil
+	}
+	returns, err := s.repos.OrderReturn.ListByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, existing := range returns {
+		if existing.Source == "wassel" || existing.Status == domain.ReturnStatusRequested {
+			return existing, false, nil
+		}
+	}
+
+	orderItems, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	items, err := BuildReturnItems(orderItems, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reason := domain.ReturnReasonCustomerReturned
+	if wasselStatus == WasselStatusReturnedToShipper {
+		reason = domain.ReturnReasonReturnToShipper
+	}
+	ret = &domain.OrderReturn{
+		Source:       "wassel",
+		Reason:       reason,
+		WasselStatus: &wasselStatus,
+		Items:        items,
+	}
+	if err := s.OpenReturn(ctx, order, ret); err != nil {
+		if _, ok := err.(*errors.ErrConflict); ok {
+			// Opened concurrently by another delivery update
+			existing, getErr := s.repos.OrderReturn.GetOpenByOrderID(ctx, order.ID)
+			return existing, false, getErr
+		}
+		return nil, false, err
+	}
+	return ret, true, nil
+}
+
+// ApproveReturn approves a REQUESTED return and moves the order to RETURNED.
+// Shopify restocking (when restock is true) must already have been done by the caller.
+func (s *returnService) ApproveReturn(ctx context.Context, returnID, staffID uuid.UUID, restock bool, note string) (*domain.OrderReturn, error) {
+	ret, err := s.decide(ctx, returnID, staffID, domain.ReturnStatusApproved, note)
+	if err != nil {
+		return nil, err
+	}
+	ret.Restock = restock
+	if err := s.repos.OrderReturn.UpdateDecision(ctx, ret); err != nil {
+		return nil, err
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: ret.SupplierOrderID,
+		EventType:       domain.OrderEventReturnApproved,
+		EventData: map[string]interface{}{
+			"return_id": ret.ID.String(),
+			"restock":   restock,
+			"staff_id":  staffID.String(),
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	order, err := s.repos.SupplierOrder.GetByID(ctx, ret.SupplierOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cod.RecordReturn(ctx, order, ret); err != nil {
+		s.logger.Warn("Failed to record COD refund for return", zap.String("return_id", ret.ID.String()), zap.Error(err))
+	}
+	if order.Status == domain.OrderStatusReturnInProgress {
+		if err := s.setOrderStatus(ctx, order, domain.OrderStatusReturned, ret); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// RejectReturn rejects a REQUESTED return and restores the order's status from before the return.
+func (s *returnService) RejectReturn(ctx context.Context, returnID, staffID uuid.UUID, reason string) (*domain.OrderReturn, error) {
+	ret, err := s.decide(ctx, returnID, staffID, domain.ReturnStatusRejected, reason)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repos.OrderReturn.UpdateDecision(ctx, ret); err != nil {
+		return nil, err
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: ret.SupplierOrderID,
+		EventType:       domain.OrderEventReturnRejected,
+		EventData: map[string]interface{}{
+			"return_id": ret.ID.String(),
+			"reason":    reason,
+			"staff_id":  staffID.String(),
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	order, err := s.repos.SupplierOrder.GetByID(ctx, ret.SupplierOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == domain.OrderStatusReturnInProgress && order.Status.CanTransitionTo(ret.PreviousOrderStatus) {
+		if err := s.setOrderStatus(ctx, order, ret.PreviousOrderStatus, ret); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// decide loads a REQUESTED return and stamps the decision fields (not yet persisted).
+func (s *returnService) decide(ctx context.Context, returnID, staffID uuid.UUID, status domain.ReturnStatus, note string) (*domain.OrderReturn, error) {
+	ret, err := s.repos.OrderReturn.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != domain.ReturnStatusRequested {
+		return nil, &errors.ErrConflict{Message: fmt.Sprintf("return already %s", ret.Status)}
+	}
+	ret.Status = status
+	ret.DecidedByStaffID = &staffID
+	if note != "" {
+		ret.DecisionNote = &note
+	}
+	return ret, nil
+}
+
+func (s *returnService) setOrderStatus(ctx context.Context, order *domain.SupplierOrder, to domain.OrderStatus, ret *domain.OrderReturn) error {
+	return s.orders.ChangeStatus(ctx, order, to, StatusChange{
+		Source: "return",
+		Data:   map[string]interface{}{"return_id": ret.ID.String()},
+	})
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+)
+
+// shopCurrencyCode is the store currency used for custom line item prices in order edits.
+const shopCurrencyCode = domain.DefaultCurrency
+
+type shopifyUserError struct {
+	Field   []string `json:"field"`
+	Message string   `json:"message"`
+}
+
+// UpdateDraftOrder replaces a draft order's shipping address and, when items is non-nil, its line items.
+// Used to amend an order whose draft was never completed.
+func (s *shopifyService) UpdateDraftOrder(ctx context.Context, draftOrderID int64, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) error {
+	input := shopify.DraftOrderInput{
+		ShippingAddress: buildDraftOrderShippingAddress(order),
+	}
+	if items != nil {
+		input.LineItems = buildDraftOrderLineItems(items)
+	}
+	variables := map[string]interface{}{
+		"id":    fmt.Sprintf("gid://shopify/DraftOrder/%d", draftOrderID),
+		"input": input,
+	}
+	resp, err := s.client.Execute(shopify.DraftOrderUpdateMutation, variables)
+	if err != nil {
+		return fmt.Errorf("draftOrderUpdate: %w", err)
+	}
+	var result struct {
+		DraftOrderUpdate struct {
+			UserErrors []shopifyUserError `json:"userErrors"`
+		} `json:"draftOrderUpdate"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("parse draftOrderUpdate response: %w", err)
+	}
+	if len(result.DraftOrderUpdate.UserErrors) > 0 {
+		return fmt.Errorf("draftOrderUpdate userErrors: %v", result.DraftOrderUpdate.UserErrors)
+	}
+	s.logger.Info("Updated Shopify draft order", zap.Int64("draft_order_id", draftOrderID))
+	return nil
+}
+
+// EditOrderLineItems makes the Shopify order's line items match items using an order edit
+// (orderEditBegin → set quantity / add variant / add custom item → orderEditCommit).
+// Supplier items are matched by variant, other items by their custom line item title. Removed items are restocked.
+func (s *shopifyService) EditOrderLineItems(ctx context.Context, shopifyOrderName string, items []*domain.SupplierOrderItem, staffNote string) error {
+	orderGID, err := s.GetOrderGIDByName(ctx, shopifyOrderName)
+	if err != nil {
+		return fmt.Errorf("resolve order by name: %w", err)
+	}
+
+	resp, err := s.client.Execute(shopify.OrderEditBeginMutation, map[string]interface{}{"id": orderGID})
+	if err != nil {
+		return fmt.Errorf("orderEditBegin: %w", err)
+	}
+	var begin struct {
+		OrderEditBegin struct {
+			CalculatedOrder struct {
+				ID        string `json:"id"`
+				LineItems struct {
+					Edges []struct {
+						Node struct {
+							ID       string `json:"id"`
+							Title    string `json:"title"`
+							Quantity int    `json:"quantity"`
+							Variant  *struct {
+								ID string `json:"id"`
+							} `json:"variant"`
+						} `json:"node"`
+					} `json:"edges"`
+				} `json:"lineItems"`
+			} `json:"calculatedOrder"`
+			UserErrors []shopifyUserError `json:"userErrors"`
+		} `json:"orderEditBegin"`
+	}
+	if err := json.Unmarshal(resp.Data, &begin); err != nil {
+		return fmt.Errorf("parse orderEditBegin response: %w", err)
+	}
+	if len(begin.OrderEditBegin.UserErrors) > 0 {
+		return fmt.Errorf("orderEditBegin userErrors: %v", begin.OrderEditBegin.UserErrors)
+	}
+	calculatedOrderID := begin.OrderEditBegin.CalculatedOrder.ID
+
+	// Desired quantities keyed by variant GID (supplier items) or custom title (other items)
+	type wantedLine struct {
+		variantGID string
+		title      string
+		price      domain.Money
+		discount   domain.Money // per-unit wholesale discount for added variants
+		quantity   int
+	}
+	wanted := make(map[string]*wantedLine)
+	var order []string
+	for _, item := range items {
+		var key string
+		line := &wantedLine{}
+		if item.IsSupplierItem && item.ShopifyVariantID != nil {
+			line.variantGID = fmt.Sprintf("gid://shopify/ProductVariant/%d", *item.ShopifyVariantID)
+			line.discount, _ = wholesaleDiscountAmount(item)
+			key = "variant:" + line.variantGID
+		} else {
+			line.title = customLineItemTitle(item)
+			line.price = item.Price
+			key = "title:" + line.title
+		}
+		if existing, ok := wanted[key]; ok {
+			existing.quantity += item.Quantity
+			continue
+		}
+		line.quantity = item.Quantity
+		wanted[key] = line
+		order = append(order, key)
+	}
+
+	changed := false
+	seen := make(map[string]bool)
+	for _, edge := range begin.OrderEditBegin.CalculatedOrder.LineItems.Edges {
+		node := edge.Node
+		key := "title:" + node.Title
+		if node.Variant != nil && node.Variant.ID != "" {
+			key = "variant:" + node.Variant.ID
+		}
+		target := 0
+		if line, ok := wanted[key]; ok && !seen[key] {
+			target = line.quantity
+		}
+		seen[key] = true
+		if node.Quantity == target {
+			continue
+		}
+		if err := s.executeMutation(shopify.OrderEditSetQuantityMutation, "orderEditSetQuantity", map[string]interface{}{
+			"id":         calculatedOrderID,
+			"lineItemId": node.ID,
+			"quantity":   target,
+			"restock":    true,
+		}); err != nil {
+			return err
+		}
+		changed = true
+	}
+	for _, key := range order {
+		if seen[key] {
+			continue
+		}
+		line := wanted[key]
+		if line.variantGID != "" {
+			err = s.addOrderEditVariant(calculatedOrderID, line.variantGID, line.quantity, line.discount)
+		} else {
+			err = s.executeMutation(shopify.OrderEditAddCustomItemMutation, "orderEditAddCustomItem", map[string]interface{}{
+				"id":       calculatedOrderID,
+				"title":    line.title,
+				"price":    shopify.NewMoneyInput(line.price, shopCurrencyCode),
+				"quantity": line.quantity,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if !changed {
+		s.logger.Debug("Order edit: Shopify line items already match", zap.String("shopify_order_name", shopifyOrderName))
+		return nil
+	}
+
+	commitVars := map[string]interface{}{
+		"id":             calculatedOrderID,
+		"notifyCustomer": false,
+	}
+	if staffNote != "" {
+		commitVars["staffNote"] = staffNote
+	}
+	if err := s.executeMutation(shopify.OrderEditCommitMutation, "orderEditCommit", commitVars); err != nil {
+		return err
+	}
+	s.logger.Info("Committed Shopify order edit", zap.String("shopify_order_name", shopifyOrderName))
+	return nil
+}
+
+// addOrderEditVariant adds a variant to the calculated order, with a per-unit wholesale discount when discount > 0.
+func (s *shopifyService) addOrderEditVariant(calculatedOrderID, variantGID string, quantity int, discount domain.Money) error {
+	resp, err := s.client.Execute(shopify.OrderEditAddVariantMutation, map[string]interface{}{
+		"id":        calculatedOrderID,
+		"variantId": variantGID,
+		"quantity":  quantity,
+	})
+	if err != nil {
+		return fmt.Errorf("orderEditAddVariant: %w", err)
+	}
+	var result struct {
+		OrderEditAddVariant struct {
+			CalculatedLineItem struct {
+				ID string `json:"id"`
+			} `json:"calculatedLineItem"`
+			UserErrors []shopifyUserError `json:"userErrors"`
+		} `json:"orderEditAddVariant"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("parse orderEditAddVariant response: %w", err)
+	}
+	if len(result.OrderEditAddVariant.UserErrors) > 0 {
+		return fmt.Errorf("orderEditAddVariant userErrors: %v", result.OrderEditAddVariant.UserErrors)
+	}
+	if discount.Cmp(domain.Money{}) <= 0 {
+		return nil
+	}
+	return s.executeMutation(shopify.OrderEditAddLineItemDiscountMutation, "orderEditAddLineItemDiscount", map[string]interface{}{
+		"id":         calculatedOrderID,
+		"lineItemId": result.OrderEditAddVariant.CalculatedLineItem.ID,
+		"discount": map[string]interface{}{
+			"description": wholesaleDiscountTitle,
+			"fixedValue":  shopify.NewMoneyInput(discount, shopCurrencyCode),
+		},
+	})
+}
+
+// UpdateOrderShippingAddress sets the Shopify order's shipping address from our order's shipping_address.
+func (s *shopifyService) UpdateOrderShippingAddress(ctx context.Context, shopifyOrderName string, order *domain.SupplierOrder) error {
+	orderGID, err := s.GetOrderGIDByName(ctx, shopifyOrderName)
+	if err != nil {
+		return fmt.Errorf("resolve order by name: %w", err)
+	}
+	return s.executeMutation(shopify.OrderUpdateMutation, "orderUpdate", map[string]interface{}{
+		"input": map[string]interface{}{
+			"id":              orderGID,
+			"shippingAddress": buildOrderMailingAddress(order),
+		},
+	})
+}
+
+// executeMutation runs a mutation whose payload (under field) only needs its userErrors checked.
+func (s *shopifyService) executeMutation(mutation, field string, variables map[string]interface{}) error {
+	resp, err := s.client.Execute(mutation, variables)
+	if err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	var result map[string]struct {
+		UserErrors []shopifyUserError `json:"userErrors"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("parse %s response: %w", field, err)
+	}
+	if userErrors := result[field].UserErrors; len(userErrors) > 0 {
+		return fmt.Errorf("%s userErrors: %v", field, userErrors)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+)
+
+// ReturnedLine is a quantity of an order item coming back in a return.
+type ReturnedLine struct {
+	Item     *domain.SupplierOrderItem
+	Quantity int
+}
+
+// RestockReturn restocks returned items on the Shopify order via refundCreate (restockType RETURN, no transactions,
+// so no money is refunded). Items are matched to Shopify line items by variant, or by custom line item title.
+func (s *shopifyService) RestockReturn(ctx context.Context, shopifyOrderName string, lines []ReturnedLine, note string) error {
+	orderGID, err := s.GetOrderGIDByName(ctx, shopifyOrderName)
+	if err != nil {
+		return fmt.Errorf("resolve order by name: %w", err)
+	}
+
+	resp, err := s.client.Execute(shopify.OrderLineItemsQuery, map[string]interface{}{"id": orderGID})
+	if err != nil {
+		return fmt.Errorf("order line items: %w", err)
+	}
+	var result struct {
+		Order struct {
+			LineItems struct {
+				Edges []struct {
+					Node struct {
+						ID                 string `json:"id"`
+						Title              string `json:"title"`
+						RefundableQuantity int    `json:"refundableQuantity"`
+						Variant            *struct {
+							ID string `json:"id"`
+						} `json:"variant"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"lineItems"`
+		} `json:"order"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("parse order line items response: %w", err)
+	}
+
+	var refundLines []shopify.RefundLineItemInput
+	used := make(map[string]int)
+	for _, line := range lines {
+		remaining := line.Quantity
+		for _, edge := range result.Order.LineItems.Edges {
+			if remaining <= 0 {
+				break
+			}
+			node := edge.Node
+			if line.Item.IsSupplierItem && line.Item.ShopifyVariantID != nil {
+				if node.Variant == nil || node.Variant.ID != fmt.Sprintf("gid://shopify/ProductVariant/%d", *line.Item.ShopifyVariantID) {
+					continue
+				}
+			} else if node.Variant != nil || (node.Title != customLineItemTitle(line.Item) && node.Title != line.Item.Title) {
+				continue
+			}
+			available := node.RefundableQuantity - used[node.ID]
+			if available <= 0 {
+				continue
+			}
+			qty := remaining
+			if qty > available {
+				qty = available
+			}
+			refundLines = append(refundLines, shopify.RefundLineItemInput{LineItemID: node.ID, Quantity: qty, RestockType: "RETURN"})
+			used[node.ID] += qty
+			remaining -= qty
+		}
+		if remaining > 0 {
+			s.logger.Warn("Restock return: Shopify line item not found or not refundable",
+				zap.String("shopify_order_name", shopifyOrderName),
+				zap.String("sku", line.Item.SKU),
+				zap.Int("unmatched_quantity", remaining))
+		}
+	}
+	if len(refundLines) == 0 {
+		return fmt.Errorf("no refundable Shopify line items match the returned items")
+	}
+
+	input := map[string]interface{}{
+		"orderId":         orderGID,
+		"notify":          false,
+		"refundLineItems": refundLines,
+	}
+	if note != "" {
+		input["note"] = note
+	}
+	if err := s.executeMutation(shopify.RefundCreateMutation, "refundCreate", map[string]interface{}{"input": input}); err != nil {
+		return err
+	}
+	s.logger.Info("Restocked returned items in Shopify", zap.String("shopify_order_name", shopifyOrderName), zap.Int("line_items", len(refundLines)))
+	return nil
+}
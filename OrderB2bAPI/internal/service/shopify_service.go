@@ -13,6 +13,7 @@ import (
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository"
 	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/phone"
 )
 
 type shopifyService struct {
@@ -227,6 +228,91 @@ func (s *shopifyService) SetOrderPartnerMetafield(ctx context.Context, shopifyOr
 	return nil
 }
 
+// CancelOrder cancels a completed Shopify order by name (e.g. "1033"), restocking its items.
+// Nothing is refunded: partner orders are paid on delivery. staffNote is shown on the order in Shopify admin.
+func (s *shopifyService) CancelOrder(ctx context.Context, shopifyOrderName string, staffNote string) error {
+	orderGID, err := s.GetOrderGIDByName(ctx, shopifyOrderName)
+	if err != nil {
+		return fmt.Errorf("resolve order by name: %w", err)
+	}
+	variables := map[string]interface{}{
+		"orderId":        orderGID,
+		"reason":         "CUSTOMER",
+		"refund":         false,
+		"restock":        true,
+		"notifyCustomer": false,
+	}
+	if staffNote != "" {
+		variables["staffNote"] = staffNote
+	}
+	resp, err := s.client.Execute(shopify.OrderCancelMutation, variables)
+	if err != nil {
+		return fmt.Errorf("orderCancel: %w", err)
+	}
+	var result struct {
+		OrderCancel struct {
+			UserErrors []struct {
+				Field   []string `json:"field"`
+				Message string   `json:"message"`
+				Code    string   `json:"code"`
+			} `json:"orderCancelUserErrors"`
+		} `json:"orderCancel"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("parse orderCancel response: %w", err)
+	}
+	if len(result.OrderCancel.UserErrors) > 0 {
+		return fmt.Errorf("orderCancel userErrors: %v", result.OrderCancel.UserErrors)
+	}
+	s.logger.Info("Canceled Shopify order", zap.String("shopify_order_name", shopifyOrderName))
+	return nil
+}
+
+// MarkOrderPaid marks a completed Shopify order (by name, e.g. "1033") as paid once Wassel has collected the cash.
+func (s *shopifyService) MarkOrderPaid(ctx context.Context, shopifyOrderName string) error {
+	orderGID, err := s.GetOrderGIDByName(ctx, shopifyOrderName)
+	if err != nil {
+		return fmt.Errorf("resolve order by name: %w", err)
+	}
+	if err := s.executeMutation(shopify.OrderMarkAsPaidMutation, "orderMarkAsPaid", map[string]interface{}{
+		"input": map[string]interface{}{"id": orderGID},
+	}); err != nil {
+		return err
+	}
+	s.logger.Info("Marked Shopify order as paid", zap.String("shopify_order_name", shopifyOrderName))
+	return nil
+}
+
+// DeleteDraftOrder deletes a Shopify draft order that was never completed into an order.
+func (s *shopifyService) DeleteDraftOrder(ctx context.Context, draftOrderID int64) error {
+	variables := map[string]interface{}{
+		"input": map[string]interface{}{
+			"id": fmt.Sprintf("gid://shopify/DraftOrder/%d", draftOrderID),
+		},
+	}
+	resp, err := s.client.Execute(shopify.DraftOrderDeleteMutation, variables)
+	if err != nil {
+		return fmt.Errorf("draftOrderDelete: %w", err)
+	}
+	var result struct {
+		DraftOrderDelete struct {
+			DeletedID  string `json:"deletedId"`
+			UserErrors []struct {
+				Field   []string `json:"field"`
+				Message string   `json:"message"`
+			} `json:"userErrors"`
+		} `json:"draftOrderDelete"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("parse draftOrderDelete response: %w", err)
+	}
+	if len(result.DraftOrderDelete.UserErrors) > 0 {
+		return fmt.Errorf("draftOrderDelete userErrors: %v", result.DraftOrderDelete.UserErrors)
+	}
+	s.logger.Info("Deleted Shopify draft order", zap.Int64("draft_order_id", draftOrderID))
+	return nil
+}
+
 // FindCustomerIDByPartnerOrderTag looks up Shopify for an order with the same partner order tag.
 // If found, returns that order's customer GID and phone so the new draft can be attached to the same customer
 // only when the request phone matches (same order number + same phone = same customer).
@@ -266,12 +352,12 @@ func (s *shopifyService) FindCustomerIDByPartnerOrderTag(ctx context.Context, pa
 		return nil, nil, nil
 	}
 	id := node.Customer.ID
-	phone := strings.TrimSpace(node.Customer.Phone)
-	if phone != "" {
-		customerPhone = &phone
+	shopifyPhone := strings.TrimSpace(node.Customer.Phone)
+	if shopifyPhone != "" {
+		customerPhone = &shopifyPhone
 	}
 	customerID = &id
-	s.logger.Info("Found existing Shopify order for partner order", zap.String("partner_order_id", partnerOrderID), zap.String("customer_id", id), zap.String("customer_phone", phone))
+	s.logger.Info("Found existing Shopify order for partner order", zap.String("partner_order_id", partnerOrderID), zap.String("customer_id", id), zap.String("customer_phone", shopifyPhone))
 	return customerID, customerPhone, nil
 }
 
@@ -310,24 +396,20 @@ func (s *shopifyService) GetOrderNameByPartnerOrderTag(ctx context.Context, part
 }
 
 // FindCustomerIDByPhone looks up a Shopify customer by phone number. Returns the customer GID if found.
-// Uses customers query with phone filter (e.g. phone:0778888888 or phone:778888888).
-func (s *shopifyService) FindCustomerIDByPhone(ctx context.Context, phone string) (*string, error) {
-	if phone == "" {
+// Shopify stores customer phones in E.164, so the number is searched in that form (phone:+962778888888).
+func (s *shopifyService) FindCustomerIDByPhone(ctx context.Context, phoneNumber string) (*string, error) {
+	if phoneNumber == "" {
 		return nil, nil
 	}
-	norm := normalizePhoneForComparison(phone)
-	if norm == "" {
+	number, err := phone.Parse(phoneNumber)
+	if err != nil {
 		return nil, nil
 	}
-	// Shopify phone search: try with digits only (e.g. 778888888 or 0778888888)
-	queryString := "phone:" + norm
-	if len(norm) < 10 {
-		queryString = "phone:0" + norm
-	}
+	queryString := "phone:" + number
 	query := fmt.Sprintf(shopify.CustomersByPhoneQueryTemplate, queryString)
 	resp, err := s.client.Execute(query, nil)
 	if err != nil {
-		s.logger.Debug("FindCustomerIDByPhone: Shopify query failed", zap.String("phone", phone), zap.Error(err))
+		s.logger.Debug("FindCustomerIDByPhone: Shopify query failed", zap.String("phone", number), zap.Error(err))
 		return nil, err
 	}
 	var result struct {
@@ -346,7 +428,7 @@ func (s *shopifyService) FindCustomerIDByPhone(ctx context.Context, phone string
 		return nil, nil
 	}
 	id := result.Customers.Edges[0].Node.ID
-	s.logger.Info("Found Shopify customer by phone", zap.String("phone", phone), zap.String("customer_id", id))
+	s.logger.Info("Found Shopify customer by phone", zap.String("phone", number), zap.String("customer_id", id))
 	return &id, nil
 }
 
@@ -507,18 +589,19 @@ func addressesEqual(addr1, city, province, zip, countryCode string, order shopif
 		norm(countryCode) == norm(order.CountryCode)
 }
 
-// countryToCountryCode maps country name or 2-letter code to ISO 3166-1 alpha-2 (Shopify CountryCode). We only operate in Jordan, so default is JO.
+// countryToCountryCode maps country name or 2-letter code to ISO 3166-1 alpha-2 (Shopify CountryCode). Jordan's names
+// in English and Arabic (see isJordan) map to JO. We only operate in Jordan, so default is JO.
 func countryToCountryCode(country string) string {
 	c := strings.TrimSpace(country)
 	if c == "" {
 		return "JO"
 	}
+	if isJordan(c) {
+		return "JO"
+	}
 	if len(c) == 2 {
 		return strings.ToUpper(c)
 	}
-	if strings.EqualFold(c, "jordan") || strings.EqualFold(c, "jo") {
-		return "JO"
-	}
 	return "JO"
 }
 
@@ -529,66 +612,8 @@ func (s *shopifyService) CreateDraftOrder(
 	items []*domain.SupplierOrderItem,
 	partnerName string,
 ) (int64, error) {
-	// Build line items
-	lineItems := make([]shopify.DraftOrderLineItemInput, 0, len(items))
-
-	for _, item := range items {
-		if item.IsSupplierItem && item.ShopifyVariantID != nil {
-			// Supplier item - use variant
-			variantIDStr := fmt.Sprintf("gid://shopify/ProductVariant/%d", *item.ShopifyVariantID)
-			lineItems = append(lineItems, shopify.DraftOrderLineItemInput{
-				VariantID: &variantIDStr,
-				Quantity:  item.Quantity,
-			})
-		} else {
-			// Non-supplier item - use custom line item
-			priceStr := fmt.Sprintf("%.2f", item.Price)
-			title := item.Title
-			if item.ProductURL != nil {
-				title = fmt.Sprintf("%s (URL: %s)", title, *item.ProductURL)
-			}
-
-			customAttrs := []shopify.DraftOrderAttributeInput{
-				{Key: "product_url", Value: *item.ProductURL},
-			}
-			if item.ProductURL == nil {
-				customAttrs = []shopify.DraftOrderAttributeInput{}
-			}
-
-			lineItems = append(lineItems, shopify.DraftOrderLineItemInput{
-				Title:             &title,
-				OriginalUnitPrice: &priceStr,
-				Quantity:          item.Quantity,
-				CustomAttributes:  customAttrs,
-			})
-		}
-	}
-
-	// Build shipping address
-	shippingAddr := shopify.DraftOrderAddressInput{
-		Address1: getStringFromMap(order.ShippingAddress, "street"),
-		City:     getStringFromMap(order.ShippingAddress, "city"),
-		Zip:      getStringFromMap(order.ShippingAddress, "postal_code"),
-		Country:  getStringFromMap(order.ShippingAddress, "country"),
-	}
-
-	// Parse customer name (assume "FirstName LastName" or just "Name")
-	nameParts := strings.Fields(order.CustomerName)
-	if len(nameParts) > 0 {
-		shippingAddr.FirstName = nameParts[0]
-		if len(nameParts) > 1 {
-			lastName := strings.Join(nameParts[1:], " ")
-			shippingAddr.LastName = &lastName
-		}
-	}
-
-	if state, ok := order.ShippingAddress["state"].(string); ok && state != "" {
-		shippingAddr.Province = &state
-	}
-
-	if order.CustomerPhone != "" {
-		shippingAddr.Phone = &order.CustomerPhone
-	}
+	lineItems := buildDraftOrderLineItems(items)
+	shippingAddr := buildDraftOrderShippingAddress(order)
 
 	// Build tags
 	tags := []string{
@@ -620,9 +645,7 @@ func (s *shopifyService) CreateDraftOrder(
 
 	var useExistingCustomer bool
 	if existingCustomerID != nil && existingCustomerPhone != nil && order.CustomerPhone != "" {
-		reqNorm := normalizePhoneForComparison(order.CustomerPhone)
-		existingNorm := normalizePhoneForComparison(*existingCustomerPhone)
-		useExistingCustomer = (reqNorm != "" && existingNorm != "" && reqNorm == existingNorm)
+		useExistingCustomer = phone.Equal(order.CustomerPhone, *existingCustomerPhone)
 	}
 
 	var customerIDToUse *string
@@ -644,15 +667,10 @@ func (s *shopifyService) CreateDraftOrder(
 	// Generated email from phone: used only when we don't have a customer ID (so Shopify creates/find by this email; we never use request email so matching is by phone only)
 	var customerEmail *string
 	if customerIDToUse == nil && order.CustomerPhone != "" {
-		phoneDigits := strings.ReplaceAll(order.CustomerPhone, " ", "")
-		phoneDigits = strings.ReplaceAll(phoneDigits, "-", "")
-		phoneDigits = strings.ReplaceAll(phoneDigits, "(", "")
-		phoneDigits = strings.ReplaceAll(phoneDigits, ")", "")
-		if len(phoneDigits) > 10 {
-			phoneDigits = phoneDigits[len(phoneDigits)-10:]
-		}
-		if phoneDigits != "" {
-			email := fmt.Sprintf("phone-%s@example.com", phoneDigits)
+		// E.164 digits, e.g. phone-962778888888@example.com. Customers created before phones were stored in E.164 have
+		// the local form (phone-0778888888@example.com) and are not matched by this email.
+		if number, err := phone.Parse(order.CustomerPhone); err == nil {
+			email := fmt.Sprintf("phone-%s@example.com", phone.Digits(number))
 			customerEmail = &email
 			s.logger.Info("Using generated email from phone for new Shopify customer", zap.String("email", email), zap.String("phone", order.CustomerPhone))
 		}
@@ -674,7 +692,7 @@ func (s *shopifyService) CreateDraftOrder(
 	// Build input
 	input := shopify.DraftOrderInput{
 		LineItems:       lineItems,
-		ShippingAddress: &shippingAddr,
+		ShippingAddress: shippingAddr,
 		Tags:            tags,
 		Note:            stringPtr(order.PartnerOrderID),
 		Metafields: []shopify.MetafieldInput{
@@ -682,6 +700,10 @@ func (s *shopifyService) CreateDraftOrder(
 		},
 	}
 
+	if order.DeliveryWindow != nil {
+		input.CustomAttributes = deliveryWindowAttributes(order.DeliveryWindow)
+	}
+
 	if customerIDToUse != nil {
 		input.CustomerID = customerIDToUse
 	} else if customerEmail != nil {
@@ -731,6 +753,99 @@ func (s *shopifyService) CreateDraftOrder(
 	return draftOrderID, nil
 }
 
+// buildDraftOrderLineItems builds draft order line items: supplier items by variant, others as custom line items.
+func buildDraftOrderLineItems(items []*domain.SupplierOrderItem) []shopify.DraftOrderLineItemInput {
+	lineItems := make([]shopify.DraftOrderLineItemInput, 0, len(items))
+	for _, item := range items {
+		if item.IsSupplierItem && item.ShopifyVariantID != nil {
+			// Supplier item - use variant
+			variantIDStr := fmt.Sprintf("gid://shopify/ProductVariant/%d", *item.ShopifyVariantID)
+			lineItems = append(lineItems, shopify.DraftOrderLineItemInput{
+				VariantID:       &variantIDStr,
+				Quantity:        item.Quantity,
+				AppliedDiscount: wholesaleDiscount(item),
+			})
+			continue
+		}
+		// Non-supplier item - use custom line item
+		priceStr := shopify.NewMoneyInput(item.Price, shopCurrencyCode).Amount
+		title := customLineItemTitle(item)
+		customAttrs := []shopify.DraftOrderAttributeInput{}
+		if item.ProductURL != nil {
+			customAttrs = append(customAttrs, shopify.DraftOrderAttributeInput{Key: "product_url", Value: *item.ProductURL})
+		}
+		lineItems = append(lineItems, shopify.DraftOrderLineItemInput{
+			Title:             &title,
+			OriginalUnitPrice: &priceStr,
+			Quantity:          item.Quantity,
+			CustomAttributes:  customAttrs,
+		})
+	}
+	return lineItems
+}
+
+// wholesaleDiscount is the per-unit discount that brings a supplier item from its Shopify price down to the
+// partner's agreed price. nil when the item was not priced from a price list or the agreed price is not lower.
+func wholesaleDiscount(item *domain.SupplierOrderItem) *shopify.DraftOrderAppliedDiscountInput {
+	amount, ok := wholesaleDiscountAmount(item)
+	if !ok {
+		return nil
+	}
+	return &shopify.DraftOrderAppliedDiscountInput{
+		Title:     wholesaleDiscountTitle,
+		Value:     amount,
+		ValueType: "FIXED_AMOUNT",
+	}
+}
+
+// wholesaleDiscountTitle labels the wholesale price discount on Shopify line items.
+const wholesaleDiscountTitle = "Wholesale price"
+
+func wholesaleDiscountAmount(item *domain.SupplierOrderItem) (domain.Money, bool) {
+	if item.ListPrice == nil || item.WholesalePrice == nil {
+		return domain.Money{}, false
+	}
+	amount := item.ListPrice.Sub(*item.WholesalePrice)
+	return amount, amount.Cmp(domain.Money{}) > 0
+}
+
+// customLineItemTitle is the Shopify title used for non-supplier items (also used to match them when editing an order).
+func customLineItemTitle(item *domain.SupplierOrderItem) string {
+	if item.ProductURL != nil {
+		return fmt.Sprintf("%s (URL: %s)", item.Title, *item.ProductURL)
+	}
+	return item.Title
+}
+
+// buildDraftOrderShippingAddress builds the draft order shipping address from the order's shipping_address and customer.
+func buildDraftOrderShippingAddress(order *domain.SupplierOrder) *shopify.DraftOrderAddressInput {
+	shippingAddr := shopify.DraftOrderAddressInput{
+		Address1: getStringFromMap(order.ShippingAddress, "street"),
+		City:     getStringFromMap(order.ShippingAddress, "city"),
+		Zip:      getStringFromMap(order.ShippingAddress, "postal_code"),
+		Country:  getStringFromMap(order.ShippingAddress, "country"),
+	}
+
+	// Parse customer name (assume "FirstName LastName" or just "Name")
+	nameParts := strings.Fields(order.CustomerName)
+	if len(nameParts) > 0 {
+		shippingAddr.FirstName = nameParts[0]
+		if len(nameParts) > 1 {
+			lastName := strings.Join(nameParts[1:], " ")
+			shippingAddr.LastName = &lastName
+		}
+	}
+
+	if state, ok := order.ShippingAddress["state"].(string); ok && state != "" {
+		shippingAddr.Province = &state
+	}
+
+	if order.CustomerPhone != "" {
+		shippingAddr.Phone = &order.CustomerPhone
+	}
+	return &shippingAddr
+}
+
 // Helper functions
 func getStringFromMap(m map[string]interface{}, key string) string {
 	if val, ok := m[key].(string); ok {
@@ -757,18 +872,3 @@ func extractIDFromGID(gid string) (int64, error) {
 
 	return id, nil
 }
-
-// normalizePhoneForComparison strips non-digits and takes the last 10 digits so "0778888888", "+962 77 888 8888", "00962778888888" match.
-func normalizePhoneForComparison(phone string) string {
-	var digits strings.Builder
-	for _, r := range phone {
-		if r >= '0' && r <= '9' {
-			digits.WriteRune(r)
-		}
-	}
-	s := digits.String()
-	if len(s) > 10 {
-		return s[len(s)-10:]
-	}
-	return s
-}
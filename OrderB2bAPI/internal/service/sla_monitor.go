@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+const (
+	// slaScanPageSize is how many orders are read per query while scanning
+	slaScanPageSize = 500
+	// slaAlertBatchSize caps the breaches sent in one staff alert
+	slaAlertBatchSize = 100
+)
+
+var slaMonitorMu sync.Mutex
+
+// slaShippedStatuses are the order statuses whose Wassel delivery status is checked.
+var slaShippedStatuses = []domain.OrderStatus{domain.OrderStatusFulfilled, domain.OrderStatusPartiallyFulfilled}
+
+// SLACheckResult summarizes one SLA monitor scan.
+type SLACheckResult struct {
+	Breaching int // orders past a threshold
+	Opened    int
+	Resolved  int
+	Alerted   int
+}
+
+// slaPolicy is one partner's thresholds: the SLA_THRESHOLDS defaults with the partner's overrides applied.
+type slaPolicy struct {
+	statuses   map[domain.OrderStatus]time.Duration
+	deliveries map[int]time.Duration
+}
+
+func newSLAPolicy(cfg config.SLAConfig, overrides []*domain.PartnerSLAThreshold) slaPolicy {
+	policy := slaPolicy{
+		statuses:   make(map[domain.OrderStatus]time.Duration, len(cfg.StatusThresholds)),
+		deliveries: make(map[int]time.Duration, len(cfg.DeliveryThresholds)),
+	}
+	for status, d := range cfg.StatusThresholds {
+		policy.statuses[status] = d
+	}
+	for code, d := range cfg.DeliveryThresholds {
+		policy.deliveries[code] = d
+	}
+	for _, t := range overrides {
+		d := time.Duration(t.MaxMinutes) * time.Minute
+		if t.OrderStatus != nil {
+			policy.statuses[*t.OrderStatus] = d
+		} else if t.DeliveryStatus != nil {
+			policy.deliveries[*t.DeliveryStatus] = d
+		}
+	}
+	return policy
+}
+
+// slaKey identifies a breach: the order, the status it is stuck in and when it entered it. An order that leaves the
+// status and comes back gets a new breach.
+type slaKey struct {
+	orderID        uuid.UUID
+	orderStatus    domain.OrderStatus
+	deliveryStatus int // 0 for order status breaches
+	since          int64
+}
+
+func slaKeyOf(b *domain.SLABreach) slaKey {
+	key := slaKey{orderID: b.SupplierOrderID, orderStatus: b.OrderStatus, since: b.Since.UnixMicro()}
+	if b.DeliveryStatus != nil {
+		key.deliveryStatus = *b.DeliveryStatus
+	}
+	return key
+}
+
+// RunSLACheckOnce scans every partner's orders against its SLA thresholds. Orders past a threshold get a breach
+// record; open breaches whose order has moved on (or is no longer past its threshold) are resolved. Breaches not yet
+// sent to staff are then POSTed to STAFF_ALERT_WEBHOOK_URL, and retried on the next scan until the webhook succeeds.
+func RunSLACheckOnce(ctx context.Context, cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) (*SLACheckResult, error) {
+	now := time.Now()
+	partners, err := repos.Partner.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list partners: %w", err)
+	}
+	thresholds, err := repos.PartnerSLAThreshold.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list partner SLA thresholds: %w", err)
+	}
+	overrides := make(map[uuid.UUID][]*domain.PartnerSLAThreshold)
+	for _, t := range thresholds {
+		overrides[t.PartnerID] = append(overrides[t.PartnerID], t)
+	}
+
+	// Orders past a threshold now. Any scan error stops the check, so open breaches are never resolved from a
+	// partial scan.
+	found := make(map[slaKey]*domain.SLABreach)
+	partnerNames := make(map[uuid.UUID]string, len(partners))
+	for _, p := range partners {
+		partnerNames[p.ID] = p.Name
+		policy := newSLAPolicy(cfg.SLA, overrides[p.ID])
+
+		for status, d := range policy.statuses {
+			if d <= 0 {
+				continue
+			}
+			before := now.Add(-d)
+			filter := repository.OrderListFilter{
+				PartnerID:           &p.ID,
+				Statuses:            []domain.OrderStatus{status},
+				StatusChangedBefore: &before,
+			}
+			err := forEachOrder(ctx, repos, filter, func(order *domain.SupplierOrder) {
+				b := newSLABreach(order, order.StatusChangedAt, d, now)
+				found[slaKeyOf(b)] = b
+			})
+			if err != nil {
+				return nil, fmt.Errorf("scan %s orders of partner %s: %w", status, p.ID, err)
+			}
+		}
+
+		for code, d := range policy.deliveries {
+			if d <= 0 {
+				continue
+			}
+			code := code
+			before := now.Add(-d)
+			filter := repository.OrderListFilter{
+				PartnerID:          &p.ID,
+				Statuses:           slaShippedStatuses,
+				DeliveryStatus:     &code,
+				LastDeliveryBefore: &before,
+			}
+			err := forEachOrder(ctx, repos, filter, func(order *domain.SupplierOrder) {
+				b := newSLABreach(order, *order.LastDeliveryAt, d, now)
+				b.DeliveryStatus = &code
+				found[slaKeyOf(b)] = b
+			})
+			if err != nil {
+				return nil, fmt.Errorf("scan delivery status %d orders of partner %s: %w", code, p.ID, err)
+			}
+		}
+	}
+
+	result := &SLACheckResult{Breaching: len(found)}
+	open, err := repos.SLABreach.ListOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list open SLA breaches: %w", err)
+	}
+	var unalerted []*domain.SLABreach
+	for _, b := range open {
+		key := slaKeyOf(b)
+		if _, ok := found[key]; ok {
+			delete(found, key)
+			if b.AlertedAt == nil {
+				unalerted = append(unalerted, b)
+			}
+			continue
+		}
+		if err := repos.SLABreach.Resolve(ctx, b.ID, now); err != nil {
+			return result, fmt.Errorf("resolve SLA breach %s: %w", b.ID, err)
+		}
+		result.Resolved++
+	}
+
+	opened := make([]*domain.SLABreach, 0, len(found))
+	for _, b := range found {
+		opened = append(opened, b)
+	}
+	sort.Slice(opened, func(i, j int) bool { return opened[i].Since.Before(opened[j].Since) })
+	for _, b := range opened {
+		created, err := repos.SLABreach.Create(ctx, b)
+		if err != nil {
+			return result, fmt.Errorf("create SLA breach for order %s: %w", b.SupplierOrderID, err)
+		}
+		if !created {
+			// Recorded meanwhile by another instance, which alerts it
+			continue
+		}
+		result.Opened++
+		unalerted = append(unalerted, b)
+	}
+
+	result.Alerted = alertSLABreaches(ctx, cfg.SLA.AlertWebhookURL, repos, unalerted, partnerNames, now, logger)
+	return result, nil
+}
+
+func newSLABreach(order *domain.SupplierOrder, since time.Time, threshold time.Duration, now time.Time) *domain.SLABreach {
+	return &domain.SLABreach{
+		SupplierOrderID:  order.ID,
+		PartnerID:        order.PartnerID,
+		PartnerOrderID:   order.PartnerOrderID,
+		OrderStatus:      order.Status,
+		Since:            since,
+		ThresholdMinutes: int(threshold / time.Minute),
+		DetectedAt:       now,
+	}
+}
+
+// forEachOrder calls fn for every order matching filter, reading them page by page.
+func forEachOrder(ctx context.Context, repos *repository.Repositories, filter repository.OrderListFilter, fn func(*domain.SupplierOrder)) error {
+	filter.Limit = slaScanPageSize
+	filter.SkipTotal = true
+	for {
+		page, err := repos.SupplierOrder.ListOrders(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, order := range page.Orders {
+			fn(order)
+		}
+		if page.Next == nil {
+			return nil
+		}
+		filter.After = page.Next
+	}
+}
+
+// SLABreachSummary describes a breach in a line of text, e.g. for staff alerts.
+func SLABreachSummary(b *domain.SLABreach, now time.Time) string {
+	stuck := now.Sub(b.Since).Truncate(time.Minute)
+	threshold := time.Duration(b.ThresholdMinutes) * time.Minute
+	if b.DeliveryStatus != nil {
+		return fmt.Sprintf("Order %s has been at delivery status %d for %s (SLA %s)",
+			b.PartnerOrderID, *b.DeliveryStatus, formatSLADuration(stuck), formatSLADuration(threshold))
+	}
+	return fmt.Sprintf("Order %s has been %s for %s (SLA %s)",
+		b.PartnerOrderID, b.OrderStatus, formatSLADuration(stuck), formatSLADuration(threshold))
+}
+
+// formatSLADuration prints whole hours and minutes (e.g. 80h15m, 45m).
+func formatSLADuration(d time.Duration) string {
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh%dm", hours, minutes)
+}
+
+// alertSLABreaches sends the breaches to the staff alert webhook in batches and marks the ones delivered. Returns
+// how many were alerted; without a webhook URL nothing is sent.
+func alertSLABreaches(ctx context.Context, webhookURL string, repos *repository.Repositories, breaches []*domain.SLABreach, partnerNames map[uuid.UUID]string, now time.Time, logger *zap.Logger) int {
+	if webhookURL == "" || len(breaches) == 0 {
+		return 0
+	}
+
+	alerted := 0
+	for start := 0; start < len(breaches); start += slaAlertBatchSize {
+		batch := breaches[start:min(start+slaAlertBatchSize, len(breaches))]
+		items := make([]map[string]interface{}, len(batch))
+		ids := make([]uuid.UUID, len(batch))
+		for i, b := range batch {
+			ids[i] = b.ID
+			items[i] = map[string]interface{}{
+				"breach_id":         b.ID.String(),
+				"supplier_order_id": b.SupplierOrderID.String(),
+				"partner_id":        b.PartnerID.String(),
+				"partner_name":      partnerNames[b.PartnerID],
+				"partner_order_id":  b.PartnerOrderID,
+				"order_status":      b.OrderStatus,
+				"delivery_status":   b.DeliveryStatus,
+				"since":             b.Since.UTC().Format(time.RFC3339),
+				"threshold_minutes": b.ThresholdMinutes,
+				"summary":           SLABreachSummary(b, now),
+			}
+		}
+		payload := map[string]interface{}{
+			"event":       "sla_breach",
+			"detected_at": now.UTC().Format(time.RFC3339),
+			"breaches":    items,
+		}
+		if _, err := postWebhook(webhookURL, payload); err != nil {
+			logger.Warn("SLA monitor: staff alert failed, will retry on the next scan",
+				zap.Int("breaches", len(breaches)-start), zap.Error(err))
+			return alerted
+		}
+		if err := repos.SLABreach.MarkAlerted(ctx, ids, now); err != nil {
+			logger.Error("SLA monitor: failed to mark breaches alerted", zap.Error(err))
+			return alerted
+		}
+		alerted += len(batch)
+	}
+	return alerted
+}
+
+// RunSLAMonitorLoop runs the SLA check once, then every SLA_CHECK_INTERVAL_MINUTES. Call from a goroutine; it
+// returns at once when the monitor is disabled.
+func RunSLAMonitorLoop(ctx context.Context, cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) {
+	if cfg.SLA.CheckInterval <= 0 {
+		return
+	}
+	run := func() {
+		slaMonitorMu.Lock()
+		defer slaMonitorMu.Unlock()
+		result, err := RunSLACheckOnce(ctx, cfg, repos, logger)
+		if err != nil {
+			logger.Error("SLA monitor: check failed", zap.Error(err))
+			return
+		}
+		if result.Opened > 0 || result.Resolved > 0 || result.Alerted > 0 {
+			logger.Info("SLA monitor: check finished",
+				zap.Int("breaching", result.Breaching),
+				zap.Int("opened", result.Opened),
+				zap.Int("resolved", result.Resolved),
+				zap.Int("alerted", result.Alerted))
+		}
+	}
+
+	run()
+	ticker := time.NewTicker(cfg.SLA.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Item availability statuses returned by CheckCartStock
+const (
+	StockInStock      = "in_stock"
+	StockInsufficient = "insufficient"
+	StockUntracked    = "untracked" // Shopify does not track the variant's inventory, or sells it when out of stock
+	StockUnknown      = "unknown"   // not one of our variants, or Shopify could not be reached
+)
+
+// variantsPerInventoryQuery is Shopify's limit on ids per nodes() call.
+const variantsPerInventoryQuery = 250
+
+// VariantStock is a variant's stock level in Shopify.
+type VariantStock struct {
+	Available int
+	Tracked   bool // false: Shopify does not track inventory for the variant
+	Backorder bool // inventoryPolicy CONTINUE: keeps selling when out of stock
+}
+
+// limited reports whether the variant can run out (tracked and not sold on backorder).
+func (v VariantStock) limited() bool {
+	return v.Tracked && !v.Backorder
+}
+
+// stockCache keeps Shopify stock levels for a short TTL so bursts of cart submissions do not query Shopify for
+// every cart. Quantities ordered since a level was fetched are reserved (subtracted) until it expires.
+type stockCache struct {
+	mu      sync.Mutex
+	entries map[int64]stockCacheEntry
+}
+
+type stockCacheEntry struct {
+	stock   VariantStock
+	expires time.Time
+}
+
+var variantStockCache = &stockCache{entries: make(map[int64]stockCacheEntry)}
+
+// get returns the cached levels for ids and the ids that are missing or expired.
+func (c *stockCache) get(ids []int64, now time.Time) (map[int64]VariantStock, []int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	found := make(map[int64]VariantStock, len(ids))
+	var missing []int64
+	for _, id := range ids {
+		if entry, ok := c.entries[id]; ok && now.Before(entry.expires) {
+			found[id] = entry.stock
+			continue
+		}
+		delete(c.entries, id)
+		missing = append(missing, id)
+	}
+	return found, missing
+}
+
+func (c *stockCache) put(stocks map[int64]VariantStock, ttl time.Duration, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, stock := range stocks {
+		c.entries[id] = stockCacheEntry{stock: stock, expires: now.Add(ttl)}
+	}
+}
+
+func (c *stockCache) reserve(quantities map[int64]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, qty := range quantities {
+		if entry, ok := c.entries[id]; ok {
+			entry.stock.Available -= qty
+			c.entries[id] = entry
+		}
+	}
+}
+
+// GetVariantStock fetches live stock levels for variant IDs. Variants Shopify does not return are left out.
+func (s *shopifyService) GetVariantStock(ctx context.Context, variantIDs []int64) (map[int64]VariantStock, error) {
+	stocks := make(map[int64]VariantStock, len(variantIDs))
+	for start := 0; start < len(variantIDs); start += variantsPerInventoryQuery {
+		end := start + variantsPerInventoryQuery
+		if end > len(variantIDs) {
+			end = len(variantIDs)
+		}
+		gids := make([]string, 0, end-start)
+		for _, id := range variantIDs[start:end] {
+			gids = append(gids, fmt.Sprintf("gid://shopify/ProductVariant/%d", id))
+		}
+
+		resp, err := s.client.Execute(shopify.VariantsInventoryQuery, map[string]interface{}{"ids": gids})
+		if err != nil {
+			return nil, fmt.Errorf("variants inventory: %w", err)
+		}
+		var result struct {
+			Nodes []*struct {
+				ID                string `json:"id"`
+				InventoryQuantity int    `json:"inventoryQuantity"`
+				InventoryPolicy   string `json:"inventoryPolicy"`
+				InventoryItem     struct {
+					Tracked bool `json:"tracked"`
+				} `json:"inventoryItem"`
+			} `json:"nodes"`
+		}
+		if err := json.Unmarshal(resp.Data, &result); err != nil {
+			return nil, fmt.Errorf("parse variants inventory response: %w", err)
+		}
+		for _, node := range result.Nodes {
+			if node == nil || node.ID == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(strings.TrimPrefix(node.ID, "gid://shopify/ProductVariant/"), 10, 64)
+			if err != nil {
+				continue
+			}
+			stocks[id] = VariantStock{
+				Available: node.InventoryQuantity,
+				Tracked:   node.InventoryItem.Tracked,
+				Backorder: node.InventoryPolicy == "CONTINUE",
+			}
+		}
+	}
+	return stocks, nil
+}
+
+// ItemAvailability is the stock check result for one cart item. Available is set for stock-limited variants.
+type ItemAvailability struct {
+	SKU       string `json:"sku"`
+	Requested int    `json:"requested"`
+	Available *int   `json:"available,omitempty"`
+	Status    string `json:"status"`
+}
+
+// StockCheck is the result of CheckCartStock.
+type StockCheck struct {
+	Policy     domain.StockPolicy
+	Items      []ItemAvailability // in cart order
+	HoldReason string             // set under the hold policy when an item is short
+	reserved   map[int64]int      // quantity per stock-limited variant, reserved once the order is created
+}
+
+// Insufficient returns the items whose quantity exceeds stock.
+func (c *StockCheck) Insufficient() []ItemAvailability {
+	var short []ItemAvailability
+	for _, item := range c.Items {
+		if item.Status == StockInsufficient {
+			short = append(short, item)
+		}
+	}
+	return short
+}
+
+// CheckCartStock checks the quantity of each of our items against its Shopify stock (cached for ttl). Quantities
+// of the same variant on several lines are added up. If Shopify cannot be reached the items are reported as
+// unknown and the cart goes through. Returns nil under the off policy.
+//
+// When an item is short: reject returns *errors.ErrValidation keyed by field, hold sets HoldReason, report only
+// reports it.
+func (s *shopifyService) CheckCartStock(
+	ctx context.Context,
+	items []CartItem,
+	supplierItems map[string]*domain.PartnerSKUMapping,
+	policy domain.StockPolicy,
+	ttl time.Duration,
+) (*StockCheck, error) {
+	if !policy.IsValid() {
+		policy = domain.StockPolicyReport
+	}
+	if policy == domain.StockPolicyOff {
+		return nil, nil
+	}
+
+	requested := make(map[int64]int)
+	var variantIDs []int64
+	for _, item := range items {
+		if mapping, ok := supplierItems[item.SKU]; ok && mapping.ShopifyVariantID != 0 {
+			if _, seen := requested[mapping.ShopifyVariantID]; !seen {
+				variantIDs = append(variantIDs, mapping.ShopifyVariantID)
+			}
+			requested[mapping.ShopifyVariantID] += item.Quantity
+		}
+	}
+
+	now := time.Now()
+	stocks, missing := variantStockCache.get(variantIDs, now)
+	if len(missing) > 0 {
+		fetched, err := s.GetVariantStock(ctx, missing)
+		if err != nil {
+			s.logger.Warn("Stock check: Shopify inventory lookup failed, reporting availability as unknown", zap.Error(err))
+		} else {
+			variantStockCache.put(fetched, ttl, now)
+			for id, stock := range fetched {
+				stocks[id] = stock
+			}
+		}
+	}
+
+	check := &StockCheck{Policy: policy, Items: make([]ItemAvailability, len(items)), reserved: make(map[int64]int)}
+	fields := make(map[string]string)
+	var short []string
+	for i, item := range items {
+		availability := ItemAvailability{SKU: item.SKU, Requested: item.Quantity, Status: StockUnknown}
+		if mapping, ok := supplierItems[item.SKU]; ok {
+			if stock, ok := stocks[mapping.ShopifyVariantID]; ok {
+				switch {
+				case !stock.limited():
+					availability.Status = StockUntracked
+				case requested[mapping.ShopifyVariantID] > stock.Available:
+					availability.Status = StockInsufficient
+				default:
+					availability.Status = StockInStock
+				}
+				if stock.limited() {
+					available := stock.Available
+					if available < 0 {
+						available = 0
+					}
+					availability.Available = &available
+					check.reserved[mapping.ShopifyVariantID] = requested[mapping.ShopifyVariantID]
+				}
+			}
+		}
+		if availability.Status == StockInsufficient {
+			fields[fmt.Sprintf("items[%d].quantity", i)] = fmt.Sprintf("only %d in stock", *availability.Available)
+			short = append(short, fmt.Sprintf("%s (requested %d, available %d)", item.SKU, item.Quantity, *availability.Available))
+		}
+		check.Items[i] = availability
+	}
+
+	if len(short) == 0 {
+		return check, nil
+	}
+	switch policy {
+	case domain.StockPolicyReject:
+		return check, &errors.ErrValidation{Message: "insufficient stock", Fields: fields}
+	case domain.StockPolicyHold:
+		check.HoldReason = "Insufficient stock: " + strings.Join(short, ", ")
+	}
+	return check, nil
+}
+
+// ReserveStock subtracts the order's quantities from the cached stock levels so later carts within the cache TTL
+// see the reduced stock.
+func ReserveStock(check *StockCheck) {
+	if check == nil {
+		return
+	}
+	variantStockCache.reserve(check.reserved)
+}
+
+// RecordStockCheck holds the order (hold policy) and logs a stock_shortage event when an item was short.
+func (s *orderService) RecordStockCheck(ctx context.Context, order *domain.SupplierOrder, check *StockCheck) error {
+	if check == nil {
+		return nil
+	}
+	short := check.Insufficient()
+	if len(short) == 0 {
+		return nil
+	}
+
+	if check.HoldReason != "" {
+		if err := s.repos.SupplierOrder.UpdateHoldReason(ctx, order.ID, &check.HoldReason); err != nil {
+			return err
+		}
+		order.HoldReason = &check.HoldReason
+	}
+
+	items := make([]map[string]interface{}, len(short))
+	for i, item := range short {
+		items[i] = map[string]interface{}{
+			"sku":       item.SKU,
+			"requested": item.Requested,
+			"available": *item.Available,
+		}
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       domain.OrderEventStockShortage,
+		EventData: map[string]interface{}{
+			"policy": check.Policy,
+			"held":   check.HoldReason != "",
+			"items":  items,
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+	return nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -14,33 +15,38 @@ const webhookTimeout = 10 * time.Second
 // NotifyDeliveryUpdate sends a delivery update payload to the partner's webhook URL.
 // It is intended to be called in a goroutine so the API response is not blocked.
 // Payload should include: partner_id, order_id (optional), partner_order_id (optional),
-// shipping_address, shipment, and optionally event ("delivery_status" or "order_shipped").
+// shipping_address, shipment, and optionally event ("delivery_status", "order_shipped" or "order_canceled").
 func NotifyDeliveryUpdate(webhookURL string, payload map[string]interface{}, logger *zap.Logger) {
 	if webhookURL == "" {
 		return
 	}
-	body, err := json.Marshal(payload)
+	status, err := postWebhook(webhookURL, payload)
 	if err != nil {
-		logger.Warn("Webhook: failed to marshal delivery payload", zap.Error(err))
+		logger.Warn("Webhook: delivery notification failed", zap.String("url", webhookURL), zap.Error(err))
 		return
 	}
+	logger.Info("Webhook: delivery notification sent", zap.String("url", webhookURL), zap.Int("status", status))
+}
+
+// postWebhook POSTs payload as JSON to webhookURL and returns the response status. A non-2xx response is an error.
+func postWebhook(webhookURL string, payload interface{}) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
 	client := &http.Client{Timeout: webhookTimeout}
 	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body))
 	if err != nil {
-		logger.Warn("Webhook: failed to create request", zap.String("url", webhookURL), zap.Error(err))
-		return
+		return 0, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := client.Do(req)
 	if err != nil {
-		logger.Warn("Webhook: delivery notification request failed", zap.String("url", webhookURL), zap.Error(err))
-		return
+		return 0, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		logger.Warn("Webhook: delivery notification returned non-2xx",
-			zap.String("url", webhookURL), zap.Int("status", resp.StatusCode))
-		return
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
 	}
-	logger.Info("Webhook: delivery notification sent", zap.String("url", webhookURL), zap.Int("status", resp.StatusCode))
+	return resp.StatusCode, nil
 }
@@ -1,5 +1,7 @@
 package shopify
 
+import "github.com/jafarshop/b2bapi/internal/domain"
+
 // DraftOrderCreateMutation creates a draft order
 const DraftOrderCreateMutation = `
 mutation draftOrderCreate($input: DraftOrderInput!) {
@@ -55,6 +57,223 @@ mutation metafieldsSet($metafields: [MetafieldsSetInput!]!) {
 }
 `
 
+// OrderCancelMutation cancels a completed Shopify order (asynchronous job; we only check userErrors).
+const OrderCancelMutation = `
+mutation orderCancel($orderId: ID!, $reason: OrderCancelReason!, $refund: Boolean!, $restock: Boolean!, $notifyCustomer: Boolean, $staffNote: String) {
+  orderCancel(orderId: $orderId, reason: $reason, refund: $refund, restock: $restock, notifyCustomer: $notifyCustomer, staffNote: $staffNote) {
+    job {
+      id
+      done
+    }
+    orderCancelUserErrors {
+      field
+      message
+      code
+    }
+  }
+}
+`
+
+// DraftOrderDeleteMutation deletes a draft order that was never completed.
+const DraftOrderDeleteMutation = `
+mutation draftOrderDelete($input: DraftOrderDeleteInput!) {
+  draftOrderDelete(input: $input) {
+    deletedId
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// DraftOrderUpdateMutation replaces a draft order's line items / shipping address (used before the draft is completed).
+const DraftOrderUpdateMutation = `
+mutation draftOrderUpdate($id: ID!, $input: DraftOrderInput!) {
+  draftOrderUpdate(id: $id, input: $input) {
+    draftOrder {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// OrderEditBeginMutation starts an order edit and returns the calculated order with its current line items.
+const OrderEditBeginMutation = `
+mutation orderEditBegin($id: ID!) {
+  orderEditBegin(id: $id) {
+    calculatedOrder {
+      id
+      lineItems(first: 250) {
+        edges {
+          node {
+            id
+            title
+            quantity
+            variant {
+              id
+            }
+          }
+        }
+      }
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// OrderEditSetQuantityMutation sets a line item quantity on a calculated order (0 removes it).
+const OrderEditSetQuantityMutation = `
+mutation orderEditSetQuantity($id: ID!, $lineItemId: ID!, $quantity: Int!, $restock: Boolean) {
+  orderEditSetQuantity(id: $id, lineItemId: $lineItemId, quantity: $quantity, restock: $restock) {
+    calculatedOrder {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// OrderEditAddVariantMutation adds a variant to a calculated order.
+const OrderEditAddVariantMutation = `
+mutation orderEditAddVariant($id: ID!, $variantId: ID!, $quantity: Int!) {
+  orderEditAddVariant(id: $id, variantId: $variantId, quantity: $quantity) {
+    calculatedLineItem {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// OrderEditAddCustomItemMutation adds a custom (non-variant) line item to a calculated order.
+const OrderEditAddCustomItemMutation = `
+mutation orderEditAddCustomItem($id: ID!, $title: String!, $price: MoneyInput!, $quantity: Int!) {
+  orderEditAddCustomItem(id: $id, title: $title, price: $price, quantity: $quantity) {
+    calculatedLineItem {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// OrderEditAddLineItemDiscountMutation adds a discount to a line item added in the order edit.
+const OrderEditAddLineItemDiscountMutation = `
+mutation orderEditAddLineItemDiscount($id: ID!, $lineItemId: ID!, $discount: OrderEditAppliedDiscountInput!) {
+  orderEditAddLineItemDiscount(id: $id, lineItemId: $lineItemId, discount: $discount) {
+    calculatedLineItem {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// OrderEditCommitMutation applies the staged order edit to the order.
+const OrderEditCommitMutation = `
+mutation orderEditCommit($id: ID!, $notifyCustomer: Boolean, $staffNote: String) {
+  orderEditCommit(id: $id, notifyCustomer: $notifyCustomer, staffNote: $staffNote) {
+    order {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// OrderUpdateMutation updates order fields that are not part of an order edit (e.g. shipping address).
+const OrderUpdateMutation = `
+mutation orderUpdate($input: OrderInput!) {
+  orderUpdate(input: $input) {
+    order {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// OrderMarkAsPaidMutation marks an order as paid by recording a manual payment for its outstanding balance.
+const OrderMarkAsPaidMutation = `
+mutation orderMarkAsPaid($input: OrderMarkAsPaidInput!) {
+  orderMarkAsPaid(input: $input) {
+    order {
+      id
+      displayFinancialStatus
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// RefundCreateMutation creates a refund. With refundLineItems (restockType RETURN) and no transactions it
+// restocks returned items without moving money.
+const RefundCreateMutation = `
+mutation refundCreate($input: RefundInput!) {
+  refundCreate(input: $input) {
+    refund {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// RefundLineItemInput is a line item (and quantity) to refund/restock.
+type RefundLineItemInput struct {
+	LineItemID  string `json:"lineItemId"`
+	Quantity    int    `json:"quantity"`
+	RestockType string `json:"restockType"`
+}
+
+// MoneyInput is used for custom line item prices in order edits.
+type MoneyInput struct {
+	Amount       string `json:"amount"`
+	CurrencyCode string `json:"currencyCode"`
+}
+
+// NewMoneyInput formats amount with its currency's decimals. Amounts without a currency are taken to be in
+// currencyCode.
+func NewMoneyInput(amount domain.Money, currencyCode string) MoneyInput {
+	if amount.Currency() != "" {
+		currencyCode = amount.Currency()
+	}
+	amount = amount.In(currencyCode)
+	return MoneyInput{Amount: amount.String(), CurrencyCode: currencyCode}
+}
+
 // DraftOrderInput represents the input for creating a draft order
 type DraftOrderInput struct {
 	LineItems       []DraftOrderLineItemInput `json:"lineItems"`
@@ -82,6 +301,15 @@ type DraftOrderLineItemInput struct {
 	OriginalUnitPrice *string `json:"originalUnitPrice,omitempty"`
 	Quantity     int      `json:"quantity"`
 	CustomAttributes []DraftOrderAttributeInput `json:"customAttributes,omitempty"`
+	// Per-unit discount off the variant price (e.g. a partner's agreed wholesale price).
+	AppliedDiscount *DraftOrderAppliedDiscountInput `json:"appliedDiscount,omitempty"`
+}
+
+// DraftOrderAppliedDiscountInput is a discount on a draft order line item. ValueType is FIXED_AMOUNT or PERCENTAGE.
+type DraftOrderAppliedDiscountInput struct {
+	Title     string       `json:"title,omitempty"`
+	Value     domain.Money `json:"value"`
+	ValueType string       `json:"valueType"`
 }
 
 type DraftOrderAddressInput struct {
@@ -1,6 +1,6 @@
 package shopify
 
-// CustomersByPhoneQueryTemplate finds customers by phone (query string is e.g. "phone:0778888888")
+// CustomersByPhoneQueryTemplate finds customers by phone (query string is e.g. "phone:+962778888888")
 const CustomersByPhoneQueryTemplate = `
 query getCustomersByPhone {
   customers(first: 1, query: "%s") {
@@ -206,3 +206,41 @@ query getOrderByID($id: ID!) {
   }
 }
 `
+
+// OrderLineItemsQuery fetches an order's line items with how many can still be refunded (used to restock returns).
+const OrderLineItemsQuery = `
+query getOrderLineItems($id: ID!) {
+  order(id: $id) {
+    id
+    lineItems(first: 250) {
+      edges {
+        node {
+          id
+          title
+          quantity
+          refundableQuantity
+          variant {
+            id
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// VariantsInventoryQuery fetches stock levels for variants by GID (at most 250 ids per call).
+const VariantsInventoryQuery = `
+query variantsInventory($ids: [ID!]!) {
+  nodes(ids: $ids) {
+    ... on ProductVariant {
+      id
+      inventoryQuantity
+      inventoryPolicy
+      inventoryItem {
+        tracked
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_supplier_staff_updated_at ON supplier_staff;
+DROP TABLE IF EXISTS supplier_staff;
@@ -0,0 +1,18 @@
+-- Supplier staff (warehouse team) identities, separate from partners.
+-- Staff can confirm, reject and ship orders across all partners via /v1/supplier.
+CREATE TABLE IF NOT EXISTS supplier_staff (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    api_key_hash VARCHAR(255) NOT NULL UNIQUE,
+    api_key_lookup VARCHAR(64) NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_staff_api_key_lookup ON supplier_staff(api_key_lookup);
+CREATE INDEX IF NOT EXISTS idx_supplier_staff_is_active ON supplier_staff(is_active);
+
+CREATE TRIGGER update_supplier_staff_updated_at BEFORE UPDATE ON supplier_staff
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
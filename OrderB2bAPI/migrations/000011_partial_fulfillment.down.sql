@@ -0,0 +1,3 @@
+UPDATE supplier_orders SET status = 'FULFILLED' WHERE status = 'PARTIALLY_FULFILLED';
+DROP TABLE IF EXISTS supplier_order_item_fulfillments;
+ALTER TABLE supplier_order_items DROP COLUMN IF EXISTS fulfilled_quantity;
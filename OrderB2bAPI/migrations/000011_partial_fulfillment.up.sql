@@ -0,0 +1,19 @@
+-- Per-item fulfilled quantity (sum of supplier_order_item_fulfillments.quantity)
+ALTER TABLE supplier_order_items ADD COLUMN fulfilled_quantity INTEGER NOT NULL DEFAULT 0;
+
+-- Which items shipped under which Shopify fulfillment / tracking number
+CREATE TABLE supplier_order_item_fulfillments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    supplier_order_id UUID NOT NULL REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    supplier_order_item_id UUID NOT NULL REFERENCES supplier_order_items(id) ON DELETE CASCADE,
+    shopify_fulfillment_id BIGINT NOT NULL,
+    quantity INTEGER NOT NULL,
+    tracking_carrier VARCHAR(100),
+    tracking_number VARCHAR(255),
+    tracking_url VARCHAR(500),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(shopify_fulfillment_id, supplier_order_item_id)
+);
+
+CREATE INDEX idx_supplier_order_item_fulfillments_order_id ON supplier_order_item_fulfillments(supplier_order_id);
+CREATE INDEX idx_supplier_order_item_fulfillments_item_id ON supplier_order_item_fulfillments(supplier_order_item_id);
@@ -0,0 +1,8 @@
+-- Put orders in a return state back to the status they had when their return was opened
+UPDATE supplier_orders o SET status = r.previous_order_status
+FROM order_returns r
+WHERE r.supplier_order_id = o.id AND o.status IN ('RETURN_IN_PROGRESS', 'RETURNED');
+
+DROP TRIGGER IF EXISTS update_order_returns_updated_at ON order_returns;
+DROP TABLE IF EXISTS order_return_items;
+DROP TABLE IF EXISTS order_returns;
@@ -0,0 +1,36 @@
+-- Returns (RMA): opened by Wassel return statuses (180, 190, 210) or by partners, decided by supplier staff.
+CREATE TABLE IF NOT EXISTS order_returns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    supplier_order_id UUID NOT NULL REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL DEFAULT 'REQUESTED',
+    source VARCHAR(50) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    note TEXT,
+    wassel_status INTEGER,
+    previous_order_status VARCHAR(50) NOT NULL,
+    restock BOOLEAN NOT NULL DEFAULT false,
+    decision_note TEXT,
+    decided_by_staff_id UUID REFERENCES supplier_staff(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_returns_supplier_order_id ON order_returns(supplier_order_id);
+CREATE INDEX IF NOT EXISTS idx_order_returns_status ON order_returns(status);
+
+-- At most one open (REQUESTED) return per order
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_returns_one_open_per_order ON order_returns(supplier_order_id) WHERE status = 'REQUESTED';
+
+CREATE TABLE IF NOT EXISTS order_return_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_return_id UUID NOT NULL REFERENCES order_returns(id) ON DELETE CASCADE,
+    supplier_order_item_id UUID NOT NULL REFERENCES supplier_order_items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_return_items_order_return_id ON order_return_items(order_return_id);
+
+CREATE TRIGGER update_order_returns_updated_at BEFORE UPDATE ON order_returns
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
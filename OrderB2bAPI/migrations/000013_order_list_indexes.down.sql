@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_supplier_orders_last_delivery_status;
+DROP INDEX IF EXISTS idx_supplier_orders_customer_phone;
+DROP INDEX IF EXISTS idx_supplier_orders_partner_created_at_id;
+DROP INDEX IF EXISTS idx_supplier_orders_created_at_id;
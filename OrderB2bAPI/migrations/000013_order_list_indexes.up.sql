@@ -0,0 +1,5 @@
+-- Keyset pagination for order listings (newest first) and the filterable columns
+CREATE INDEX idx_supplier_orders_created_at_id ON supplier_orders(created_at DESC, id DESC);
+CREATE INDEX idx_supplier_orders_partner_created_at_id ON supplier_orders(partner_id, created_at DESC, id DESC);
+CREATE INDEX idx_supplier_orders_customer_phone ON supplier_orders(customer_phone);
+CREATE INDEX idx_supplier_orders_last_delivery_status ON supplier_orders(last_delivery_status);
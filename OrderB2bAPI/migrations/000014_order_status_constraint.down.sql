@@ -0,0 +1,5 @@
+-- Legacy rows are not restored: current names are valid for every build since 000004
+ALTER TABLE order_returns DROP CONSTRAINT IF EXISTS chk_order_returns_previous_order_status;
+ALTER TABLE supplier_orders DROP CONSTRAINT IF EXISTS chk_supplier_orders_status;
+-- Restore the default 000004 set (000013 left it unchanged)
+ALTER TABLE supplier_orders ALTER COLUMN status SET DEFAULT 'INCOMPLETE_CAUTION';
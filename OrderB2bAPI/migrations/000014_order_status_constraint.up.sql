@@ -0,0 +1,26 @@
+-- Rewrite any legacy status left behind (rows written by old builds after 000004) to the Shopify-aligned names
+UPDATE supplier_orders SET status = 'INCOMPLETE_CAUTION' WHERE status = 'PENDING_CONFIRMATION';
+UPDATE supplier_orders SET status = 'UNFULFILLED' WHERE status = 'CONFIRMED';
+UPDATE supplier_orders SET status = 'FULFILLED' WHERE status = 'SHIPPED';
+UPDATE supplier_orders SET status = 'COMPLETE' WHERE status = 'DELIVERED';
+UPDATE supplier_orders SET status = 'CANCELED' WHERE status = 'CANCELLED';
+
+-- Rejected returns restore previous_order_status, so it must hold current names too
+UPDATE order_returns SET previous_order_status = 'INCOMPLETE_CAUTION' WHERE previous_order_status = 'PENDING_CONFIRMATION';
+UPDATE order_returns SET previous_order_status = 'UNFULFILLED' WHERE previous_order_status = 'CONFIRMED';
+UPDATE order_returns SET previous_order_status = 'FULFILLED' WHERE previous_order_status = 'SHIPPED';
+UPDATE order_returns SET previous_order_status = 'COMPLETE' WHERE previous_order_status = 'DELIVERED';
+UPDATE order_returns SET previous_order_status = 'CANCELED' WHERE previous_order_status = 'CANCELLED';
+
+ALTER TABLE supplier_orders ALTER COLUMN status SET DEFAULT 'INCOMPLETE_CAUTION';
+
+-- Fails (and rolls back) if any other unknown status is still present; fix those rows by hand first
+ALTER TABLE supplier_orders ADD CONSTRAINT chk_supplier_orders_status CHECK (status IN (
+    'INCOMPLETE_CAUTION', 'UNFULFILLED', 'PARTIALLY_FULFILLED', 'FULFILLED', 'COMPLETE',
+    'REJECTED', 'CANCELED', 'RETURN_IN_PROGRESS', 'RETURNED', 'REFUNDED', 'ARCHIVED'
+));
+
+ALTER TABLE order_returns ADD CONSTRAINT chk_order_returns_previous_order_status CHECK (previous_order_status IN (
+    'INCOMPLETE_CAUTION', 'UNFULFILLED', 'PARTIALLY_FULFILLED', 'FULFILLED', 'COMPLETE',
+    'REJECTED', 'CANCELED', 'RETURN_IN_PROGRESS', 'RETURNED', 'REFUNDED', 'ARCHIVED'
+));
@@ -0,0 +1,13 @@
+-- Order notes: a message thread on an order between the partner and supplier staff.
+-- The full thread is mirrored into the Shopify order note after each new message.
+CREATE TABLE IF NOT EXISTS order_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    supplier_order_id UUID NOT NULL REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    author_type VARCHAR(20) NOT NULL CHECK (author_type IN ('partner', 'staff')),
+    partner_id UUID REFERENCES partners(id) ON DELETE SET NULL,
+    staff_id UUID REFERENCES supplier_staff(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_notes_supplier_order_id ON order_notes(supplier_order_id, created_at);
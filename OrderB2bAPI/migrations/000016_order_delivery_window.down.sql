@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_supplier_orders_delivery_date;
+ALTER TABLE supplier_orders DROP COLUMN IF EXISTS delivery_window_to;
+ALTER TABLE supplier_orders DROP COLUMN IF EXISTS delivery_window_from;
+ALTER TABLE supplier_orders DROP COLUMN IF EXISTS delivery_date;
@@ -0,0 +1,6 @@
+-- Partner-requested delivery date and optional time window (warehouse local time, HH:MM), for dispatch planning
+ALTER TABLE supplier_orders ADD COLUMN IF NOT EXISTS delivery_date DATE;
+ALTER TABLE supplier_orders ADD COLUMN IF NOT EXISTS delivery_window_from VARCHAR(5);
+ALTER TABLE supplier_orders ADD COLUMN IF NOT EXISTS delivery_window_to VARCHAR(5);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_orders_delivery_date ON supplier_orders(delivery_date) WHERE delivery_date IS NOT NULL;
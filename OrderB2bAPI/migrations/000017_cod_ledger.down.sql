@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cod_ledger_entries;
+DROP TABLE IF EXISTS cod_remittances;
@@ -0,0 +1,29 @@
+-- Cash-on-delivery ledger: cash Wassel collects on delivery, refunds owed for returned items, and Wassel remittances.
+CREATE TABLE IF NOT EXISTS cod_remittances (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reference VARCHAR(100) NOT NULL UNIQUE,
+    amount NUMERIC(14, 3) NOT NULL,
+    remitted_at TIMESTAMP NOT NULL,
+    note TEXT,
+    recorded_by_staff_id UUID REFERENCES supplier_staff(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS cod_ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    supplier_order_id UUID NOT NULL REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    order_return_id UUID REFERENCES order_returns(id) ON DELETE SET NULL,
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('collected', 'returned')),
+    amount NUMERIC(14, 3) NOT NULL CHECK (amount >= 0),
+    waybill VARCHAR(100),
+    remittance_id UUID REFERENCES cod_remittances(id) ON DELETE SET NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One collection per order, one refund per return (repeated Wassel webhooks are no-ops)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cod_ledger_collected_per_order ON cod_ledger_entries(supplier_order_id) WHERE entry_type = 'collected';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cod_ledger_returned_per_return ON cod_ledger_entries(order_return_id) WHERE entry_type = 'returned';
+CREATE INDEX IF NOT EXISTS idx_cod_ledger_partner_occurred_at ON cod_ledger_entries(partner_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_cod_ledger_waybill ON cod_ledger_entries(waybill) WHERE waybill IS NOT NULL;
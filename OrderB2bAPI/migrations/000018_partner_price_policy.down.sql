@@ -0,0 +1,2 @@
+ALTER TABLE partners DROP CONSTRAINT IF EXISTS chk_partners_price_policy;
+ALTER TABLE partners DROP COLUMN IF EXISTS price_policy;
@@ -0,0 +1,4 @@
+-- How cart submissions are handled when item prices or totals disagree with our synced Shopify prices:
+-- reject (422), flag (accept the partner's prices and record the discrepancy) or override (use our prices)
+ALTER TABLE partners ADD COLUMN IF NOT EXISTS price_policy VARCHAR(20) NOT NULL DEFAULT 'flag';
+ALTER TABLE partners ADD CONSTRAINT chk_partners_price_policy CHECK (price_policy IN ('reject', 'flag', 'override'));
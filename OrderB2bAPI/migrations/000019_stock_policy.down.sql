@@ -0,0 +1,3 @@
+ALTER TABLE supplier_orders DROP COLUMN IF EXISTS hold_reason;
+ALTER TABLE partners DROP CONSTRAINT IF EXISTS chk_partners_stock_policy;
+ALTER TABLE partners DROP COLUMN IF EXISTS stock_policy;
@@ -0,0 +1,7 @@
+-- Live Shopify stock check on cart submission: off, report (availability in the response only), hold (order kept
+-- in INCOMPLETE_CAUTION with hold_reason) or reject (422) when a quantity exceeds stock
+ALTER TABLE partners ADD COLUMN IF NOT EXISTS stock_policy VARCHAR(20) NOT NULL DEFAULT 'report';
+ALTER TABLE partners ADD CONSTRAINT chk_partners_stock_policy CHECK (stock_policy IN ('off', 'report', 'hold', 'reject'));
+
+-- Why an order is held for manual review (e.g. insufficient stock at submission)
+ALTER TABLE supplier_orders ADD COLUMN IF NOT EXISTS hold_reason TEXT;
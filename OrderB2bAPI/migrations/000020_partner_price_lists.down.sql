@@ -0,0 +1,3 @@
+ALTER TABLE supplier_order_items DROP COLUMN IF EXISTS wholesale_price;
+ALTER TABLE supplier_order_items DROP COLUMN IF EXISTS list_price;
+DROP TABLE IF EXISTS partner_prices;
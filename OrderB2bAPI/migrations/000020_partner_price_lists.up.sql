@@ -0,0 +1,23 @@
+-- Partner price lists: agreed wholesale prices per SKU or per catalog collection, as a fixed price or a percentage
+-- off the Shopify price, optionally limited to a date range. A SKU rule beats a collection rule.
+CREATE TABLE IF NOT EXISTS partner_prices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    sku VARCHAR(255),
+    collection_handle VARCHAR(255),
+    fixed_price NUMERIC(14, 3) CHECK (fixed_price >= 0),
+    discount_percent NUMERIC(5, 2) CHECK (discount_percent > 0 AND discount_percent <= 100),
+    valid_from DATE,
+    valid_to DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_partner_prices_target CHECK ((sku IS NULL) <> (collection_handle IS NULL)),
+    CONSTRAINT chk_partner_prices_amount CHECK ((fixed_price IS NULL) <> (discount_percent IS NULL)),
+    CONSTRAINT chk_partner_prices_dates CHECK (valid_to IS NULL OR valid_from IS NULL OR valid_to >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_prices_partner_id ON partner_prices(partner_id);
+
+-- Shopify price and agreed price of supplier items priced from the partner's price list (NULL otherwise)
+ALTER TABLE supplier_order_items ADD COLUMN IF NOT EXISTS list_price NUMERIC(14, 3);
+ALTER TABLE supplier_order_items ADD COLUMN IF NOT EXISTS wholesale_price NUMERIC(14, 3);
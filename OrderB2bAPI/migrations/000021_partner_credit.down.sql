@@ -0,0 +1,4 @@
+ALTER TABLE partners DROP CONSTRAINT IF EXISTS chk_partners_credit_policy;
+ALTER TABLE partners DROP CONSTRAINT IF EXISTS chk_partners_credit_limit;
+ALTER TABLE partners DROP COLUMN IF EXISTS credit_policy;
+ALTER TABLE partners DROP COLUMN IF EXISTS credit_limit;
@@ -0,0 +1,6 @@
+-- Credit account: partners ordering on credit get a limit on their exposure (total of their open unpaid orders).
+-- NULL means no limit. Carts that would exceed it are refused (reject) or created and held for review (hold).
+ALTER TABLE partners ADD COLUMN IF NOT EXISTS credit_limit NUMERIC(14, 3);
+ALTER TABLE partners ADD COLUMN IF NOT EXISTS credit_policy VARCHAR(20) NOT NULL DEFAULT 'reject';
+ALTER TABLE partners ADD CONSTRAINT chk_partners_credit_limit CHECK (credit_limit IS NULL OR credit_limit >= 0);
+ALTER TABLE partners ADD CONSTRAINT chk_partners_credit_policy CHECK (credit_policy IN ('reject', 'hold'));
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS invoices_immutable ON invoices;
+DROP FUNCTION IF EXISTS reject_invoice_change();
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_counter;
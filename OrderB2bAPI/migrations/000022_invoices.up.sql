@@ -0,0 +1,35 @@
+-- Tax invoices: one per order, numbered without gaps (invoice_counter hands out the next number inside the
+-- issuing transaction). The rendered PDF is stored as issued; invoices cannot be changed or deleted.
+CREATE TABLE IF NOT EXISTS invoice_counter (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_sequence BIGINT NOT NULL DEFAULT 0
+);
+INSERT INTO invoice_counter (id, last_sequence) VALUES (TRUE, 0) ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sequence BIGINT NOT NULL UNIQUE,
+    invoice_number VARCHAR(50) NOT NULL UNIQUE,
+    supplier_order_id UUID NOT NULL UNIQUE REFERENCES supplier_orders(id),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+    currency VARCHAR(3) NOT NULL,
+    subtotal NUMERIC(14, 3) NOT NULL,
+    tax_rate NUMERIC(5, 2) NOT NULL,
+    tax NUMERIC(14, 3) NOT NULL,
+    total NUMERIC(14, 3) NOT NULL,
+    pdf BYTEA NOT NULL,
+    issued_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_partner_issued_at ON invoices(partner_id, issued_at);
+
+CREATE OR REPLACE FUNCTION reject_invoice_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'invoice % is immutable once issued', OLD.invoice_number;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER invoices_immutable BEFORE UPDATE OR DELETE ON invoices
+    FOR EACH ROW EXECUTE FUNCTION reject_invoice_change();
@@ -0,0 +1,9 @@
+ALTER TABLE supplier_orders DROP CONSTRAINT IF EXISTS chk_supplier_orders_currency;
+ALTER TABLE supplier_orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE partners DROP CONSTRAINT IF EXISTS chk_partners_currency;
+ALTER TABLE partners DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE partner_sku_mappings ALTER COLUMN price TYPE VARCHAR(50) USING price::TEXT;
+
+ALTER TABLE supplier_order_items ALTER COLUMN price TYPE DECIMAL(10, 2);
+ALTER TABLE supplier_orders ALTER COLUMN cart_total TYPE DECIMAL(10, 2);
@@ -0,0 +1,15 @@
+-- Money amounts are exact decimals to three places (JOD fils) in the partner's currency. Widen the money columns
+-- from the initial schema to NUMERIC(14, 3) (later ones are created that way), store synced Shopify prices as
+-- numbers, and give partners and orders a currency.
+ALTER TABLE supplier_orders ALTER COLUMN cart_total TYPE NUMERIC(14, 3);
+ALTER TABLE supplier_order_items ALTER COLUMN price TYPE NUMERIC(14, 3);
+
+-- Synced prices that are not plain decimals are dropped (re-synced from Shopify on the next catalog sync)
+ALTER TABLE partner_sku_mappings ALTER COLUMN price TYPE NUMERIC(14, 3)
+    USING CASE WHEN trim(price) ~ '^[0-9]+(\.[0-9]+)?$' THEN trim(price)::NUMERIC(14, 3) END;
+
+-- Amounts are not converted to the shop currency (Shopify prices, order edits, credit), so JOD is the only one allowed
+ALTER TABLE partners ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'JOD';
+ALTER TABLE partners ADD CONSTRAINT chk_partners_currency CHECK (currency = 'JOD');
+ALTER TABLE supplier_orders ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'JOD';
+ALTER TABLE supplier_orders ADD CONSTRAINT chk_supplier_orders_currency CHECK (currency = 'JOD');
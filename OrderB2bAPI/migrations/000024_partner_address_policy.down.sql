@@ -0,0 +1,2 @@
+ALTER TABLE partners DROP CONSTRAINT IF EXISTS chk_partners_address_policy;
+ALTER TABLE partners DROP COLUMN IF EXISTS address_policy;
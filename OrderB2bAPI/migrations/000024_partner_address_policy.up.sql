@@ -0,0 +1,4 @@
+-- Shipping addresses are normalized against the Jordan gazetteer on cart submit. Cities not in it are stored as
+-- sent (lenient) or refused with 422 (strict).
+ALTER TABLE partners ADD COLUMN IF NOT EXISTS address_policy VARCHAR(20) NOT NULL DEFAULT 'lenient';
+ALTER TABLE partners ADD CONSTRAINT chk_partners_address_policy CHECK (address_policy IN ('lenient', 'strict'));
@@ -0,0 +1,2 @@
+-- The backfill is not reversible: the phones' original formats are not kept. E.164 numbers stay valid.
+SELECT 1;
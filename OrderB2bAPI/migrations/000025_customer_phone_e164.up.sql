@@ -0,0 +1,35 @@
+-- Customer phones are stored in E.164 (+962791234567). Rewrite existing ones the way pkg/phone parses them:
+-- separators dropped, Arabic-Indic digits converted, "+"/"00" international prefixes, Jordanian national numbers
+-- (07XXXXXXXX, 7XXXXXXXX, 0[2356]XXXXXXX) and 962... without "+". Numbers that do not parse are left as they are.
+UPDATE supplier_orders o
+SET customer_phone = n.e164
+FROM (
+	SELECT id,
+		CASE
+			WHEN intl IS NOT NULL AND intl ~ '^9620?(7[789][0-9]{7}|[2356][0-9]{7})$'
+				THEN '+962' || regexp_replace(substr(intl, 4), '^0', '')
+			WHEN intl IS NOT NULL AND intl !~ '^962' AND intl ~ '^[1-9][0-9]{7,14}$'
+				THEN '+' || intl
+			WHEN intl IS NULL AND p ~ '^9620?(7[789][0-9]{7}|[2356][0-9]{7})$'
+				THEN '+962' || regexp_replace(substr(p, 4), '^0', '')
+			WHEN intl IS NULL AND p ~ '^0?(7[789][0-9]{7}|[2356][0-9]{7})$'
+				THEN '+962' || regexp_replace(p, '^0', '')
+		END AS e164
+	FROM (
+		SELECT id, p,
+			CASE
+				WHEN p LIKE '+%' THEN substr(p, 2)
+				WHEN p LIKE '00%' THEN substr(p, 3)
+			END AS intl
+		FROM (
+			SELECT id,
+				regexp_replace(
+					translate(replace(customer_phone, chr(160), ''), '٠١٢٣٤٥٦٧٨٩۰۱۲۳۴۵۶۷۸۹', '01234567890123456789'),
+					'[[:space:]()./-]', '', 'g'
+				) AS p
+			FROM supplier_orders
+			WHERE customer_phone IS NOT NULL AND customer_phone <> ''
+		) cleaned
+	) parsed
+) n
+WHERE o.id = n.id AND n.e164 IS NOT NULL AND n.e164 <> o.customer_phone;
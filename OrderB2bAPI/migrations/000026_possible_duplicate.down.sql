@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_supplier_orders_partner_phone_created_at;
+ALTER TABLE supplier_orders DROP COLUMN IF EXISTS possible_duplicate_of;
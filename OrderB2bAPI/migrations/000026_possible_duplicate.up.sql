@@ -0,0 +1,4 @@
+-- Orders that look like a retry of an earlier order under a new partner_order_id (same partner, customer phone and
+-- items within DUPLICATE_ORDER_WINDOW_MINUTES) are held in INCOMPLETE_CAUTION and point at that order
+ALTER TABLE supplier_orders ADD COLUMN IF NOT EXISTS possible_duplicate_of UUID REFERENCES supplier_orders(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_supplier_orders_partner_phone_created_at ON supplier_orders(partner_id, customer_phone, created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_supplier_orders_customer_phone_created_at;
+DROP TABLE IF EXISTS partner_order_rules;
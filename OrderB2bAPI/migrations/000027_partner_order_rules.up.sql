@@ -0,0 +1,28 @@
+-- Auto-confirmation rules: new orders are checked against the partner's rules by priority (lowest first) and the
+-- first rule whose conditions all hold confirms the order (UNFULFILLED) or holds it for review. NULL or empty
+-- conditions always hold.
+CREATE TABLE IF NOT EXISTS partner_order_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 100,
+    min_cart_total NUMERIC(14, 3) CHECK (min_cart_total >= 0),
+    max_cart_total NUMERIC(14, 3) CHECK (max_cart_total >= 0),
+    city_codes TEXT[],
+    min_item_count INTEGER CHECK (min_item_count >= 0),
+    max_item_count INTEGER CHECK (max_item_count >= 0),
+    min_delivered INTEGER CHECK (min_delivered >= 0),
+    max_returned INTEGER CHECK (max_returned >= 0),
+    payment_methods TEXT[],
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_partner_order_rules_action CHECK (action IN ('confirm', 'hold')),
+    CONSTRAINT chk_partner_order_rules_cart_total CHECK (max_cart_total IS NULL OR min_cart_total IS NULL OR max_cart_total >= min_cart_total),
+    CONSTRAINT chk_partner_order_rules_item_count CHECK (max_item_count IS NULL OR min_item_count IS NULL OR max_item_count >= min_item_count)
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_order_rules_partner_id ON partner_order_rules(partner_id);
+
+-- Customer history lookups (delivered and returned-to-shipper counts per phone)
+CREATE INDEX IF NOT EXISTS idx_supplier_orders_customer_phone_created_at ON supplier_orders(customer_phone, created_at);
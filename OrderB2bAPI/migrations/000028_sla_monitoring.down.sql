@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS sla_breaches;
+DROP TABLE IF EXISTS partner_sla_thresholds;
+DROP INDEX IF EXISTS idx_supplier_orders_status_changed_at;
+ALTER TABLE supplier_orders DROP COLUMN IF EXISTS status_changed_at;
@@ -0,0 +1,52 @@
+-- When the order entered its current status, for SLA monitoring. Existing orders take the time of their last
+-- status_change event to that status, or their creation time.
+ALTER TABLE supplier_orders ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP;
+UPDATE supplier_orders o SET status_changed_at = COALESCE(
+    (SELECT MAX(e.created_at) FROM order_events e
+     WHERE e.supplier_order_id = o.id AND e.event_type = 'status_change' AND e.event_data->>'to' = o.status),
+    o.created_at
+)
+WHERE status_changed_at IS NULL;
+ALTER TABLE supplier_orders ALTER COLUMN status_changed_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE supplier_orders ALTER COLUMN status_changed_at SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_supplier_orders_status_changed_at ON supplier_orders(status, status_changed_at);
+
+-- Per-partner SLA thresholds: how long an order may stay in an order status, or a shipment at a Wassel delivery
+-- status. They replace the SLA_THRESHOLDS defaults for the same status; 0 turns the check off for the partner.
+CREATE TABLE IF NOT EXISTS partner_sla_thresholds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    order_status VARCHAR(50),
+    delivery_status INTEGER,
+    max_minutes INTEGER NOT NULL CHECK (max_minutes >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_partner_sla_thresholds_target CHECK ((order_status IS NULL) <> (delivery_status IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_partner_sla_thresholds_order_status
+    ON partner_sla_thresholds(partner_id, order_status) WHERE order_status IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_partner_sla_thresholds_delivery_status
+    ON partner_sla_thresholds(partner_id, delivery_status) WHERE delivery_status IS NOT NULL;
+
+-- Orders found past a threshold by the SLA monitor. A breach is resolved once the order leaves the status (or the
+-- shipment the delivery status); alerted_at is set once staff were sent it.
+CREATE TABLE IF NOT EXISTS sla_breaches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    supplier_order_id UUID NOT NULL REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    order_status VARCHAR(50) NOT NULL,
+    delivery_status INTEGER,
+    since TIMESTAMPTZ NOT NULL,
+    threshold_minutes INTEGER NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL,
+    alerted_at TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_open ON sla_breaches(detected_at) WHERE resolved_at IS NULL;
+-- One open breach per order, status and time it entered the status, so overlapping scans cannot record it twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sla_breaches_open_key
+    ON sla_breaches(supplier_order_id, order_status, COALESCE(delivery_status, 0), since) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_partner_id ON sla_breaches(partner_id, detected_at);
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_supplier_order_id ON sla_breaches(supplier_order_id);
@@ -62,3 +62,14 @@ type ErrInvalidStateTransition struct {
 func (e *ErrInvalidStateTransition) Error() string {
 	return fmt.Sprintf("invalid state transition from %s to %s", e.From, e.To)
 }
+
+// ErrCreditLimitExceeded is returned when an order would take a partner over its credit limit
+type ErrCreditLimitExceeded struct {
+	Limit     domain.Money
+	Exposure  domain.Money // open unpaid orders before this one
+	Requested domain.Money // this order's total
+}
+
+func (e *ErrCreditLimitExceeded) Error() string {
+	return fmt.Sprintf("credit limit exceeded: %s open + %s requested > %s limit", e.Exposure.Display(), e.Requested.Display(), e.Limit.Display())
+}
@@ -0,0 +1,282 @@
+// Package gazetteer matches free-text Jordanian city and area names, in English or Arabic, to the canonical
+// entries of an embedded gazetteer. Names are compared after normalization (case, spacing, punctuation, Arabic
+// letter variants and diacritics, the definite article) and, failing an exact match, by edit distance.
+package gazetteer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Governorate is one of Jordan's twelve governorates. Code is its ISO 3166-2 code (e.g. JO-AM).
+type Governorate struct {
+	Code   string
+	NameEN string
+	NameAR string
+}
+
+// City is a city or town. Code is a lowercase slug, unique in the gazetteer.
+type City struct {
+	Code        string
+	Governorate string // ISO 3166-2 code of the governorate
+	NameEN      string
+	NameAR      string
+	Aliases     []string // other spellings, English or Arabic
+	Areas       []*Area
+}
+
+// Area is a district or neighbourhood of a city. Code is a lowercase slug, unique within its city.
+type Area struct {
+	Code    string
+	NameEN  string
+	NameAR  string
+	Aliases []string
+}
+
+// names returns the canonical names and aliases of a city or area.
+func names(en, ar string, aliases []string) []string {
+	return append([]string{en, ar}, aliases...)
+}
+
+// ref is a gazetteer entry: a city, or an area with its city.
+type ref struct {
+	city *City
+	area *Area
+}
+
+type indexKey struct {
+	key   []rune
+	entry ref
+}
+
+// index maps normalized names to entries, built once from the gazetteer tables. A name shared by different
+// entries is ambiguous and matches none of them.
+type index struct {
+	keys      map[string]ref
+	ambiguous map[string]bool
+	ordered   []indexKey // all keys, for fuzzy matching
+}
+
+func newIndex() *index {
+	return &index{keys: map[string]ref{}, ambiguous: map[string]bool{}}
+}
+
+func (ix *index) add(entry ref, all []string) {
+	for _, name := range all {
+		key := Normalize(name)
+		if key == "" {
+			continue
+		}
+		if existing, exists := ix.keys[key]; exists {
+			if existing != entry {
+				ix.ambiguous[key] = true
+				ix.ordered = append(ix.ordered, indexKey{key: []rune(key), entry: entry})
+			}
+			continue
+		}
+		ix.keys[key] = entry
+		ix.ordered = append(ix.ordered, indexKey{key: []rune(key), entry: entry})
+	}
+}
+
+// find returns the entry whose name matches name exactly after normalization, or else the single entry closest
+// to it within maxDistance edits. fuzzy is true for an edit-distance match.
+func (ix *index) find(name string) (entry ref, fuzzy bool, ok bool) {
+	key := Normalize(name)
+	if key == "" || ix.ambiguous[key] {
+		return ref{}, false, false
+	}
+	if entry, ok := ix.keys[key]; ok {
+		return entry, false, true
+	}
+
+	runes := []rune(key)
+	limit := maxDistance(len(runes))
+	if limit == 0 {
+		return ref{}, false, false
+	}
+	best := limit
+	var found []ref
+	for _, candidate := range ix.ordered {
+		d := distance(runes, candidate.key)
+		switch {
+		case d < best || (d == best && len(found) == 0):
+			best = d
+			found = append(found[:0], candidate.entry)
+		case d == best && !contains(found, candidate.entry):
+			found = append(found, candidate.entry)
+		}
+	}
+	// Different entries equally close: ambiguous
+	if len(found) != 1 {
+		return ref{}, false, false
+	}
+	return found[0], true, true
+}
+
+func contains(entries []ref, entry ref) bool {
+	for _, e := range entries {
+		if e == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// maxDistance is the number of edits tolerated for a normalized name of n letters: none for very short names,
+// where a single edit often makes another place.
+func maxDistance(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the optimal string alignment distance between a and b: insertions, deletions, substitutions and
+// transpositions of adjacent letters each count as one edit.
+func distance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// arabicLetters folds Arabic letter variants that partners use interchangeably.
+var arabicLetters = map[rune]rune{
+	'أ': 'ا', 'إ': 'ا', 'آ': 'ا', 'ٱ': 'ا', // alef with hamza or madda, alef wasla
+	'ة': 'ه', // teh marbuta
+	'ى': 'ي', // alef maksura
+	'ؤ': 'و', // waw with hamza
+	'ئ': 'ي', // yeh with hamza
+}
+
+// latinArticles are transliterations of the Arabic definite article, dropped when they stand alone.
+var latinArticles = map[string]bool{
+	"al": true, "el": true, "il": true, "ad": true, "adh": true, "an": true, "ar": true, "as": true,
+	"ash": true, "at": true, "az": true,
+}
+
+// Normalize returns the form names are compared in: lowercase letters and digits only, without spaces, Arabic
+// diacritics and tatweel, with Arabic letter variants folded and the definite article ("al-", "el ", "ال")
+// dropped. "Tla' Al-Ali", "tla al ali" and "TLA ALALI" all normalize the same way, as do "عمّان" and "عمان".
+func Normalize(name string) string {
+	var word strings.Builder
+	var out strings.Builder
+	flush := func() {
+		w := word.String()
+		word.Reset()
+		if latinArticles[w] {
+			return
+		}
+		if r := []rune(w); len(r) > 3 && r[0] == 'ا' && r[1] == 'ل' {
+			w = string(r[2:])
+		}
+		out.WriteString(w)
+	}
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 0x064B && r <= 0x0652, r == 0x0670, r == 0x0640: // harakat, superscript alef, tatweel
+			continue
+		case r == '\'' || r == '`' || r == '’' || r == '‘' || r == 'ʼ' || r == 'ʿ' || r == 'ʾ':
+			continue // hamza and ain marks in transliterations: "Ma'an" is "Maan"
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if folded, ok := arabicLetters[r]; ok {
+				r = folded
+			}
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return out.String()
+}
+
+var (
+	cityIndex   = newIndex()
+	areaIndex   = map[*City]*index{}
+	anyArea     = newIndex()
+	cityByCode  = map[string]*City{}
+	governorate = map[string]*Governorate{}
+)
+
+func init() {
+	for _, g := range governorates {
+		governorate[g.Code] = g
+	}
+	for _, city := range cities {
+		cityByCode[city.Code] = city
+		cityIndex.add(ref{city: city}, names(city.NameEN, city.NameAR, city.Aliases))
+		areas := newIndex()
+		for _, area := range city.Areas {
+			all := names(area.NameEN, area.NameAR, area.Aliases)
+			areas.add(ref{city: city, area: area}, all)
+			anyArea.add(ref{city: city, area: area}, all)
+		}
+		areaIndex[city] = areas
+	}
+}
+
+// FindCity returns the city named name. fuzzy is true when the name only matched within a few edits (a misspelling).
+func FindCity(name string) (city *City, fuzzy bool, ok bool) {
+	entry, fuzzy, ok := cityIndex.find(name)
+	return entry.city, fuzzy, ok
+}
+
+// FindArea returns the area of c named name.
+func (c *City) FindArea(name string) (area *Area, fuzzy bool, ok bool) {
+	areas, exists := areaIndex[c]
+	if !exists {
+		return nil, false, false
+	}
+	entry, fuzzy, ok := areas.find(name)
+	return entry.area, fuzzy, ok
+}
+
+// FindAnyArea returns the area named name in any city, with its city, for areas sent in place of the city (e.g.
+// "Khalda" for Amman). Names shared by areas of different cities do not match.
+func FindAnyArea(name string) (city *City, area *Area, fuzzy bool, ok bool) {
+	entry, fuzzy, ok := anyArea.find(name)
+	return entry.city, entry.area, fuzzy, ok
+}
+
+// CityByCode returns the city with code, or nil.
+func CityByCode(code string) *City {
+	return cityByCode[code]
+}
+
+// GovernorateByCode returns the governorate with ISO 3166-2 code, or nil.
+func GovernorateByCode(code string) *Governorate {
+	return governorate[code]
+}
+
+// Governorates returns Jordan's governorates, north to south.
+func Governorates() []*Governorate {
+	return governorates
+}
+
+// Cities returns every city in the gazetteer.
+func Cities() []*City {
+	return cities
+}
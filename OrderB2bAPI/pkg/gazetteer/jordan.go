@@ -0,0 +1,136 @@
+package gazetteer
+
+// governorates are Jordan's governorates (ISO 3166-2:JO), north to south.
+var governorates = []*Governorate{
+	{Code: "JO-IR", NameEN: "Irbid", NameAR: "إربد"},
+	{Code: "JO-AJ", NameEN: "Ajloun", NameAR: "عجلون"},
+	{Code: "JO-JA", NameEN: "Jerash", NameAR: "جرش"},
+	{Code: "JO-MA", NameEN: "Mafraq", NameAR: "المفرق"},
+	{Code: "JO-BA", NameEN: "Balqa", NameAR: "البلقاء"},
+	{Code: "JO-AM", NameEN: "Amman", NameAR: "العاصمة"},
+	{Code: "JO-AZ", NameEN: "Zarqa", NameAR: "الزرقاء"},
+	{Code: "JO-MD", NameEN: "Madaba", NameAR: "مادبا"},
+	{Code: "JO-KA", NameEN: "Karak", NameAR: "الكرك"},
+	{Code: "JO-AT", NameEN: "Tafilah", NameAR: "الطفيلة"},
+	{Code: "JO-MN", NameEN: "Ma'an", NameAR: "معان"},
+	{Code: "JO-AQ", NameEN: "Aqaba", NameAR: "العقبة"},
+}
+
+// cities are the cities and towns Wassel delivers to, with the areas partners commonly send. English names are
+// the ones printed on Wassel waybills.
+var cities = []*City{
+	// Amman
+	{Code: "amman", Governorate: "JO-AM", NameEN: "Amman", NameAR: "عمان", Aliases: []string{"Aman", "Ammann", "Amman City", "العاصمة", "عمان العاصمة"}, Areas: []*Area{
+		{Code: "abdali", NameEN: "Abdali", NameAR: "العبدلي"},
+		{Code: "abdoun", NameEN: "Abdoun", NameAR: "عبدون", Aliases: []string{"Abdoon"}},
+		{Code: "abu-alanda", NameEN: "Abu Alanda", NameAR: "أبو علندا", Aliases: []string{"Abu Olanda"}},
+		{Code: "abu-nseir", NameEN: "Abu Nseir", NameAR: "أبو نصير", Aliases: []string{"Abu Nsair", "Abu Nusair"}},
+		{Code: "airport-road", NameEN: "Airport Road", NameAR: "طريق المطار"},
+		{Code: "arjan", NameEN: "Arjan", NameAR: "عرجان"},
+		{Code: "bayader", NameEN: "Bayader Wadi Al Seer", NameAR: "بيادر وادي السير", Aliases: []string{"Bayader", "Al Bayader", "البيادر"}},
+		{Code: "dabouq", NameEN: "Dabouq", NameAR: "دابوق", Aliases: []string{"Dabouk", "Dabuq"}},
+		{Code: "dahiyat-al-rasheed", NameEN: "Dahiyat Al Rasheed", NameAR: "ضاحية الرشيد", Aliases: []string{"Rasheed Suburb", "Dahiat Al Rasheed"}},
+		{Code: "dahiyat-al-yasmeen", NameEN: "Dahiyat Al Yasmeen", NameAR: "ضاحية الياسمين", Aliases: []string{"Yasmeen", "Dahiat Al Yasmin", "Al Yasmeen"}},
+		{Code: "deir-ghbar", NameEN: "Deir Ghbar", NameAR: "دير غبار", Aliases: []string{"Dair Ghbar"}},
+		{Code: "downtown", NameEN: "Downtown", NameAR: "وسط البلد", Aliases: []string{"Al Balad", "Balad", "البلد"}},
+		{Code: "gardens", NameEN: "Gardens", NameAR: "الجاردنز", Aliases: []string{"Jardens", "Al Gardens", "Wasfi Al Tal Street", "شارع وصفي التل"}},
+		{Code: "hashmi-shamali", NameEN: "Hashmi Al Shamali", NameAR: "الهاشمي الشمالي", Aliases: []string{"Hashmi Shamali", "Al Hashmi"}},
+		{Code: "jabal-al-hussein", NameEN: "Jabal Al Hussein", NameAR: "جبل الحسين", Aliases: []string{"Jabal Hussein", "Jabal Al Hussain"}},
+		{Code: "jabal-al-nasr", NameEN: "Jabal Al Nasr", NameAR: "جبل النصر", Aliases: []string{"Jabal Al Naser"}},
+		{Code: "jabal-al-zohour", NameEN: "Jabal Al Zohour", NameAR: "جبل الزهور", Aliases: []string{"Jabal Al Zuhoor"}},
+		{Code: "jabal-amman", NameEN: "Jabal Amman", NameAR: "جبل عمان", Aliases: []string{"Jabal Aman"}},
+		{Code: "jubeiha", NameEN: "Jubeiha", NameAR: "الجبيهة", Aliases: []string{"Jubaiha", "Jbeiha"}},
+		{Code: "khalda", NameEN: "Khalda", NameAR: "خلدا", Aliases: []string{"Khelda"}},
+		{Code: "marj-al-hamam", NameEN: "Marj Al Hamam", NameAR: "مرج الحمام", Aliases: []string{"Marj Alhamam"}},
+		{Code: "marka", NameEN: "Marka", NameAR: "ماركا"},
+		{Code: "muqabalain", NameEN: "Muqabalain", NameAR: "المقابلين", Aliases: []string{"Mqabalain", "Al Muqabalain"}},
+		{Code: "naour", NameEN: "Naour", NameAR: "ناعور", Aliases: []string{"Naur", "Na'ur"}},
+		{Code: "nuzha", NameEN: "Nuzha", NameAR: "النزهة", Aliases: []string{"Al Nuzha", "Nozha"}},
+		{Code: "quwaismeh", NameEN: "Quwaismeh", NameAR: "القويسمة", Aliases: []string{"Qweismeh", "Quwaysimah"}},
+		{Code: "rabieh", NameEN: "Rabieh", NameAR: "الرابية", Aliases: []string{"Rabia", "Al Rabiah"}},
+		{Code: "ras-al-ain", NameEN: "Ras Al Ain", NameAR: "رأس العين"},
+		{Code: "shafa-badran", NameEN: "Shafa Badran", NameAR: "شفا بدران"},
+		{Code: "shmeisani", NameEN: "Shmeisani", NameAR: "الشميساني", Aliases: []string{"Shmaisani", "Shmesani"}},
+		{Code: "sweifieh", NameEN: "Sweifieh", NameAR: "الصويفية", Aliases: []string{"Sweifiyeh", "Swefieh", "Swaifyeh"}},
+		{Code: "sweileh", NameEN: "Sweileh", NameAR: "صويلح", Aliases: []string{"Suwaylih", "Swaileh"}},
+		{Code: "tabarbour", NameEN: "Tabarbour", NameAR: "طبربور", Aliases: []string{"Tabarbor"}},
+		{Code: "tla-al-ali", NameEN: "Tla' Al Ali", NameAR: "تلاع العلي", Aliases: []string{"Tlaa Al Ali", "Tlaa Al-Ali"}},
+		{Code: "um-al-summaq", NameEN: "Um Al Summaq", NameAR: "أم السماق", Aliases: []string{"Um Summaq", "Umm As Summaq"}},
+		{Code: "um-uthaina", NameEN: "Um Uthaina", NameAR: "أم أذينة", Aliases: []string{"Um Uthayna", "Umm Uthaynah", "Um Othaina"}},
+		{Code: "weibdeh", NameEN: "Jabal Al Weibdeh", NameAR: "جبل اللويبدة", Aliases: []string{"Weibdeh", "Lweibdeh", "اللويبدة"}},
+		{Code: "yadoudeh", NameEN: "Yadoudeh", NameAR: "اليادودة", Aliases: []string{"Yadudah"}},
+	}},
+	{Code: "jiza", Governorate: "JO-AM", NameEN: "Al Jiza", NameAR: "الجيزة", Aliases: []string{"Jeeza", "Jizah"}},
+	{Code: "muwaqqar", Governorate: "JO-AM", NameEN: "Al Muwaqqar", NameAR: "الموقر", Aliases: []string{"Muwaqar", "Mwaqqar"}},
+	{Code: "sahab", Governorate: "JO-AM", NameEN: "Sahab", NameAR: "سحاب"},
+
+	// Zarqa
+	{Code: "zarqa", Governorate: "JO-AZ", NameEN: "Zarqa", NameAR: "الزرقاء", Aliases: []string{"Zarka", "Zerqa", "Az Zarqa", "الزرقا"}, Areas: []*Area{
+		{Code: "jabal-tareq", NameEN: "Jabal Tareq", NameAR: "جبل طارق", Aliases: []string{"Jabal Tariq"}},
+		{Code: "masoum", NameEN: "Hay Masoum", NameAR: "حي معصوم", Aliases: []string{"Masoum", "Ma'soum"}},
+		{Code: "new-zarqa", NameEN: "New Zarqa", NameAR: "الزرقاء الجديدة", Aliases: []string{"Zarqa Al Jadida", "Zarqa Jadida"}},
+	}},
+	{Code: "azraq", Governorate: "JO-AZ", NameEN: "Al Azraq", NameAR: "الأزرق", Aliases: []string{"Azrak"}},
+	{Code: "dhlail", Governorate: "JO-AZ", NameEN: "Al Dhlail", NameAR: "الضليل", Aliases: []string{"Dulail", "Dhleil"}},
+	{Code: "hashemiyah", Governorate: "JO-AZ", NameEN: "Al Hashemiyah", NameAR: "الهاشمية", Aliases: []string{"Hashimiya", "Hashmiyeh"}},
+	{Code: "russeifa", Governorate: "JO-AZ", NameEN: "Russeifa", NameAR: "الرصيفة", Aliases: []string{"Rusaifa", "Rusayfah", "Ruseifa"}, Areas: []*Area{
+		{Code: "awajan", NameEN: "Awajan", NameAR: "عوجان"},
+	}},
+
+	// Irbid
+	{Code: "irbid", Governorate: "JO-IR", NameEN: "Irbid", NameAR: "إربد", Aliases: []string{"Irbed", "Arbid", "اربد"}, Areas: []*Area{
+		{Code: "aydoun", NameEN: "Aydoun", NameAR: "ايدون", Aliases: []string{"Aidoun", "Idoun"}},
+		{Code: "bushra", NameEN: "Bushra", NameAR: "بشرى"},
+		{Code: "huwwara", NameEN: "Huwwara", NameAR: "حوارة", Aliases: []string{"Howwara", "Hawwara"}},
+		{Code: "nuzha", NameEN: "Nuzha", NameAR: "النزهة", Aliases: []string{"Al Nuzha", "Nozha"}},
+		{Code: "sareeh", NameEN: "Al Sareeh", NameAR: "الصريح", Aliases: []string{"Sarih"}},
+		{Code: "university-street", NameEN: "University Street", NameAR: "شارع الجامعة", Aliases: []string{"Jamaa Street"}},
+	}},
+	{Code: "husn", Governorate: "JO-IR", NameEN: "Al Husn", NameAR: "الحصن", Aliases: []string{"Husun", "Hosn"}},
+	{Code: "north-shouneh", Governorate: "JO-IR", NameEN: "North Shouneh", NameAR: "الشونة الشمالية", Aliases: []string{"Shouna Shamalia", "Al Shuna Al Shamaliyah"}},
+	{Code: "ramtha", Governorate: "JO-IR", NameEN: "Ramtha", NameAR: "الرمثا", Aliases: []string{"Ar Ramtha", "Ramtha City"}},
+
+	// Ajloun
+	{Code: "ajloun", Governorate: "JO-AJ", NameEN: "Ajloun", NameAR: "عجلون", Aliases: []string{"Ajlun", "Ajlon"}},
+	{Code: "anjara", Governorate: "JO-AJ", NameEN: "Anjara", NameAR: "عنجرة", Aliases: []string{"Anjarah"}},
+	{Code: "kufranjah", Governorate: "JO-AJ", NameEN: "Kufranjah", NameAR: "كفرنجة", Aliases: []string{"Kofranja", "Kufranja"}},
+
+	// Jerash
+	{Code: "jerash", Governorate: "JO-JA", NameEN: "Jerash", NameAR: "جرش", Aliases: []string{"Jarash", "Gerasa"}},
+
+	// Mafraq
+	{Code: "mafraq", Governorate: "JO-MA", NameEN: "Mafraq", NameAR: "المفرق", Aliases: []string{"Al Mafraq", "Mafrak"}},
+	{Code: "ruwaished", Governorate: "JO-MA", NameEN: "Ruwaished", NameAR: "الرويشد", Aliases: []string{"Ruwayshid", "Rweished"}},
+
+	// Balqa
+	{Code: "salt", Governorate: "JO-BA", NameEN: "Salt", NameAR: "السلط", Aliases: []string{"As Salt", "Al Salt"}},
+	{Code: "ain-al-basha", Governorate: "JO-BA", NameEN: "Ain Al Basha", NameAR: "عين الباشا", Aliases: []string{"Ein Al Basha", "Ain Albasha"}, Areas: []*Area{
+		{Code: "baqaa", NameEN: "Baqa'a", NameAR: "البقعة", Aliases: []string{"Baqaa Camp", "Al Baqa"}},
+	}},
+	{Code: "deir-alla", Governorate: "JO-BA", NameEN: "Deir Alla", NameAR: "دير علا", Aliases: []string{"Dair Alla", "Deir Ala"}},
+	{Code: "fuheis", Governorate: "JO-BA", NameEN: "Fuheis", NameAR: "الفحيص", Aliases: []string{"Fuhais", "Fhais"}},
+	{Code: "mahis", Governorate: "JO-BA", NameEN: "Mahis", NameAR: "ماحص", Aliases: []string{"Mahes"}},
+	{Code: "south-shouneh", Governorate: "JO-BA", NameEN: "South Shouneh", NameAR: "الشونة الجنوبية", Aliases: []string{"Shouna Janoubia", "Al Shuna Al Janubiyah"}},
+
+	// Madaba
+	{Code: "madaba", Governorate: "JO-MD", NameEN: "Madaba", NameAR: "مادبا", Aliases: []string{"Ma'daba", "Madaba City"}},
+	{Code: "dhiban", Governorate: "JO-MD", NameEN: "Dhiban", NameAR: "ذيبان", Aliases: []string{"Theeban", "Diban"}},
+
+	// Karak
+	{Code: "karak", Governorate: "JO-KA", NameEN: "Karak", NameAR: "الكرك", Aliases: []string{"Kerak", "Al Karak"}},
+	{Code: "ghor-al-safi", Governorate: "JO-KA", NameEN: "Ghor Al Safi", NameAR: "غور الصافي", Aliases: []string{"Ghor Safi", "Safi"}},
+	{Code: "mutah", Governorate: "JO-KA", NameEN: "Mu'tah", NameAR: "مؤتة", Aliases: []string{"Mutah", "Muta"}},
+
+	// Tafilah
+	{Code: "tafilah", Governorate: "JO-AT", NameEN: "Tafilah", NameAR: "الطفيلة", Aliases: []string{"Tafila", "At Tafilah", "Tafileh"}},
+	{Code: "busaira", Governorate: "JO-AT", NameEN: "Busaira", NameAR: "بصيرا", Aliases: []string{"Buseira", "Busayra"}},
+
+	// Ma'an
+	{Code: "maan", Governorate: "JO-MN", NameEN: "Ma'an", NameAR: "معان", Aliases: []string{"Maan", "Ma'an City"}},
+	{Code: "shobak", Governorate: "JO-MN", NameEN: "Shobak", NameAR: "الشوبك", Aliases: []string{"Shoubak", "Shawbak"}},
+	{Code: "wadi-musa", Governorate: "JO-MN", NameEN: "Wadi Musa", NameAR: "وادي موسى", Aliases: []string{"Wadi Mousa", "Petra", "البتراء"}},
+
+	// Aqaba
+	{Code: "aqaba", Governorate: "JO-AQ", NameEN: "Aqaba", NameAR: "العقبة", Aliases: []string{"Akaba", "Al Aqaba"}},
+	{Code: "quweira", Governorate: "JO-AQ", NameEN: "Al Quweira", NameAR: "القويرة", Aliases: []string{"Quwayra", "Qweira"}},
+}
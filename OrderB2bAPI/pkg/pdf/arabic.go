@@ -0,0 +1,245 @@
+package pdf
+
+import "unicode"
+
+// arabicForms holds the presentation forms (Arabic Presentation Forms-B) of each Arabic letter: isolated, final,
+// initial and medial. Letters that never join the following letter have no initial or medial form (0).
+var arabicForms = map[rune][4]rune{
+	0x0621: {0xFE80, 0, 0, 0},                // hamza
+	0x0622: {0xFE81, 0xFE82, 0, 0},           // alef with madda
+	0x0623: {0xFE83, 0xFE84, 0, 0},           // alef with hamza above
+	0x0624: {0xFE85, 0xFE86, 0, 0},           // waw with hamza
+	0x0625: {0xFE87, 0xFE88, 0, 0},           // alef with hamza below
+	0x0626: {0xFE89, 0xFE8A, 0xFE8B, 0xFE8C}, // yeh with hamza
+	0x0627: {0xFE8D, 0xFE8E, 0, 0},           // alef
+	0x0628: {0xFE8F, 0xFE90, 0xFE91, 0xFE92}, // beh
+	0x0629: {0xFE93, 0xFE94, 0, 0},           // teh marbuta
+	0x062A: {0xFE95, 0xFE96, 0xFE97, 0xFE98}, // teh
+	0x062B: {0xFE99, 0xFE9A, 0xFE9B, 0xFE9C}, // theh
+	0x062C: {0xFE9D, 0xFE9E, 0xFE9F, 0xFEA0}, // jeem
+	0x062D: {0xFEA1, 0xFEA2, 0xFEA3, 0xFEA4}, // hah
+	0x062E: {0xFEA5, 0xFEA6, 0xFEA7, 0xFEA8}, // khah
+	0x062F: {0xFEA9, 0xFEAA, 0, 0},           // dal
+	0x0630: {0xFEAB, 0xFEAC, 0, 0},           // thal
+	0x0631: {0xFEAD, 0xFEAE, 0, 0},           // reh
+	0x0632: {0xFEAF, 0xFEB0, 0, 0},           // zain
+	0x0633: {0xFEB1, 0xFEB2, 0xFEB3, 0xFEB4}, // seen
+	0x0634: {0xFEB5, 0xFEB6, 0xFEB7, 0xFEB8}, // sheen
+	0x0635: {0xFEB9, 0xFEBA, 0xFEBB, 0xFEBC}, // sad
+	0x0636: {0xFEBD, 0xFEBE, 0xFEBF, 0xFEC0}, // dad
+	0x0637: {0xFEC1, 0xFEC2, 0xFEC3, 0xFEC4}, // tah
+	0x0638: {0xFEC5, 0xFEC6, 0xFEC7, 0xFEC8}, // zah
+	0x0639: {0xFEC9, 0xFECA, 0xFECB, 0xFECC}, // ain
+	0x063A: {0xFECD, 0xFECE, 0xFECF, 0xFED0}, // ghain
+	0x0640: {0x0640, 0x0640, 0x0640, 0x0640}, // tatweel
+	0x0641: {0xFED1, 0xFED2, 0xFED3, 0xFED4}, // feh
+	0x0642: {0xFED5, 0xFED6, 0xFED7, 0xFED8}, // qaf
+	0x0643: {0xFED9, 0xFEDA, 0xFEDB, 0xFEDC}, // kaf
+	0x0644: {0xFEDD, 0xFEDE, 0xFEDF, 0xFEE0}, // lam
+	0x0645: {0xFEE1, 0xFEE2, 0xFEE3, 0xFEE4}, // meem
+	0x0646: {0xFEE5, 0xFEE6, 0xFEE7, 0xFEE8}, // noon
+	0x0647: {0xFEE9, 0xFEEA, 0xFEEB, 0xFEEC}, // heh
+	0x0648: {0xFEED, 0xFEEE, 0, 0},           // waw
+	0x0649: {0xFEEF, 0xFEF0, 0, 0},           // alef maksura
+	0x064A: {0xFEF1, 0xFEF2, 0xFEF3, 0xFEF4}, // yeh
+}
+
+// lamAlef holds the isolated and final lam-alef ligatures, by the alef that follows lam.
+var lamAlef = map[rune][2]rune{
+	0x0622: {0xFEF5, 0xFEF6},
+	0x0623: {0xFEF7, 0xFEF8},
+	0x0625: {0xFEF9, 0xFEFA},
+	0x0627: {0xFEFB, 0xFEFC},
+}
+
+// isArabicMark reports whether r is a vowel sign or other combining mark. Marks are dropped: without glyph
+// positioning they would be drawn beside the letters rather than over them.
+func isArabicMark(r rune) bool {
+	return (r >= 0x064B && r <= 0x065F) || r == 0x0670
+}
+
+// shapeArabic replaces Arabic letters (in logical order) with the presentation form for their position in the
+// word, so a font without OpenType shaping draws them joined.
+func shapeArabic(runes []rune) []rune {
+	letters := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		if !isArabicMark(r) {
+			letters = append(letters, r)
+		}
+	}
+
+	// joinsNext reports whether the letter at i connects to the letter after it
+	joinsNext := func(i int) bool {
+		forms, ok := arabicForms[letters[i]]
+		return ok && forms[2] != 0
+	}
+	// joinsPrev reports whether the letter at i can connect to the letter before it
+	joinsPrev := func(i int) bool {
+		forms, ok := arabicForms[letters[i]]
+		return ok && forms[1] != 0
+	}
+
+	out := make([]rune, 0, len(letters))
+	for i := 0; i < len(letters); i++ {
+		r := letters[i]
+		forms, ok := arabicForms[r]
+		if !ok {
+			out = append(out, r)
+			continue
+		}
+		prev := i > 0 && joinsNext(i-1) && joinsPrev(i)
+
+		if r == 0x0644 && i+1 < len(letters) {
+			if lig, ok := lamAlef[letters[i+1]]; ok {
+				if prev {
+					out = append(out, lig[1])
+				} else {
+					out = append(out, lig[0])
+				}
+				i++
+				continue
+			}
+		}
+
+		next := i+1 < len(letters) && joinsNext(i) && joinsPrev(i+1)
+		switch {
+		case prev && next:
+			out = append(out, forms[3])
+		case prev:
+			out = append(out, forms[1])
+		case next:
+			out = append(out, forms[2])
+		default:
+			out = append(out, forms[0])
+		}
+	}
+	return out
+}
+
+// isRTL reports whether r is a right-to-left letter (Arabic, including presentation forms).
+func isRTL(r rune) bool {
+	switch {
+	case r >= 0x0660 && r <= 0x0669, r >= 0x06F0 && r <= 0x06F9: // Arabic-Indic digits read left to right
+		return false
+	case r >= 0x0600 && r <= 0x06FF, r >= 0x0750 && r <= 0x077F, r >= 0xFB50 && r <= 0xFDFF, r >= 0xFE70 && r <= 0xFEFF:
+		return unicode.IsLetter(r) || unicode.IsMark(r) || r == 0x061F || r == 0x060C || r == 0x061B
+	}
+	return false
+}
+
+// Bidirectional classes used by visualOrder
+const (
+	neutral = iota
+	ltr
+	rtl
+)
+
+// mirrored swaps paired punctuation drawn inside right-to-left text.
+var mirrored = map[rune]rune{'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<'}
+
+// visualOrder reorders a line from logical to display order. It is a reduced form of the Unicode bidirectional
+// algorithm: letters and digits are left-to-right, Arabic is right-to-left, and spaces and punctuation take the
+// direction of the text around them. A line whose first letter is Arabic reads right to left.
+func visualOrder(runes []rune) []rune {
+	hasRTL := false
+	for _, r := range runes {
+		if isRTL(r) {
+			hasRTL = true
+			break
+		}
+	}
+	if !hasRTL {
+		return runes
+	}
+
+	classes := make([]int, len(runes))
+	base := ltr
+	for i, r := range runes {
+		switch {
+		case isRTL(r):
+			classes[i] = rtl
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			classes[i] = ltr
+		}
+		if base == ltr && classes[i] == rtl && firstStrong(classes[:i]) < 0 {
+			base = rtl
+		}
+	}
+
+	level := func(class int) int {
+		switch {
+		case class == rtl:
+			return 1
+		case base == rtl:
+			return 2
+		}
+		return 0
+	}
+	levels := make([]int, len(runes))
+	maxLevel := 0
+	for i, class := range classes {
+		if class == neutral {
+			before, after := base, base
+			for j := i - 1; j >= 0; j-- {
+				if classes[j] != neutral {
+					before = classes[j]
+					break
+				}
+			}
+			for j := i + 1; j < len(classes); j++ {
+				if classes[j] != neutral {
+					after = classes[j]
+					break
+				}
+			}
+			class = base
+			if before == after {
+				class = before
+			}
+			classes[i] = class
+		}
+		levels[i] = level(classes[i])
+		if levels[i] > maxLevel {
+			maxLevel = levels[i]
+		}
+	}
+
+	out := make([]rune, len(runes))
+	copy(out, runes)
+	// Reverse every run at or above each level, from the highest level down to 1
+	for lvl := maxLevel; lvl >= 1; lvl-- {
+		for i := 0; i < len(out); {
+			if levels[i] < lvl {
+				i++
+				continue
+			}
+			j := i
+			for j < len(out) && levels[j] >= lvl {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				out[a], out[b] = out[b], out[a]
+				levels[a], levels[b] = levels[b], levels[a]
+			}
+			i = j
+		}
+	}
+	for i, r := range out {
+		if levels[i]%2 == 1 {
+			if m, ok := mirrored[r]; ok {
+				out[i] = m
+			}
+		}
+	}
+	return out
+}
+
+// firstStrong returns the index of the first non-neutral class, or -1.
+func firstStrong(classes []int) int {
+	for i, class := range classes {
+		if class != neutral {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,358 @@
+// Package pdf writes simple A4 documents (text, rules and shaded boxes) such as invoices. Text is set in
+// Helvetica, or in an embedded TrueType font when one is given, which also draws Arabic: letters are joined with
+// their presentation forms and lines are laid out right to left.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document is a PDF being built page by page. Coordinates are in points from the top-left corner of the page.
+type Document struct {
+	title string
+	font  *trueTypeFont   // nil: Helvetica
+	used  map[uint16]rune // glyphs drawn with font, and the character each one shows
+	pages []*Page
+}
+
+// Page is one page of a Document.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// New starts an empty document.
+func New() *Document {
+	return &Document{used: make(map[uint16]rune)}
+}
+
+// SetTitle sets the document title shown by PDF viewers.
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// SetFont embeds a TrueType font (.ttf) for all text. Without one, characters outside Latin-1 print as "?".
+// Bold text is drawn with a stroked outline, since only one font is embedded.
+func (d *Document) SetFont(ttf []byte) error {
+	font, err := parseTrueType(ttf)
+	if err != nil {
+		return err
+	}
+	d.font = font
+	return nil
+}
+
+// HasUnicodeFont reports whether an embedded font is set, i.e. whether text such as Arabic can be drawn.
+func (d *Document) HasUnicodeFont() bool {
+	return d.font != nil
+}
+
+// CanDraw reports whether every character of s can be drawn: in Latin-1 (WinAnsi) without an embedded font, or
+// present in the font. Characters that cannot be drawn print as "?".
+func (d *Document) CanDraw(s string) bool {
+	if d.font == nil {
+		for _, r := range s {
+			if _, ok := winAnsiByte(r); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	for _, r := range shapeArabic([]rune(s)) {
+		if d.font.glyph(r) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AddPage appends a blank A4 page.
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// PageCount returns the number of pages added so far.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// glyphs shapes s and returns its glyph IDs in display order, with the character each one shows. Characters
+// the font lacks are shown as "?".
+func (d *Document) glyphs(s string) ([]uint16, []rune) {
+	runes := visualOrder(shapeArabic([]rune(s)))
+	ids := make([]uint16, len(runes))
+	for i, r := range runes {
+		ids[i] = d.font.glyph(r)
+		if ids[i] == 0 {
+			ids[i], runes[i] = d.font.glyph('?'), '?'
+		}
+	}
+	return ids, runes
+}
+
+// TextWidth returns the width of s in points at size.
+func (d *Document) TextWidth(s string, size float64, bold bool) float64 {
+	if d.font == nil {
+		return helveticaWidth(winAnsi(s), bold) * size / 1000
+	}
+	var w float64
+	ids, _ := d.glyphs(s)
+	for _, g := range ids {
+		w += d.font.advance(g)
+	}
+	return w * size / 1000
+}
+
+// Text draws s with its baseline starting at (x, y).
+func (p *Page) Text(x, y, size float64, bold bool, s string) {
+	if s == "" {
+		return
+	}
+	d := p.doc
+	if d.font == nil {
+		fontName := "F1"
+		if bold {
+			fontName = "F2"
+		}
+		fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+			fontName, num(size), num(x), num(PageHeight-y), escapeString(winAnsi(s)))
+		return
+	}
+
+	var hex strings.Builder
+	ids, runes := d.glyphs(s)
+	for i, g := range ids {
+		fmt.Fprintf(&hex, "%04X", g)
+		d.used[g] = runes[i]
+	}
+	if bold {
+		fmt.Fprintf(&p.content, "BT 2 Tr %s w /F1 %s Tf %s %s Td <%s> Tj ET\n",
+			num(size*0.03), num(size), num(x), num(PageHeight-y), hex.String())
+		return
+	}
+	fmt.Fprintf(&p.content, "BT 0 Tr /F1 %s Tf %s %s Td <%s> Tj ET\n", num(size), num(x), num(PageHeight-y), hex.String())
+}
+
+// TextRight draws s so that it ends at x.
+func (p *Page) TextRight(x, y, size float64, bold bool, s string) {
+	p.Text(x-p.doc.TextWidth(s, size, bold), y, size, bold, s)
+}
+
+// Line draws a straight line of the given width.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// FillRect fills the rectangle whose top-left corner is (x, y) with a gray level (0 black, 1 white).
+func (p *Page) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(&p.content, "q %s g %s %s %s %s re f Q\n", num(gray), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo renders the document to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	out := &objectWriter{}
+	out.buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	// Object numbers: 1 catalog, 2 page tree, 3 info, then fonts, then a page and its content per page
+	const catalogObj, pagesObj, infoObj = 1, 2, 3
+	next := 4
+	fontObjs := make(map[string]int)
+	if d.font == nil {
+		fontObjs["F1"], fontObjs["F2"] = next, next+1
+		next += 2
+	} else {
+		fontObjs["F1"] = next
+		next += 5 // Type0 font, CID font, descriptor, font file, ToUnicode map
+	}
+	pageObjs := make([]int, len(d.pages))
+	for i := range d.pages {
+		pageObjs[i] = next
+		next += 2
+	}
+	out.offsets = make([]int, next)
+
+	out.object(catalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj))
+	kids := make([]string, len(pageObjs))
+	for i, obj := range pageObjs {
+		kids[i] = fmt.Sprintf("%d 0 R", obj)
+	}
+	out.object(pagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pageObjs)))
+	out.object(infoObj, fmt.Sprintf("<< /Title %s >>", textString(d.title)))
+
+	if d.font == nil {
+		out.object(fontObjs["F1"], "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+		out.object(fontObjs["F2"], "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	} else if err := d.writeFont(out, fontObjs["F1"]); err != nil {
+		return 0, err
+	}
+
+	fontRefs := make([]string, 0, len(fontObjs))
+	for name, obj := range fontObjs {
+		fontRefs = append(fontRefs, fmt.Sprintf("/%s %d 0 R", name, obj))
+	}
+	sort.Strings(fontRefs)
+	for i, page := range d.pages {
+		out.object(pageObjs[i], fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pagesObj, num(PageWidth), num(PageHeight), strings.Join(fontRefs, " "), pageObjs[i]+1))
+		if err := out.stream(pageObjs[i]+1, "", page.content.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+
+	xref := out.buf.Len()
+	fmt.Fprintf(&out.buf, "xref\n0 %d\n0000000000 65535 f \n", next)
+	for obj := 1; obj < next; obj++ {
+		fmt.Fprintf(&out.buf, "%010d 00000 n \n", out.offsets[obj])
+	}
+	fmt.Fprintf(&out.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", next, catalogObj, infoObj, xref)
+
+	n, err := w.Write(out.buf.Bytes())
+	return int64(n), err
+}
+
+// writeFont writes the embedded TrueType font as a Type0 font (objects obj to obj+4), addressed by glyph ID.
+func (d *Document) writeFont(out *objectWriter, obj int) error {
+	f := d.font
+	glyphIDs := make([]int, 0, len(d.used))
+	for g := range d.used {
+		glyphIDs = append(glyphIDs, int(g))
+	}
+	sort.Ints(glyphIDs)
+
+	var widths strings.Builder
+	for _, g := range glyphIDs {
+		fmt.Fprintf(&widths, "%d [%d] ", g, int(f.advance(uint16(g))))
+	}
+
+	out.object(obj, fmt.Sprintf(
+		"<< /Type /Font /Subtype /Type0 /BaseFont /EmbeddedFont /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		obj+1, obj+4))
+	out.object(obj+1, fmt.Sprintf(
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /EmbeddedFont /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW 1000 /W [%s] >>",
+		obj+2, strings.TrimSpace(widths.String())))
+	out.object(obj+2, fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /EmbeddedFont /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+		f.scale(f.ascent), f.scale(f.descent), f.scale(f.capHeight), obj+3))
+	if err := out.stream(obj+3, fmt.Sprintf("/Length1 %d", len(f.data)), f.data); err != nil {
+		return err
+	}
+
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(glyphIDs); start += 100 {
+		end := start + 100
+		if end > len(glyphIDs) {
+			end = len(glyphIDs)
+		}
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, g := range glyphIDs[start:end] {
+			fmt.Fprintf(&cmap, "<%04X> <", g)
+			for _, unit := range utf16.Encode([]rune{d.used[uint16(g)]}) {
+				fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return out.stream(obj+4, "", []byte(cmap.String()))
+}
+
+// objectWriter accumulates numbered objects and records where each starts, for the cross-reference table.
+type objectWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *objectWriter) object(obj int, body string) {
+	w.offsets[obj] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", obj, body)
+}
+
+// stream writes a Flate-compressed stream object; extra is added to its dictionary.
+func (w *objectWriter) stream(obj int, extra string, data []byte) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	w.offsets[obj] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode %s >>\nstream\n", obj, compressed.Len(), extra)
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+// num formats a coordinate with at most two decimals.
+func num(v float64) string {
+	return strconv.FormatFloat(float64(int64(v*100+0.5*sign(v)))/100, 'f', -1, 64)
+}
+
+func sign(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+// escapeString writes a PDF literal string.
+func escapeString(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// textString encodes s as a PDF text string (UTF-16BE with byte order mark).
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteString(">")
+	return b.String()
+}
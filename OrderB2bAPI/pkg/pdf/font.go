@@ -0,0 +1,279 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// helveticaWidths are the advance widths (1/1000 em) of Helvetica and Helvetica-Bold for ASCII 32..126.
+var helveticaWidths = [2][95]uint16{
+	{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// winAnsiExtras maps the characters outside Latin-1 that WinAnsiEncoding has to their codes.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// winAnsi encodes s for the standard Helvetica fonts; characters they cannot show become "?".
+func winAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		b, ok := winAnsiByte(r)
+		if !ok {
+			b = '?'
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// winAnsiByte encodes one character; ok is false when WinAnsi has no code for it.
+func winAnsiByte(r rune) (b byte, ok bool) {
+	switch {
+	case r >= 32 && r <= 126, r >= 160 && r <= 255:
+		return byte(r), true
+	case winAnsiExtras[r] != 0:
+		return winAnsiExtras[r], true
+	}
+	return 0, false
+}
+
+// helveticaWidth is the width of WinAnsi-encoded text in 1/1000 em.
+func helveticaWidth(text []byte, bold bool) float64 {
+	table := &helveticaWidths[0]
+	if bold {
+		table = &helveticaWidths[1]
+	}
+	var w float64
+	for _, b := range text {
+		if b >= 32 && b <= 126 {
+			w += float64(table[b-32])
+		} else {
+			w += 556
+		}
+	}
+	return w
+}
+
+// trueTypeFont is the part of a TrueType font needed to lay out text and embed the font: metrics, advance widths
+// and the Unicode character map.
+type trueTypeFont struct {
+	data       []byte
+	unitsPerEm float64
+	ascent     int16
+	descent    int16
+	capHeight  int16
+	bbox       [4]int16
+	advances   []uint16 // by glyph ID
+
+	cmap       []byte
+	cmapFormat uint16
+	cmapOffset int // start of the subtable used for lookups, within cmap
+}
+
+func parseTrueType(data []byte) (*trueTypeFont, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("font file too short")
+	}
+	switch version := binary.BigEndian.Uint32(data); version {
+	case 0x00010000, 0x74727565: // 1.0 or "true"
+	case 0x4F54544F: // "OTTO"
+		return nil, fmt.Errorf("OpenType CFF fonts are not supported; use a TrueType (glyf) font")
+	default:
+		return nil, fmt.Errorf("not a TrueType font")
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			return nil, fmt.Errorf("truncated table directory")
+		}
+		tag := string(data[rec : rec+4])
+		offset := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("table %q out of bounds", tag)
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("missing %q table", tag)
+		}
+	}
+
+	head, hhea, maxp := tables["head"], tables["hhea"], tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, fmt.Errorf("truncated font header")
+	}
+	f := &trueTypeFont{
+		data:       data,
+		unitsPerEm: float64(binary.BigEndian.Uint16(head[18:])),
+		ascent:     int16(binary.BigEndian.Uint16(hhea[4:])),
+		descent:    int16(binary.BigEndian.Uint16(hhea[6:])),
+	}
+	if f.unitsPerEm == 0 {
+		return nil, fmt.Errorf("invalid unitsPerEm")
+	}
+	for i := range f.bbox {
+		f.bbox[i] = int16(binary.BigEndian.Uint16(head[36+2*i:]))
+	}
+	f.capHeight = f.ascent
+	if os2 := tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		f.capHeight = int16(binary.BigEndian.Uint16(os2[88:]))
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	numHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	hmtx := tables["hmtx"]
+	if numHMetrics == 0 || numHMetrics > numGlyphs || len(hmtx) < 4*numHMetrics {
+		return nil, fmt.Errorf("invalid hmtx table")
+	}
+	f.advances = make([]uint16, numGlyphs)
+	for g := 0; g < numGlyphs; g++ {
+		if g < numHMetrics {
+			f.advances[g] = binary.BigEndian.Uint16(hmtx[4*g:])
+		} else {
+			f.advances[g] = f.advances[numHMetrics-1]
+		}
+	}
+
+	if err := f.selectCmap(tables["cmap"]); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// selectCmap picks a Unicode subtable: full repertoire (format 12) when there is one, otherwise the BMP (format 4).
+func (f *trueTypeFont) selectCmap(cmap []byte) error {
+	if len(cmap) < 4 {
+		return fmt.Errorf("truncated cmap table")
+	}
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	best := -1
+	var bestFormat uint16
+	for i := 0; i < numTables; i++ {
+		rec := 4 + 8*i
+		if rec+8 > len(cmap) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(cmap[rec:])
+		encoding := binary.BigEndian.Uint16(cmap[rec+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[rec+4:]))
+		if offset+2 > len(cmap) {
+			continue
+		}
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		format := binary.BigEndian.Uint16(cmap[offset:])
+		if !unicode || (format != 4 && format != 12) {
+			continue
+		}
+		if best < 0 || (format == 12 && bestFormat != 12) {
+			best, bestFormat = offset, format
+		}
+	}
+	if best < 0 {
+		return fmt.Errorf("no Unicode cmap subtable")
+	}
+	f.cmapFormat = bestFormat
+	f.cmapOffset = best
+	f.cmap = cmap
+	return nil
+}
+
+// glyph returns the glyph ID for r, 0 (.notdef) when the font has none.
+func (f *trueTypeFont) glyph(r rune) uint16 {
+	t := f.cmap[f.cmapOffset:]
+	u16 := func(off int) int {
+		if off < 0 || off+2 > len(t) {
+			return 0
+		}
+		return int(binary.BigEndian.Uint16(t[off:]))
+	}
+	u32 := func(off int) int64 {
+		if off < 0 || off+4 > len(t) {
+			return 0
+		}
+		return int64(binary.BigEndian.Uint32(t[off:]))
+	}
+
+	if f.cmapFormat == 12 {
+		// Groups are sorted by start code
+		lo, hi := 0, int(u32(12))-1
+		for lo <= hi {
+			mid := (lo + hi) / 2
+			group := 16 + 12*mid
+			start, end := u32(group), u32(group+4)
+			switch {
+			case int64(r) < start:
+				hi = mid - 1
+			case int64(r) > end:
+				lo = mid + 1
+			default:
+				return uint16(u32(group+8) + int64(r) - start)
+			}
+		}
+		return 0
+	}
+
+	if r > 0xFFFF {
+		return 0
+	}
+	c := int(r)
+	segCount := u16(6) / 2
+	endCodes := 14
+	startCodes := endCodes + 2*segCount + 2
+	idDeltas := startCodes + 2*segCount
+	idRangeOffsets := idDeltas + 2*segCount
+	for i := 0; i < segCount; i++ {
+		if c > u16(endCodes+2*i) {
+			continue
+		}
+		start := u16(startCodes + 2*i)
+		if c < start {
+			return 0
+		}
+		delta := u16(idDeltas + 2*i)
+		rangeOffset := u16(idRangeOffsets + 2*i)
+		if rangeOffset == 0 {
+			return uint16((c + delta) & 0xFFFF)
+		}
+		g := u16(idRangeOffsets + 2*i + rangeOffset + 2*(c-start))
+		if g == 0 {
+			return 0
+		}
+		return uint16((g + delta) & 0xFFFF)
+	}
+	return 0
+}
+
+// advance is the width of glyph g in 1/1000 em.
+func (f *trueTypeFont) advance(g uint16) float64 {
+	if int(g) >= len(f.advances) {
+		return 0
+	}
+	return float64(f.advances[g]) * 1000 / f.unitsPerEm
+}
+
+// scale converts font units to 1/1000 em.
+func (f *trueTypeFont) scale(v int16) int {
+	return int(float64(v) * 1000 / f.unitsPerEm)
+}
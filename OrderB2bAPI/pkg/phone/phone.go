@@ -0,0 +1,105 @@
+// Package phone parses Jordanian and international phone numbers to E.164 (e.g. +962791234567), the form
+// customer phones are stored, compared and sent to Shopify in.
+package phone
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// JordanCountryCode is Jordan's calling code.
+const JordanCountryCode = "962"
+
+var (
+	// Jordanian national significant numbers: mobiles 77/78/79 (Orange, Umniah, Zain) and landlines with area
+	// codes 2 (north), 3 (south), 5 (centre) and 6 (Amman)
+	jordanMobile   = regexp.MustCompile(`^7[789]\d{7}$`)
+	jordanLandline = regexp.MustCompile(`^[2356]\d{7}$`)
+	// Other countries: a calling code and subscriber number of 8 to 15 digits in all
+	internationalDigits = regexp.MustCompile(`^[1-9]\d{7,14}$`)
+)
+
+// separators are the characters people put between digits.
+var separators = strings.NewReplacer(" ", "", "\u00a0", "", "-", "", ".", "", "(", "", ")", "", "/", "")
+
+// Parse returns the number in E.164 form. Accepted forms are international ones (+962 79 123 4567,
+// 00962791234567, 962791234567, with or without the trunk 0 after the calling code) and Jordanian national ones
+// (0791234567, 791234567, 065123456). Arabic-Indic digits are accepted. Jordanian numbers must have a mobile or
+// landline prefix; national numbers of other countries cannot be told apart and are refused.
+func Parse(raw string) (string, error) {
+	s := separators.Replace(toASCIIDigits(strings.TrimSpace(raw)))
+	if s == "" {
+		return "", fmt.Errorf("phone number is empty")
+	}
+
+	international := false
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s, international = s[1:], true
+	case strings.HasPrefix(s, "00"):
+		s, international = s[2:], true
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("invalid phone number %q", raw)
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(s, JordanCountryCode); ok && (international || len(s) >= 11) {
+		return parseJordan(raw, strings.TrimPrefix(rest, "0"))
+	}
+	if international {
+		if !internationalDigits.MatchString(s) {
+			return "", fmt.Errorf("invalid phone number %q", raw)
+		}
+		return "+" + s, nil
+	}
+	// National: Jordanian, with or without the trunk 0
+	return parseJordan(raw, strings.TrimPrefix(s, "0"))
+}
+
+// parseJordan checks a Jordanian national significant number (without trunk 0).
+func parseJordan(raw, national string) (string, error) {
+	if !jordanMobile.MatchString(national) && !jordanLandline.MatchString(national) {
+		return "", fmt.Errorf("invalid Jordanian phone number %q", raw)
+	}
+	return "+" + JordanCountryCode + national, nil
+}
+
+// IsMobile reports whether an E.164 number is a mobile number. Jordanian numbers must have a mobile prefix (77,
+// 78, 79); numbers of other countries are taken to be mobiles.
+func IsMobile(e164 string) bool {
+	if national, ok := strings.CutPrefix(e164, "+"+JordanCountryCode); ok {
+		return jordanMobile.MatchString(national)
+	}
+	return strings.HasPrefix(e164, "+")
+}
+
+// Equal reports whether a and b are the same number. Numbers that do not parse are never equal.
+func Equal(a, b string) bool {
+	na, err := Parse(a)
+	if err != nil {
+		return false
+	}
+	nb, err := Parse(b)
+	return err == nil && na == nb
+}
+
+// Digits returns an E.164 number without the leading +, e.g. for use in identifiers.
+func Digits(e164 string) string {
+	return strings.TrimPrefix(e164, "+")
+}
+
+// toASCIIDigits replaces Arabic-Indic and Eastern Arabic-Indic (Persian) digits with ASCII ones.
+func toASCIIDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		}
+		return r
+	}, s)
+}